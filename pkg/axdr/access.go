@@ -0,0 +1,194 @@
+package axdr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AsUint64 returns the value of an unsigned, long-unsigned, double-long-unsigned,
+// long64-unsigned or enum, widened to uint64.
+func (d DlmsData) AsUint64() (uint64, error) {
+	switch v := d.Value.(type) {
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	default:
+		return 0, d.errAs("an unsigned integer")
+	}
+}
+
+// AsInt64 returns the value of an integer of any size, widened to int64. A
+// long64-unsigned is only returned if it fits in int64.
+func (d DlmsData) AsInt64() (int64, error) {
+	switch v := d.Value.(type) {
+	case int8:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d of tag %v overflows int64", v, d.Tag)
+		}
+		return int64(v), nil
+	default:
+		u, err := d.AsUint64()
+		if err != nil {
+			return 0, d.errAs("an integer")
+		}
+		return int64(u), nil
+	}
+}
+
+// AsFloat returns the value of a floating-point or of an integer as float64.
+func (d DlmsData) AsFloat() (float64, error) {
+	switch v := d.Value.(type) {
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case uint64:
+		return float64(v), nil
+	default:
+		i, err := d.AsInt64()
+		if err != nil {
+			return 0, d.errAs("a number")
+		}
+		return float64(i), nil
+	}
+}
+
+// AsBytes returns the octets of an octet-string (including an Obis code or a
+// date-time), the characters of a visible-string or utf8-string and the bits of
+// a bit-string padded with trailing zeros.
+func (d DlmsData) AsBytes() ([]byte, error) {
+	switch v := d.Value.(type) {
+	case string:
+		switch d.Tag {
+		case TagOctetString:
+			out, err := EncodeOctetString(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid octet-string %q: %w", v, err)
+			}
+			return out, nil
+		case TagVisibleString, TagUTF8String:
+			return []byte(v), nil
+		case TagBitString:
+			return EncodeBitString(v)
+		}
+	case time.Time:
+		if d.Tag == TagOctetString {
+			return EncodeDateTime(v)
+		}
+	}
+
+	return nil, d.errAs("bytes")
+}
+
+// AsTime returns the time of a date-time, date or time, or of an octet-string
+// holding the encoding of one of them (12, 5 or 4 octets).
+func (d DlmsData) AsTime() (time.Time, error) {
+	switch d.Tag {
+	case TagDateTime, TagDate, TagTime:
+		t, err := d.timeValue()
+		if err != nil {
+			return time.Time{}, d.errAs("a time")
+		}
+		return t, nil
+	case TagOctetString:
+		if t, ok := d.Value.(time.Time); ok {
+			return t, nil
+		}
+
+		src, err := d.AsBytes()
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		var t time.Time
+		switch len(src) {
+		case 12:
+			_, t, err = DecodeDateTime(&src)
+		case 5:
+			_, t, err = DecodeDate(&src)
+		case 4:
+			_, t, err = DecodeTime(&src)
+		default:
+			err = fmt.Errorf("unexpected length %d", len(src))
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time in octet-string: %w", err)
+		}
+		return t, nil
+	}
+
+	return time.Time{}, d.errAs("a time")
+}
+
+// Elements returns the elements of an array, structure or compact-array.
+func (d *DlmsData) Elements() ([]*DlmsData, error) {
+	switch d.Tag {
+	case TagArray, TagStructure, TagCompactArray:
+		if elements, ok := d.Value.([]*DlmsData); ok {
+			return elements, nil
+		}
+	}
+
+	return nil, d.errAs("an array or structure")
+}
+
+// Index returns the element i of an array, structure or compact-array.
+func (d *DlmsData) Index(i int) (*DlmsData, error) {
+	elements, err := d.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	if i < 0 || i >= len(elements) {
+		return nil, fmt.Errorf("index %d out of range of %d elements", i, len(elements))
+	}
+
+	if elements[i] == nil {
+		return nil, fmt.Errorf("element %d is nil", i)
+	}
+
+	return elements[i], nil
+}
+
+// Get returns the element at the path of indexes separated by dots, e.g. "2.0.1"
+// is the element 1 of the element 0 of the element 2. An empty path returns d.
+func (d *DlmsData) Get(path string) (*DlmsData, error) {
+	if path == "" {
+		return d, nil
+	}
+
+	current := d
+	steps := strings.Split(path, ".")
+	for n, step := range steps {
+		i, err := strconv.Atoi(step)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %q in path %q", step, path)
+		}
+
+		if current, err = current.Index(i); err != nil {
+			return nil, fmt.Errorf("path %q: %w", strings.Join(steps[:n+1], "."), err)
+		}
+	}
+
+	return current, nil
+}
+
+func (d DlmsData) errAs(want string) error {
+	return fmt.Errorf("cannot get %T with tag %v as %s", d.Value, d.Tag, want)
+}
@@ -0,0 +1,163 @@
+package axdr
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDlmsData_AsNumber(t *testing.T) {
+	tables := []struct {
+		name string
+		data *DlmsData
+		u    uint64
+		uErr bool
+		i    int64
+		iErr bool
+		f    float64
+		fErr bool
+	}{
+		{"unsigned", CreateAxdrUnsigned(200), 200, false, 200, false, 200, false},
+		{"long-unsigned", CreateAxdrLongUnsigned(60000), 60000, false, 60000, false, 60000, false},
+		{"double-long-unsigned", CreateAxdrDoubleLongUnsigned(70000), 70000, false, 70000, false, 70000, false},
+		{"long64-unsigned", CreateAxdrLong64Unsigned(1 << 40), 1 << 40, false, 1 << 40, false, 1 << 40, false},
+		{"long64-unsigned overflow", CreateAxdrLong64Unsigned(math.MaxUint64), math.MaxUint64, false, 0, true, math.MaxUint64, false},
+		{"enum", CreateAxdrEnum(3), 3, false, 3, false, 3, false},
+		{"integer", CreateAxdrInteger(-1), 0, true, -1, false, -1, false},
+		{"long", CreateAxdrLong(-300), 0, true, -300, false, -300, false},
+		{"double-long", CreateAxdrDoubleLong(-70000), 0, true, -70000, false, -70000, false},
+		{"long64", CreateAxdrLong64(-1 << 40), 0, true, -1 << 40, false, -1 << 40, false},
+		{"float32", CreateAxdrFloat32(1.5), 0, true, 0, true, 1.5, false},
+		{"float64", CreateAxdrFloat64(-2.25), 0, true, 0, true, -2.25, false},
+		{"boolean", CreateAxdrBoolean(true), 0, true, 0, true, 0, true},
+		{"octet-string", CreateAxdrOctetString("01"), 0, true, 0, true, 0, true},
+	}
+
+	for _, table := range tables {
+		t.Run(table.name, func(t *testing.T) {
+			u, err := table.data.AsUint64()
+			if table.uErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, table.u, u)
+			}
+
+			i, err := table.data.AsInt64()
+			if table.iErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, table.i, i)
+			}
+
+			f, err := table.data.AsFloat()
+			if table.fErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, table.f, f)
+			}
+		})
+	}
+}
+
+func TestDlmsData_AsBytes(t *testing.T) {
+	tables := []struct {
+		data *DlmsData
+		want []byte
+	}{
+		{CreateAxdrOctetString("0102ff"), []byte{0x01, 0x02, 0xFF}},
+		{CreateAxdrOctetString("1.0.99.1.0.255"), []byte{1, 0, 99, 1, 0, 255}},
+		{CreateAxdrOctetString(time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC)), decodeHexString("07E7010F0700000000000000")},
+		{CreateAxdrVisibleString("abc"), []byte("abc")},
+		{CreateAxdrUTF8String("é"), []byte("é")},
+		{CreateAxdrBitString("101"), []byte{0xA0}},
+	}
+
+	for _, table := range tables {
+		got, err := table.data.AsBytes()
+		assert.NoError(t, err)
+		assert.Equal(t, table.want, got)
+	}
+
+	_, err := CreateAxdrOctetString("0g").AsBytes()
+	assert.Error(t, err)
+
+	_, err = CreateAxdrUnsigned(1).AsBytes()
+	assert.Error(t, err)
+}
+
+func TestDlmsData_AsTime(t *testing.T) {
+	dt := time.Date(2023, time.January, 15, 10, 20, 30, 0, time.UTC)
+
+	tables := []struct {
+		data *DlmsData
+		want time.Time
+	}{
+		{CreateAxdrDateTime(dt), dt},
+		{&DlmsData{Tag: TagDateTime, Value: "2023-01-15 10:20:30"}, dt},
+		{CreateAxdrOctetString(dt), dt},
+		{CreateAxdrOctetString("07E7010F070A141E00000000"), dt},
+		{CreateAxdrOctetString("07E7010F07"), time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, table := range tables {
+		got, err := table.data.AsTime()
+		assert.NoError(t, err)
+		assert.True(t, table.want.Equal(got), "%v != %v", table.want, got)
+	}
+
+	for _, data := range []*DlmsData{
+		CreateAxdrOctetString("0102"),
+		{Tag: TagDate, Value: "15/01/2023"},
+		CreateAxdrDoubleLongUnsigned(1),
+	} {
+		_, err := data.AsTime()
+		assert.Error(t, err)
+	}
+}
+
+func TestDlmsData_Get(t *testing.T) {
+	data := CreateAxdrArray([]*DlmsData{
+		CreateAxdrStructure([]*DlmsData{CreateAxdrUnsigned(1)}),
+		CreateAxdrStructure([]*DlmsData{CreateAxdrUnsigned(2), CreateAxdrNull()}),
+		CreateAxdrStructure([]*DlmsData{
+			CreateAxdrArray([]*DlmsData{
+				CreateAxdrStructure([]*DlmsData{CreateAxdrOctetString("0.0.1.0.0.255"), CreateAxdrLongUnsigned(8)}),
+			}),
+		}),
+	})
+
+	got, err := data.Get("2.0.0.1")
+	require.NoError(t, err)
+	value, err := got.AsUint64()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), value)
+
+	got, err = data.Get("")
+	assert.NoError(t, err)
+	assert.Same(t, data, got)
+
+	elements, err := data.Elements()
+	assert.NoError(t, err)
+	assert.Len(t, elements, 3)
+
+	got, err = data.Index(1)
+	assert.NoError(t, err)
+	assert.Same(t, elements[1], got)
+
+	for _, path := range []string{"3", "-1", "0.0.0", "2.0.1", "x", "1.", ".1"} {
+		_, err = data.Get(path)
+		assert.Error(t, err, path)
+	}
+
+	_, err = data.Get("2.0.1")
+	assert.EqualError(t, err, `path "2.0.1": index 1 out of range of 1 elements`)
+
+	_, err = CreateAxdrUnsigned(1).Elements()
+	assert.Error(t, err)
+}
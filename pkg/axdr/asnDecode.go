@@ -3,96 +3,233 @@
 package axdr
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+// AsnDecode returns the text notation of the data, which AsnEncode parses back to
+// data giving the same text. Octet-strings holding a time are written as the hex
+// of their encoding.
 func AsnDecode(value *DlmsData) (data string, err error) {
 	if value == nil {
 		return "", fmt.Errorf("value to encode cannot be nil")
 	}
 
+	var sb strings.Builder
+	if err := writeAsn(&sb, value); err != nil {
+		return "", fmt.Errorf(nonEncodableError+"%w", err)
+	}
+
+	return sb.String(), nil
+}
+
+// writeAsn writes the text notation of value to sb.
+func writeAsn(sb *strings.Builder, value *DlmsData) error {
+	if value == nil {
+		return fmt.Errorf("value cannot be nil")
+	}
+
+	errDataType := fmt.Errorf("unexpected value of type %T with tag %v", value.Value, value.Tag)
+
+	var name, text string
 	switch value.Tag {
 	case TagNull:
-		data = strNull + "{}"
-	case TagArray:
-		data = strArray + "{"
-		strArray := value.Value.([]*DlmsData)
-		for _, str := range strArray {
-			tmp, err := AsnDecode(str)
-			if err != nil {
-				return "", fmt.Errorf(nonEncodableError+"%w", err)
-			}
+		name = strNull
+	case TagDontCare:
+		name = strDontCare
+	case TagArray, TagStructure, TagCompactArray:
+		elements, ok := value.Value.([]*DlmsData)
+		if !ok {
+			return errDataType
+		}
 
-			data += tmp
+		switch value.Tag {
+		case TagArray:
+			sb.WriteString(strArray)
+		case TagStructure:
+			sb.WriteString(strStructure)
+		default:
+			sb.WriteString(strCompactArray)
 		}
-		data += "}"
-	case TagStructure:
-		data = strStructure + "{"
-		strStructure := value.Value.([]*DlmsData)
-		for _, str := range strStructure {
-			tmp, err := AsnDecode(str)
-			if err != nil {
-				return "", fmt.Errorf(nonEncodableError+"%w", err)
-			}
 
-			data += tmp
+		sb.WriteRune(openBracket)
+		for _, element := range elements {
+			if err := writeAsn(sb, element); err != nil {
+				return err
+			}
 		}
-		data += "}"
+		sb.WriteRune(closeBracket)
+
+		return nil
 	case TagBoolean:
-		data = fmt.Sprintf(strBoolean+"{%s}", strconv.FormatBool(value.Value.(bool)))
+		v, ok := value.Value.(bool)
+		if !ok {
+			return errDataType
+		}
+		name, text = strBoolean, strconv.FormatBool(v)
 	case TagBitString:
-		data = fmt.Sprintf(strBitString+"{%s}", value.Value.(string))
+		v, ok := value.Value.(string)
+		if !ok {
+			return errDataType
+		}
+
+		if _, err := EncodeBitString(v); err != nil {
+			return err
+		}
+		name, text = strBitString, asnEscape(v)
 	case TagDoubleLong:
-		data = fmt.Sprintf(strDoubleLong+"{%d}", (value.Value.(int32)))
+		v, ok := value.Value.(int32)
+		if !ok {
+			return errDataType
+		}
+		name, text = strDoubleLong, strconv.FormatInt(int64(v), 10)
 	case TagDoubleLongUnsigned:
-		data = fmt.Sprintf(strDoubleLongUnsigned+"{%d}", (value.Value.(uint32)))
+		v, ok := value.Value.(uint32)
+		if !ok {
+			return errDataType
+		}
+		name, text = strDoubleLongUnsigned, strconv.FormatUint(uint64(v), 10)
 	case TagFloatingPoint:
-		data = fmt.Sprintf(strFloatingPoint+"{%g}", (value.Value.(float32)))
+		v, ok := value.Value.(float32)
+		if !ok {
+			return errDataType
+		}
+		name, text = strFloatingPoint, fmt.Sprintf("%g", v)
 	case TagOctetString:
-		data = fmt.Sprintf(strOctetString+"{%s}", value.Value.(string))
+		switch v := value.Value.(type) {
+		case string:
+			text = asnEscape(v)
+		case time.Time:
+			raw, err := EncodeDateTime(v)
+			if err != nil {
+				return err
+			}
+			text = hex.EncodeToString(raw)
+		default:
+			return errDataType
+		}
+		name = strOctetString
 	case TagVisibleString:
-		data = fmt.Sprintf(strVisibleString+"{%s}", value.Value.(string))
+		v, ok := value.Value.(string)
+		if !ok {
+			return errDataType
+		}
+
+		if _, err := EncodeVisibleString(v); err != nil {
+			return err
+		}
+		name, text = strVisibleString, asnEscape(v)
+	case TagUTF8String:
+		v, ok := value.Value.(string)
+		if !ok {
+			return errDataType
+		}
+
+		if !utf8.ValidString(v) {
+			return fmt.Errorf("invalid UTF-8 string")
+		}
+		name, text = strUTF8String, asnEscape(v)
 	case TagBCD:
-		data = fmt.Sprintf(strBCD+"{%d}", value.Value.(int8))
+		v, ok := value.Value.(int8)
+		if !ok {
+			return errDataType
+		}
+		name, text = strBCD, strconv.FormatInt(int64(v), 10)
 	case TagInteger:
-		data = fmt.Sprintf(strInteger+"{%d}", value.Value.(int8))
+		v, ok := value.Value.(int8)
+		if !ok {
+			return errDataType
+		}
+		name, text = strInteger, strconv.FormatInt(int64(v), 10)
 	case TagLong:
-		data = fmt.Sprintf(strLong+"{%d}", value.Value.(int16))
+		v, ok := value.Value.(int16)
+		if !ok {
+			return errDataType
+		}
+		name, text = strLong, strconv.FormatInt(int64(v), 10)
 	case TagUnsigned:
-		data = fmt.Sprintf(strUnsigned+"{%d}", value.Value.(uint8))
-	case TagCompactArray:
-		data = strCompactArray + "{"
-		strStructure := value.Value.([]*DlmsData)
-		for _, str := range strStructure {
-			tmp, err := AsnDecode(str)
-			if err != nil {
-				return "", fmt.Errorf(nonEncodableError+"%w", err)
-			}
-
-			data += tmp
+		v, ok := value.Value.(uint8)
+		if !ok {
+			return errDataType
 		}
-		data += "}"
+		name, text = strUnsigned, strconv.FormatUint(uint64(v), 10)
 	case TagLongUnsigned:
-		data = fmt.Sprintf(strLongUnsigned+"{%d}", value.Value.(uint16))
+		v, ok := value.Value.(uint16)
+		if !ok {
+			return errDataType
+		}
+		name, text = strLongUnsigned, strconv.FormatUint(uint64(v), 10)
 	case TagLong64:
-		data = fmt.Sprintf(strLong64+"{%d}", value.Value.(int64))
+		v, ok := value.Value.(int64)
+		if !ok {
+			return errDataType
+		}
+		name, text = strLong64, strconv.FormatInt(v, 10)
 	case TagLong64Unsigned:
-		data = fmt.Sprintf(strLong64Unsigned+"{%d}", value.Value.(uint64))
+		v, ok := value.Value.(uint64)
+		if !ok {
+			return errDataType
+		}
+		name, text = strLong64Unsigned, strconv.FormatUint(v, 10)
 	case TagEnum:
-		data = fmt.Sprintf(strEnum+"{%d}", value.Value.(uint8))
+		v, ok := value.Value.(uint8)
+		if !ok {
+			return errDataType
+		}
+		name, text = strEnum, strconv.FormatUint(uint64(v), 10)
 	case TagFloat32:
-		data = fmt.Sprintf(strFloat32+"{%g}", value.Value.(float32))
+		v, ok := value.Value.(float32)
+		if !ok {
+			return errDataType
+		}
+		name, text = strFloat32, fmt.Sprintf("%g", v)
 	case TagFloat64:
-		data = fmt.Sprintf(strFloat64+"{%g}", value.Value.(float64))
-	case TagDateTime:
-		data = fmt.Sprintf(strDateTime+"{%s}", value.Value.(time.Time).Format(dateTimeLayout))
-	case TagDate:
-		data = fmt.Sprintf(strDate+"{%s}", value.Value.(time.Time).Format(dateLayout))
-	case TagTime:
-		data = fmt.Sprintf(strTime+"{%s}", value.Value.(time.Time).Format(timeLayout))
+		v, ok := value.Value.(float64)
+		if !ok {
+			return errDataType
+		}
+		name, text = strFloat64, fmt.Sprintf("%g", v)
+	case TagDateTime, TagDate, TagTime:
+		t, err := value.timeValue()
+		if err != nil {
+			return err
+		}
+
+		if t.Year() < 0 || t.Year() > 9999 {
+			return fmt.Errorf("year %d out of range", t.Year())
+		}
+
+		switch value.Tag {
+		case TagDateTime:
+			name, text = strDateTime, t.Format(dateTimeLayout+fractionLayout)
+			if _, offset := t.Zone(); offset != 0 {
+				if offset%60 != 0 || offset <= -24*3600 || offset >= 24*3600 {
+					return fmt.Errorf("deviation of %d seconds cannot be written", offset)
+				}
+				text += t.Format(zoneLayout)
+			}
+		case TagDate:
+			name, text = strDate, t.Format(dateLayout)
+		default:
+			name, text = strTime, t.Format(timeLayout+fractionLayout)
+		}
+	default:
+		return fmt.Errorf("unsupported tag %v", value.Tag)
 	}
 
-	return data, nil
+	sb.WriteString(name)
+	sb.WriteRune(openBracket)
+	sb.WriteString(text)
+	sb.WriteRune(closeBracket)
+
+	return nil
+}
+
+// asnEscape escapes the braces and backslashes of a string
+func asnEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `{`, `\{`, `}`, `\}`).Replace(s)
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAsnDecode(t *testing.T) {
@@ -182,6 +183,51 @@ func TestAsnDecode(t *testing.T) {
 			want:    "time{15:04:05}",
 			wantErr: false,
 		},
+		{
+			name:    "utf8_string",
+			v:       CreateAxdrUTF8String("año"),
+			want:    "utf8_string{año}",
+			wantErr: false,
+		},
+		{
+			name:    "escaped string",
+			v:       CreateAxdrVisibleString(`a{b}c\`),
+			want:    `visible_string{a\{b\}c\\}`,
+			wantErr: false,
+		},
+		{
+			name:    "dont_care",
+			v:       &DlmsData{Tag: TagDontCare},
+			want:    "dont_care{}",
+			wantErr: false,
+		},
+		{
+			name:    "octet_string with time",
+			v:       &DlmsData{Tag: TagOctetString, Value: time1},
+			want:    "octet_string{07e00401050a000000000000}",
+			wantErr: false,
+		},
+		{
+			name:    "date_time with deviation",
+			v:       CreateAxdrDateTime(time.Date(2016, time.April, 1, 10, 0, 0, 500000000, time.FixedZone("", -3600))),
+			want:    "date_time{2016/04/01 10:00:00.5 -01:00}",
+			wantErr: false,
+		},
+		{
+			name:    "wrong value",
+			v:       &DlmsData{Tag: TagUnsigned, Value: 1},
+			wantErr: true,
+		},
+		{
+			name:    "wrong element",
+			v:       CreateAxdrArray([]*DlmsData{{Tag: TagLong, Value: "1"}}),
+			wantErr: true,
+		},
+		{
+			name:    "wrong tag",
+			v:       &DlmsData{Tag: 200, Value: 1},
+			wantErr: true,
+		},
 		{
 			name:    "no exist",
 			v:       nil,
@@ -213,3 +259,30 @@ func TestAsnDecode(t *testing.T) {
 		})
 	}
 }
+
+func FuzzAsnDecode(f *testing.F) {
+	f.Add([]byte{0x02, 0x02, 0x12, 0x00, 0x08, 0x09, 0x06, 0x00, 0x00, 0x01, 0x00, 0x00, 0xff})
+	f.Add([]byte{0x01, 0x02, 0x0a, 0x02, 0x7b, 0x7d, 0x0c, 0x02, 0xc3, 0xb1})
+	f.Add([]byte{0x19, 0x07, 0xe0, 0x04, 0x01, 0x05, 0x0a, 0x00, 0x00, 0x32, 0xff, 0xc4, 0x00})
+	f.Add([]byte{0x13, 0x02, 0x02, 0x11, 0x10, 0x00, 0x03, 0x01, 0xff, 0x00, 0x02})
+
+	f.Fuzz(func(t *testing.T, src []byte) {
+		data, err := NewDataDecoder(&src).Decode(&src)
+		if err != nil {
+			return
+		}
+
+		text, err := AsnDecode(&data)
+		if err != nil {
+			return
+		}
+
+		// The text of any data must be parsed back to data with the same text
+		parsed, err := AsnEncode(text)
+		require.NoError(t, err, text)
+
+		got, err := AsnDecode(parsed)
+		require.NoError(t, err)
+		assert.Equal(t, text, got)
+	})
+}
@@ -5,10 +5,10 @@ package axdr
 import (
 	"encoding/hex"
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -22,6 +22,7 @@ const (
 	strFloatingPoint      = "floating_point"
 	strOctetString        = "octet_string"
 	strVisibleString      = "visible_string"
+	strUTF8String         = "utf8_string"
 	strBCD                = "bcd"
 	strInteger            = "integer"
 	strLong               = "long"
@@ -43,228 +44,454 @@ const (
 const (
 	openBracket       = '{'
 	closeBracket      = '}'
+	escapeChar        = '\\'
 	nonEncodableError = "data is non-encodable: "
-	filter            = `^([^\{]+)\{(.*?)\}$`
 	dateTimeLayout    = "2006/01/02 15:04:05"
 	dateLayout        = "2006/01/02"
 	timeLayout        = "15:04:05"
+	fractionLayout    = ".999999999"
+	zoneLayout        = " -07:00"
 )
 
+// AsnSyntaxError is the error returned by AsnEncode for text that is not valid,
+// with the position where the problem was found.
+type AsnSyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+	Err    error
+}
+
+func (e *AsnSyntaxError) Error() string {
+	return fmt.Sprintf(nonEncodableError+"line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+func (e *AsnSyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// AsnEncode parses the text notation of DLMS data given by AsnDecode. Each value
+// is written as its type followed by its contents in braces, e.g. unsigned{5};
+// arrays, structures and compact arrays hold their elements, which can be
+// separated by white space, e.g.
+//
+//	structure{
+//	  long_unsigned{8}
+//	  octet_string{0000010000ff}
+//	}
+//
+// Braces and backslashes inside strings must be escaped with a backslash. Dates are
+// written as 2006/01/02, times as 15:04:05 and date-times as 2006/01/02 15:04:05,
+// both with optional fractions of second and date-times with an optional deviation
+// as -07:00. raw{} holds the hex of encoded data. Errors are *AsnSyntaxError.
 func AsnEncode(value string) (data *DlmsData, err error) {
-	re := regexp.MustCompile(filter)
-	valueSplit := re.FindStringSubmatch(value)
+	s := newAsnScanner(value)
+
+	data, err = s.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	s.skipSpace()
+	if !s.eof() {
+		return nil, s.errorf(s.position(), nil, "unexpected %q after the value", s.peek())
+	}
+
+	return data, nil
+}
+
+// asnPosition is the position of a character in the text, starting at 1.
+type asnPosition struct {
+	line   int
+	column int
+}
+
+// asnScanner reads the tokens of the text notation: the names of the types, the
+// braces and the contents of the values.
+type asnScanner struct {
+	src    string
+	offset int
+	pos    asnPosition
+}
+
+func newAsnScanner(src string) *asnScanner {
+	return &asnScanner{src: src, pos: asnPosition{line: 1, column: 1}}
+}
+
+func (s *asnScanner) position() asnPosition {
+	return s.pos
+}
+
+func (s *asnScanner) errorf(pos asnPosition, err error, format string, a ...interface{}) error {
+	return &AsnSyntaxError{Line: pos.line, Column: pos.column, Msg: fmt.Sprintf(format, a...), Err: err}
+}
+
+func (s *asnScanner) eof() bool {
+	return s.offset >= len(s.src)
+}
+
+// peek returns the next character without reading it, or utf8.RuneError at the
+// end of the text.
+func (s *asnScanner) peek() rune {
+	if s.eof() {
+		return utf8.RuneError
+	}
+
+	r, _ := utf8.DecodeRuneInString(s.src[s.offset:])
+
+	return r
+}
+
+func (s *asnScanner) next() rune {
+	r, size := utf8.DecodeRuneInString(s.src[s.offset:])
+	s.offset += size
+
+	if r == '\n' {
+		s.pos.line++
+		s.pos.column = 1
+	} else {
+		s.pos.column++
+	}
 
-	if len(valueSplit) != 3 {
-		return nil, fmt.Errorf(nonEncodableError+"%s", value)
+	return r
+}
+
+func (s *asnScanner) skipSpace() {
+	for !s.eof() && strings.ContainsRune(" \t\r\n", s.peek()) {
+		s.next()
+	}
+}
+
+// unexpected returns the error for the next character, which is not the one
+// expected.
+func (s *asnScanner) unexpected(expected string) error {
+	if s.eof() {
+		return s.errorf(s.position(), nil, "unexpected end of text, expected %s", expected)
+	}
+
+	return s.errorf(s.position(), nil, "unexpected %q, expected %s", s.peek(), expected)
+}
+
+func (s *asnScanner) expect(r rune) error {
+	if s.eof() || s.peek() != r {
+		return s.unexpected(strconv.QuoteRune(r))
+	}
+
+	s.next()
+
+	return nil
+}
+
+// name reads the name of a type, made of lowercase letters, digits and
+// underscores.
+func (s *asnScanner) name() string {
+	start := s.offset
+	for !s.eof() {
+		r := s.peek()
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			break
+		}
+		s.next()
+	}
+
+	return s.src[start:s.offset]
+}
+
+// text reads the contents of a value up to its closing brace, which is not read,
+// removing the escapes.
+func (s *asnScanner) text() (string, error) {
+	var sb strings.Builder
+	for {
+		if s.eof() {
+			return "", s.unexpected(strconv.QuoteRune(closeBracket))
+		}
+
+		switch r := s.peek(); r {
+		case closeBracket:
+			return sb.String(), nil
+		case openBracket:
+			return "", s.errorf(s.position(), nil, "unescaped %q in value", r)
+		case escapeChar:
+			s.next()
+			if s.eof() {
+				return "", s.unexpected("escaped character")
+			}
+			sb.WriteRune(s.next())
+		default:
+			sb.WriteRune(s.next())
+		}
+	}
+}
+
+// parseValue reads a value with its type and contents.
+func (s *asnScanner) parseValue() (*DlmsData, error) {
+	s.skipSpace()
+
+	start := s.position()
+	name := s.name()
+	if name == "" {
+		return nil, s.unexpected("type name")
+	}
+
+	switch name {
+	case strArray, strStructure, strCompactArray, strNull, strDontCare:
+	default:
+		if !asnScalarTypes()[name] {
+			return nil, s.errorf(start, nil, "unsupported type %q", name)
+		}
 	}
 
-	switch valueSplit[1] {
-	case strNull:
+	s.skipSpace()
+	if err := s.expect(openBracket); err != nil {
+		return nil, err
+	}
+
+	var data *DlmsData
+	switch name {
+	case strArray, strStructure, strCompactArray:
+		elements, err := s.parseElements()
+		if err != nil {
+			return nil, err
+		}
+
+		switch name {
+		case strArray:
+			data = CreateAxdrArray(elements)
+		case strStructure:
+			data = CreateAxdrStructure(elements)
+		default:
+			data = CreateAxdrCompactArray(elements)
+		}
+	case strNull, strDontCare:
+		s.skipSpace()
+
 		data = CreateAxdrNull()
-	case strArray:
-		axdrArray, err := setArrayStructure(valueSplit[2])
+		if name == strDontCare {
+			data.Tag = TagDontCare
+		}
+	default:
+		contents := s.position()
+		text, err := s.text()
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
-		data = CreateAxdrArray(axdrArray)
-	case strStructure:
-		axdrStruct, err := setArrayStructure(valueSplit[2])
+
+		data, err = asnScalar(name, text)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, s.errorf(contents, err, "invalid %s %q: %v", name, text, err)
 		}
+	}
 
-		data = CreateAxdrStructure(axdrStruct)
-	case strBoolean:
-		tmp, err := strconv.ParseBool(valueSplit[2])
+	if err := s.expect(closeBracket); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// parseElements reads the elements of an array, structure or compact array up
+// to its closing brace, which is not read.
+func (s *asnScanner) parseElements() ([]*DlmsData, error) {
+	elements := make([]*DlmsData, 0)
+	for {
+		s.skipSpace()
+		if s.eof() || s.peek() == closeBracket {
+			return elements, nil
+		}
+
+		element, err := s.parseValue()
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
+		elements = append(elements, element)
+	}
+}
 
+// asnScalarTypes are the names of the types whose contents are a single value
+func asnScalarTypes() map[string]bool {
+	return map[string]bool{
+		strBoolean:            true,
+		strBitString:          true,
+		strDoubleLong:         true,
+		strDoubleLongUnsigned: true,
+		strFloatingPoint:      true,
+		strOctetString:        true,
+		strVisibleString:      true,
+		strUTF8String:         true,
+		strBCD:                true,
+		strInteger:            true,
+		strLong:               true,
+		strUnsigned:           true,
+		strLongUnsigned:       true,
+		strLong64:             true,
+		strLong64Unsigned:     true,
+		strEnum:               true,
+		strFloat32:            true,
+		strFloat64:            true,
+		strDateTime:           true,
+		strDate:               true,
+		strTime:               true,
+		strRaw:                true,
+	}
+}
+
+// asnScalar returns the data of the type with the name and the contents, without
+// escapes.
+func asnScalar(name string, value string) (data *DlmsData, err error) {
+	switch name {
+	case strBoolean:
+		tmp, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, err
+		}
 		data = CreateAxdrBoolean(tmp)
 	case strBitString:
-		data = CreateAxdrBitString(valueSplit[2])
+		if _, err := EncodeBitString(value); err != nil {
+			return nil, err
+		}
+		data = CreateAxdrBitString(value)
 	case strDoubleLong:
-		tmp, err := strconv.ParseInt(valueSplit[2], 10, 32)
+		tmp, err := strconv.ParseInt(value, 10, 32)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrDoubleLong(int32(tmp))
 	case strDoubleLongUnsigned:
-		tmp, err := strconv.ParseUint(valueSplit[2], 10, 32)
+		tmp, err := strconv.ParseUint(value, 10, 32)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrDoubleLongUnsigned(uint32(tmp))
 	case strFloatingPoint:
-		tmp, err := strconv.ParseFloat(valueSplit[2], 32)
+		tmp, err := strconv.ParseFloat(value, 32)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrFloatingPoint(float32(tmp))
 	case strOctetString:
-		data = CreateAxdrOctetString(valueSplit[2])
+		// Kept as given, which can be hex or an OBIS code with dots
+		data = CreateAxdrOctetString(value)
 	case strVisibleString:
-		data = CreateAxdrVisibleString(valueSplit[2])
+		if _, err := EncodeVisibleString(value); err != nil {
+			return nil, err
+		}
+		data = CreateAxdrVisibleString(value)
+	case strUTF8String:
+		if !utf8.ValidString(value) {
+			return nil, fmt.Errorf("invalid UTF-8 string")
+		}
+		data = CreateAxdrUTF8String(value)
 	case strBCD:
-		tmp, err := strconv.ParseInt(valueSplit[2], 10, 8)
+		tmp, err := strconv.ParseInt(value, 10, 8)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrBCD(int8(tmp))
 	case strInteger:
-		tmp, err := strconv.ParseInt(valueSplit[2], 10, 8)
+		tmp, err := strconv.ParseInt(value, 10, 8)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrInteger(int8(tmp))
 	case strLong:
-		tmp, err := strconv.ParseInt(valueSplit[2], 10, 16)
+		tmp, err := strconv.ParseInt(value, 10, 16)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrLong(int16(tmp))
 	case strUnsigned:
-		tmp, err := strconv.ParseUint(valueSplit[2], 10, 8)
+		tmp, err := strconv.ParseUint(value, 10, 8)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrUnsigned(uint8(tmp))
 	case strLongUnsigned:
-		tmp, err := strconv.ParseUint(valueSplit[2], 10, 16)
+		tmp, err := strconv.ParseUint(value, 10, 16)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrLongUnsigned(uint16(tmp))
 	case strLong64:
-		tmp, err := strconv.ParseInt(valueSplit[2], 10, 64)
+		tmp, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrLong64(tmp)
 	case strLong64Unsigned:
-		tmp, err := strconv.ParseUint(valueSplit[2], 10, 64)
+		tmp, err := strconv.ParseUint(value, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrLong64Unsigned(tmp)
 	case strEnum:
-		tmp, err := strconv.ParseInt(valueSplit[2], 10, 8)
+		tmp, err := strconv.ParseUint(value, 10, 8)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrEnum(uint8(tmp))
 	case strFloat32:
-		tmp, err := strconv.ParseFloat(valueSplit[2], 32)
+		tmp, err := strconv.ParseFloat(value, 32)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrFloat32(float32(tmp))
 	case strFloat64:
-		tmp, err := strconv.ParseFloat(valueSplit[2], 64)
+		tmp, err := strconv.ParseFloat(value, 64)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrFloat64(tmp)
 	case strDateTime:
-		data = CreateAxdrOctetString(valueSplit[2])
-		// data = CreateAxdrDateTime(tmp)
+		tmp, err := time.Parse(dateTimeLayout+zoneLayout, value)
+		if err != nil {
+			tmp, err = time.Parse(dateTimeLayout, value)
+		}
+
+		if err != nil {
+			// Date-times given as the hex of their octet-string are kept as such
+			if _, errHex := EncodeOctetString(value); errHex != nil {
+				return nil, err
+			}
+
+			return CreateAxdrOctetString(value), nil
+		}
+		data = CreateAxdrDateTime(tmp)
 	case strDate:
-		tmp, err := time.Parse(dateLayout, valueSplit[2])
+		tmp, err := time.Parse(dateLayout, value)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrDate(tmp)
 	case strTime:
-		tmp, err := time.Parse(timeLayout, valueSplit[2])
+		tmp, err := time.Parse(timeLayout, value)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 		data = CreateAxdrTime(tmp)
 	case strRaw:
-		src, err := hex.DecodeString(valueSplit[2])
+		src, err := hex.DecodeString(value)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
+		}
+
+		if len(src) == 0 {
+			return nil, fmt.Errorf("no data")
 		}
 
 		dec := NewDataDecoder(&src)
 		t1, err := dec.Decode(&src)
 		if err != nil {
-			return nil, fmt.Errorf(nonEncodableError+"%w", err)
+			return nil, err
 		}
 
-		data = &t1
-	default:
-		return nil, fmt.Errorf("unsupported type: %s", valueSplit[2])
-	}
-
-	return
-}
-
-func getElementsData(data string) []string {
-	dataElements := make([]string, 0, 1)
-	length := len(data)
-	posIni := 0
-	lastPost := 0
-	chrIni := data[posIni]
-
-	for i, chr := range data {
-		if chr == openBracket && (chrIni == 'a' || chrIni == 's') {
-			tmpData := data
-			for _, v := range dataElements {
-				tmpData = strings.TrimPrefix(tmpData, v)
-			}
-			lastPost = getLastPositionArrayStruct(tmpData) + posIni
-			str := data[posIni:lastPost]
-			dataElements = append(dataElements, str)
-			posIni = lastPost
-		} else if chr == closeBracket && i > posIni {
-			if chrIni != 'a' && chrIni != 's' {
-				lastPost = i + 1
-				dataElements = append(dataElements, data[posIni:lastPost])
-				posIni = lastPost
-			}
+		if len(src) > 0 {
+			return nil, fmt.Errorf("%d bytes left after the data", len(src))
 		}
 
-		if posIni >= length {
-			break
-		}
-		chrIni = data[posIni]
-	}
-
-	return dataElements
-}
-
-func getLastPositionArrayStruct(data string) int {
-	counter := 0
-	position := 0
-
-	for i := 0; i < len(data); i++ {
-		if data[i] == openBracket {
-			counter++
-		} else if data[i] == closeBracket {
-			counter--
-			if counter <= 0 {
-				position = i + 1
-				break
-			}
-		}
-	}
-
-	return position
-}
-
-func setArrayStructure(data string) (axdrValue []*DlmsData, err error) {
-	if data == "" {
-		return make([]*DlmsData, 0), nil
+		data = &t1
+	default:
+		return nil, fmt.Errorf("unsupported type: %s", name)
 	}
-	str := getElementsData(data)
-	axdrValue = make([]*DlmsData, len(str))
 
-	for i, v := range str {
-		axdrValue[i], err = AsnEncode(v)
-		if err != nil {
-			return nil, fmt.Errorf("value \"%s\": %w", v, err)
-		}
-	}
-	return axdrValue, nil
+	return data, nil
 }
@@ -3,10 +3,12 @@
 package axdr
 
 import (
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAsnEncode(t *testing.T) {
@@ -175,6 +177,80 @@ func TestAsnEncode(t *testing.T) {
 			want:    CreateAxdrStructure([]*DlmsData{CreateAxdrStructure([]*DlmsData{CreateAxdrLongUnsigned(8), CreateAxdrOctetString("00 00 01 00 00 ff"), CreateAxdrInteger(2), CreateAxdrLongUnsigned(0)}), CreateAxdrOctetString("07 E8 01 11 03 0A 00 00 FF 80 00 00"), CreateAxdrOctetString("07 E8 01 12 04 0A 00 00 FF 80 00 00"), CreateAxdrArray([]*DlmsData{})}),
 			wantErr: false,
 		},
+		{
+			name:    "utf8_string",
+			v:       "utf8_string{año}",
+			want:    CreateAxdrUTF8String("año"),
+			wantErr: false,
+		},
+		{
+			name:    "escaped string",
+			v:       `visible_string{a\{b\}c\\}`,
+			want:    CreateAxdrVisibleString(`a{b}c\`),
+			wantErr: false,
+		},
+		{
+			name:    "compact_array",
+			v:       "compact_array{long_unsigned{2}long_unsigned{4}}",
+			want:    CreateAxdrCompactArray([]*DlmsData{CreateAxdrLongUnsigned(2), CreateAxdrLongUnsigned(4)}),
+			wantErr: false,
+		},
+		{
+			name:    "dont_care",
+			v:       "dont_care{}",
+			want:    &DlmsData{Tag: TagDontCare, Value: []byte{0}},
+			wantErr: false,
+		},
+		{
+			name:    "date_time with deviation",
+			v:       "date_time{2016/04/01 10:00:00.5 +01:00}",
+			want:    CreateAxdrDateTime(time.Date(2016, time.April, 1, 10, 0, 0, 500000000, time.FixedZone("", 3600))),
+			wantErr: false,
+		},
+		{
+			name:    "enum over 127",
+			v:       "enum{200}",
+			want:    CreateAxdrEnum(200),
+			wantErr: false,
+		},
+		{
+			name: "indented structure",
+			v: `
+structure{
+	long_unsigned{8}
+	array {
+		unsigned{1}
+	}
+}
+`,
+			want:    CreateAxdrStructure([]*DlmsData{CreateAxdrLongUnsigned(8), CreateAxdrArray([]*DlmsData{CreateAxdrUnsigned(1)})}),
+			wantErr: false,
+		},
+		{
+			name:    "wrong visible_string",
+			v:       "visible_string{año}",
+			wantErr: true,
+		},
+		{
+			name:    "wrong bit_string",
+			v:       "bit_string{102}",
+			wantErr: true,
+		},
+		{
+			name:    "unescaped brace",
+			v:       "visible_string{a{b}",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed structure",
+			v:       "structure{unsigned{1}",
+			wantErr: true,
+		},
+		{
+			name:    "wrong raw",
+			v:       "raw{110102}",
+			wantErr: true,
+		},
 		{
 			name:    "wrong array",
 			v:       "array{long_unsigned{-4}long_unsigned{4}}",
@@ -307,3 +383,93 @@ func TestAsnEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestAsnEncodeSyntaxError(t *testing.T) {
+	tests := []struct {
+		name   string
+		v      string
+		line   int
+		column int
+	}{
+		{"empty", "", 1, 1},
+		{"unknown type", "structure{\n  unsigned{1}\n  no_exist{}\n}", 3, 3},
+		{"invalid value", "structure{\n  unsigned{300}\n}", 2, 12},
+		{"missing brace", "structure{\n  unsigned{1}", 2, 14},
+		{"text after value", "unsigned{1} unsigned{2}", 1, 13},
+		{"unescaped brace", "visible_string{a{b}}", 1, 17},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AsnEncode(tt.v)
+
+			var syntaxErr *AsnSyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			assert.Equal(t, tt.line, syntaxErr.Line, err.Error())
+			assert.Equal(t, tt.column, syntaxErr.Column, err.Error())
+		})
+	}
+
+	_, err := AsnEncode("unsigned{-2}")
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
+
+func TestAsnRoundTrip(t *testing.T) {
+	tests := []string{
+		"null_data{}",
+		"dont_care{}",
+		"structure{long_unsigned{8}octet_string{0000010000ff}integer{2}long_unsigned{0}}",
+		"compact_array{structure{unsigned{1}long{-2}}structure{unsigned{3}long{-4}}}",
+		`visible_string{\{\}\\}`,
+		"utf8_string{año}",
+		"bit_string{1010000010}",
+		"floating_point{4.59}",
+		"float_32{-1.25e-10}",
+		"float_64{1.23456789e+300}",
+		"float_64{NaN}",
+		"date_time{2016/04/01 10:00:00}",
+		"date_time{2016/04/01 10:00:00.25 -02:30}",
+		"date{2006/01/02}",
+		"time{15:04:05.5}",
+		"enum{255}",
+		"long64_unsigned{18446744073709551615}",
+	}
+	for _, text := range tests {
+		t.Run(text, func(t *testing.T) {
+			data, err := AsnEncode(text)
+			require.NoError(t, err)
+
+			got, err := AsnDecode(data)
+			require.NoError(t, err)
+			assert.Equal(t, text, got)
+		})
+	}
+}
+
+func FuzzAsnEncode(f *testing.F) {
+	f.Add("structure{long_unsigned{8}octet_string{00 00 01 00 00 ff}integer{2}long_unsigned{0}}")
+	f.Add("array{compact_array{unsigned{1}unsigned{2}}boolean{true}bit_string{0101}}")
+	f.Add(`visible_string{a\{b\}}`)
+	f.Add("date_time{2016/04/01 10:00:00.25 -02:30}date{2006/01/02}time{15:04:05}")
+	f.Add("raw{0204090Ca31cfc8d8e10d9a78ba9f847}")
+
+	f.Fuzz(func(t *testing.T, text string) {
+		data, err := AsnEncode(text)
+		if err != nil {
+			return
+		}
+
+		// The text given for the data must be parsed back to the same text
+		canonical, err := AsnDecode(data)
+		if err != nil {
+			return
+		}
+
+		again, err := AsnEncode(canonical)
+		require.NoError(t, err, canonical)
+
+		got, err := AsnDecode(again)
+		require.NoError(t, err)
+		assert.Equal(t, canonical, got)
+	})
+}
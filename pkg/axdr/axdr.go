@@ -7,9 +7,16 @@ It is standardized by IEC 61334-6 standard [4] and used in DLMS APDUs.
 package axdr
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 type dataTag int
@@ -60,11 +67,10 @@ func CreateAxdrBoolean(data bool) *DlmsData {
 	return &DlmsData{Tag: TagBoolean, Value: data}
 }
 
+// expect a string of binary digits as input, example: 11100000. Spaces are
+// removed; other characters are kept and make Encode return an error.
 func CreateAxdrBitString(data string) *DlmsData {
 	data = strings.ReplaceAll(data, " ", "")
-	if len(strings.Trim(data, "01")) > 0 {
-		panic("Data must be a string of binary, example: 11100000")
-	}
 	return &DlmsData{Tag: TagBitString, Value: data}
 }
 
@@ -156,330 +162,307 @@ func CreateAxdrNull() *DlmsData {
 }
 
 // Encodes Value of DlmsData object according to the Tag
-// It will panic if Value is nil, data type does not match
+// It returns an error if Value is nil, data type does not match
 // the Tag or if failed happen in encoding length/value level.
 func (d *DlmsData) Encode() (out []byte, err error) {
-	if d.Value == nil {
-		err = fmt.Errorf("value to encode cannot be nil")
-		return
+	return d.AppendEncode(nil)
+}
+
+// AppendEncode appends the encoding of the data to dst and returns the extended
+// buffer. Nested elements are appended to the same buffer, so encoding into a
+// reused dst does not allocate for most types. On error dst is returned as it
+// was, with the error of the innermost element that failed.
+func (d *DlmsData) AppendEncode(dst []byte) ([]byte, error) {
+	out, err := d.appendEncode(dst)
+	if err != nil {
+		return dst, err
 	}
 
-	errDataType := fmt.Errorf("cannot encode value %v with tag %v", d.Value, d.Tag)
-	var dataLength []byte
-	var rawValue []byte
+	return out, nil
+}
+
+func (d *DlmsData) appendEncode(dst []byte) ([]byte, error) {
+	if d == nil {
+		return dst, fmt.Errorf("data to encode cannot be nil")
+	}
 
+	// null-data and dont-care carry no value
 	switch d.Tag {
-	case TagNull:
-		rawValue = []byte{0}
+	case TagNull, TagDontCare:
+		return append(dst, byte(d.Tag), 0), nil
+	}
 
-	case TagArray:
-		data, ok := d.Value.([]*DlmsData)
-		if !ok {
-			err = errDataType
-			return
-		}
-		rawValue, _ = EncodeArray(data)
+	if d.Value == nil {
+		return dst, fmt.Errorf("value to encode cannot be nil")
+	}
 
-		dl, errLength := EncodeLength(len(data))
-		if errLength != nil {
-			err = errLength
-			return
-		}
-		dataLength = dl
+	dst = append(dst, byte(d.Tag))
 
-	case TagStructure:
-		// what's the difference between array & structure?
+	switch d.Tag {
+	case TagArray, TagStructure:
 		data, ok := d.Value.([]*DlmsData)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeStructure(data)
 
-		dl, errLength := EncodeLength(len(data))
-		if errLength != nil {
-			err = errLength
-			return
+		dst = appendLength(dst, uint64(len(data)))
+		for _, element := range data {
+			var err error
+			if dst, err = element.appendEncode(dst); err != nil {
+				return dst, err
+			}
 		}
-		dataLength = dl
 
 	case TagBoolean:
 		data, ok := d.Value.(bool)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
+		}
+
+		if data {
+			dst = append(dst, 0x01)
+		} else {
+			dst = append(dst, 0x00)
 		}
-		rawValue, _ = EncodeBoolean(data)
 
 	case TagBitString:
 		data, ok := d.Value.(string)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
 
-		rv, errEncoding := EncodeBitString(data)
-		if errEncoding != nil {
-			err = errEncoding
-			return
-		}
-		rawValue = rv
-
-		// length of bitstring is count by bits, not bytes
-		// length of "1110" is 4, not 1
-		dl, errLength := EncodeLength(len(data))
-		if errLength != nil {
-			err = errLength
-			return
-		}
-		dataLength = dl
+		return appendBitString(dst, data)
 
 	case TagDoubleLong:
 		data, ok := d.Value.(int32)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeDoubleLong(data)
+		dst = binary.BigEndian.AppendUint32(dst, uint32(data))
 
 	case TagDoubleLongUnsigned:
 		data, ok := d.Value.(uint32)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeDoubleLongUnsigned(data)
+		dst = binary.BigEndian.AppendUint32(dst, data)
 
-	case TagFloatingPoint:
+	case TagFloatingPoint, TagFloat32:
 		data, ok := d.Value.(float32)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeFloat32(data)
+		dst = binary.BigEndian.AppendUint32(dst, math.Float32bits(data))
 
 	case TagOctetString:
 		switch value := d.Value.(type) {
 		case time.Time:
-			rv, errEncoding := EncodeDateTime(value)
-			if errEncoding != nil {
-				err = errEncoding
-				return
-			}
-			rawValue = rv
+			dst = appendLength(dst, 12)
+			dst = NewCosemDateTime(value, TimeZoneDeviation).AppendEncode(dst)
 		case string:
-			rv, errEncoding := EncodeOctetString(value)
-			if errEncoding != nil {
-				err = errEncoding
-				return
-			}
-			rawValue = rv
+			return appendOctetString(dst, value)
 		default:
-			err = errDataType
-			return
-		}
-
-		dl, errLength := EncodeLength(len(rawValue))
-		if errLength != nil {
-			err = errLength
-			return
+			return dst, d.errDataType()
 		}
-		dataLength = dl
 
 	case TagVisibleString:
 		data, ok := d.Value.(string)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
 
-		rv, errEncoding := EncodeVisibleString(data)
-		if errEncoding != nil {
-			err = errEncoding
-			return
-		}
-		rawValue = rv
-
-		dl, errLength := EncodeLength(len(rawValue))
-		if errLength != nil {
-			err = errLength
-			return
+		for i := 0; i < len(data); i++ {
+			if data[i] > unicode.MaxASCII {
+				return dst, fmt.Errorf("data to encode is not a valid ASCII string")
+			}
 		}
-		dataLength = dl
+		dst = appendLength(dst, uint64(len(data)))
+		dst = append(dst, data...)
 
 	case TagUTF8String:
 		data, ok := d.Value.(string)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
 
-		rv, errEncoding := EncodeUTF8String(data)
-		if errEncoding != nil {
-			err = errEncoding
-			return
-		}
-		rawValue = rv
-
-		dl, errLength := EncodeLength(len(rawValue))
-		if errLength != nil {
-			err = errLength
-			return
-		}
-		dataLength = dl
-
-	case TagBCD:
-		data, ok := d.Value.(int8)
-		if !ok {
-			err = errDataType
-			return
+		if !utf8.ValidString(data) {
+			return dst, fmt.Errorf("data to encode is not a valid UTF-8 string")
 		}
-		rawValue, _ = EncodeBCD(data)
+		dst = appendLength(dst, uint64(len(data)))
+		dst = append(dst, data...)
 
-	case TagInteger:
+	case TagBCD, TagInteger:
 		data, ok := d.Value.(int8)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeInteger(data)
+		dst = append(dst, byte(data))
 
 	case TagLong:
 		data, ok := d.Value.(int16)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeLong(data)
+		dst = binary.BigEndian.AppendUint16(dst, uint16(data))
 
-	case TagUnsigned:
+	case TagUnsigned, TagEnum:
 		data, ok := d.Value.(uint8)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeUnsigned(data)
+		dst = append(dst, data)
 
 	case TagLongUnsigned:
 		data, ok := d.Value.(uint16)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeLongUnsigned(data)
+		dst = binary.BigEndian.AppendUint16(dst, data)
 
 	case TagCompactArray:
-		err = fmt.Errorf("not yet implemented")
-		return
-
-	case TagLong64:
-		data, ok := d.Value.(int64)
+		data, ok := d.Value.([]*DlmsData)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeLong64(data)
 
-	case TagLong64Unsigned:
-		data, ok := d.Value.(uint64)
-		if !ok {
-			err = errDataType
-			return
+		rawValue, err := EncodeCompactArray(data)
+		if err != nil {
+			return dst, err
 		}
-		rawValue, _ = EncodeLong64Unsigned(data)
+		dst = append(dst, rawValue...)
 
-	case TagEnum:
-		data, ok := d.Value.(uint8)
+	case TagLong64:
+		data, ok := d.Value.(int64)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeEnum(data)
+		dst = binary.BigEndian.AppendUint64(dst, uint64(data))
 
-	case TagFloat32:
-		data, ok := d.Value.(float32)
+	case TagLong64Unsigned:
+		data, ok := d.Value.(uint64)
 		if !ok {
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
-		rawValue, _ = EncodeFloat32(data)
+		dst = binary.BigEndian.AppendUint64(dst, data)
 
 	case TagFloat64:
 		data, ok := d.Value.(float64)
 		if !ok {
-			err = errDataType
-			return
-		}
-		rawValue, _ = EncodeFloat64(data)
-
-	case TagDateTime:
-		var data time.Time
-		switch value := d.Value.(type) {
-		case time.Time:
-			data = value
-		case string:
-			// max year value using parse string is 9999, over will give year 0000
-			data, _ = time.Parse("2006-01-02 15:04:05", value)
-		default:
-			err = errDataType
-			return
+			return dst, d.errDataType()
 		}
+		dst = binary.BigEndian.AppendUint64(dst, math.Float64bits(data))
 
-		rv, errEncoding := EncodeDateTime(data)
-		if errEncoding != nil {
-			err = errEncoding
-			return
+	case TagDateTime, TagDate, TagTime:
+		data, err := d.timeValue()
+		if err != nil {
+			return dst, err
 		}
-		rawValue = rv
 
-	case TagDate:
-		var data time.Time
-		switch value := d.Value.(type) {
-		case time.Time:
-			data = value
-		case string:
-			data, _ = time.Parse("2006-01-02", value)
+		switch d.Tag {
+		case TagDateTime:
+			dst = NewCosemDateTime(data, TimeZoneDeviation).AppendEncode(dst)
+		case TagDate:
+			rawValue, _ := EncodeDate(data)
+			dst = append(dst, rawValue...)
 		default:
-			err = errDataType
-			return
+			rawValue, _ := EncodeTime(data)
+			dst = append(dst, rawValue...)
 		}
 
-		rv, errEncoding := EncodeDate(data)
-		if errEncoding != nil {
-			err = errEncoding
-			return
-		}
-		rawValue = rv
+	default:
+		return dst, fmt.Errorf("unsupported tag %v", d.Tag)
+	}
 
-	case TagTime:
-		var data time.Time
-		switch value := d.Value.(type) {
-		case time.Time:
-			data = value
-		case string:
-			data, _ = time.Parse("15:04:05", value)
-		default:
-			err = errDataType
-			return
+	return dst, nil
+}
+
+func (d *DlmsData) errDataType() error {
+	return fmt.Errorf("cannot encode value %v with tag %v", d.Value, d.Tag)
+}
+
+// appendLength appends the A-XDR length as EncodeLength does
+func appendLength(dst []byte, length uint64) []byte {
+	if length < 0x80 {
+		return append(dst, byte(length))
+	}
+
+	size := (bits.Len64(length) + 7) / 8
+	dst = append(dst, byte(0x80+size))
+	for i := size - 1; i >= 0; i-- {
+		dst = append(dst, byte(length>>(8*i)))
+	}
+
+	return dst
+}
+
+// appendBitString appends the length in bits and the bits of a string of
+// binary digits, padded with trailing zeros to a whole byte
+func appendBitString(dst []byte, data string) ([]byte, error) {
+	data = strings.ReplaceAll(data, " ", "")
+	if len(strings.Trim(data, "01")) > 0 {
+		return dst, fmt.Errorf("data must be a string of binary, example: 11100000")
+	}
+
+	dst = appendLength(dst, uint64(len(data)))
+	for i := 0; i < len(data); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			b <<= 1
+			if i+j < len(data) && data[i+j] == '1' {
+				b |= 1
+			}
 		}
+		dst = append(dst, b)
+	}
+
+	return dst, nil
+}
 
-		rv, errEncoding := EncodeTime(data)
-		if errEncoding != nil {
-			err = errEncoding
-			return
+// appendOctetString appends the length and the octets of an Obis code
+// (a.b.c.d.e.f) or a hex string
+func appendOctetString(dst []byte, data string) ([]byte, error) {
+	if strings.Count(data, ".") == 5 {
+		dst = appendLength(dst, 6)
+		for i := 0; i < 6; i++ {
+			v, rest, _ := strings.Cut(data, ".")
+			bt, err := strconv.ParseUint(v, 10, 8)
+			if err != nil {
+				return dst, fmt.Errorf("failed to parse input as byte for Obis")
+			}
+			dst = append(dst, byte(bt))
+			data = rest
 		}
-		rawValue = rv
 
-	case TagDontCare:
-		rawValue = []byte{0}
+		return dst, nil
 	}
 
-	out = make([]byte, 1, 1+len(dataLength)+len(rawValue))
-	out[0] = byte(d.Tag)
-	if len(dataLength) > 0 {
-		out = append(out, dataLength...)
+	data = strings.ReplaceAll(data, " ", "")
+	if len(data)%2 != 0 {
+		return dst, hex.ErrLength
 	}
-	out = append(out, rawValue...)
 
-	return out, nil
+	dst = appendLength(dst, uint64(len(data)/2))
+	for i := 0; i < len(data); i += 2 {
+		hi, okHi := fromHexChar(data[i])
+		lo, okLo := fromHexChar(data[i+1])
+		if !okHi || !okLo {
+			return dst, fmt.Errorf("invalid hex string %q", data)
+		}
+		dst = append(dst, hi<<4|lo)
+	}
+
+	return dst, nil
+}
+
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+
+	return 0, false
 }
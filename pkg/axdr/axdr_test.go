@@ -401,6 +401,9 @@ func TestDlmsData_WrongBitStringValue(t *testing.T) {
 	tDD := DlmsData{Tag: TagBitString, Value: "ABCDEFG"}
 	_, err := tDD.Encode()
 	assert.Error(t, err)
+
+	_, err = CreateAxdrBitString("10 2").Encode()
+	assert.Error(t, err)
 }
 
 func TestDlmsData_DateTime(t *testing.T) {
@@ -465,6 +468,89 @@ func TestDlmsData_Array(t *testing.T) {
 	assert.Equal(t, decodeHexString("0100"), encoded)
 }
 
+func TestDlmsData_AppendEncode(t *testing.T) {
+	tDD := CreateAxdrStructure([]*DlmsData{
+		CreateAxdrOctetString("0.0.1.0.0.255"),
+		CreateAxdrLongUnsigned(8),
+		{Tag: TagNull},
+	})
+
+	buf := []byte{0xC1, 0x01}
+	encoded, err := tDD.AppendEncode(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, decodeHexString("C101020309060000010000FF1200080000"), encoded)
+
+	// Reusing the buffer gives the same encoding
+	encoded, err = tDD.AppendEncode(encoded[:2])
+	assert.NoError(t, err)
+	assert.Equal(t, decodeHexString("C101020309060000010000FF1200080000"), encoded)
+}
+
+func TestDlmsData_AppendEncodeError(t *testing.T) {
+	tables := []*DlmsData{
+		nil,
+		{Tag: TagBoolean},
+		{Tag: dataTag(8), Value: uint8(1)},
+		CreateAxdrArray([]*DlmsData{CreateAxdrUnsigned(1), {Tag: TagUnsigned, Value: 1}}),
+		CreateAxdrStructure([]*DlmsData{CreateAxdrArray([]*DlmsData{CreateAxdrBitString("12")})}),
+		CreateAxdrStructure([]*DlmsData{CreateAxdrOctetString("0102GG")}),
+		CreateAxdrStructure([]*DlmsData{CreateAxdrDateTime(time.Time{}), {Tag: TagDate, Value: "2020-13-01"}}),
+	}
+
+	for _, table := range tables {
+		buf := []byte{0xC1}
+		encoded, err := table.AppendEncode(buf)
+		assert.Error(t, err)
+		assert.Equal(t, buf, encoded)
+
+		_, err = table.Encode()
+		assert.Error(t, err)
+	}
+
+	_, err := EncodeArray([]*DlmsData{CreateAxdrBoolean(true), {Tag: TagBoolean, Value: 1}})
+	assert.Error(t, err)
+}
+
+func benchmarkData() *DlmsData {
+	entries := make([]*DlmsData, 0, 24)
+	for i := 0; i < 24; i++ {
+		entries = append(entries, CreateAxdrStructure([]*DlmsData{
+			CreateAxdrOctetString("0.0.1.0.0.255"),
+			CreateAxdrDateTime(time.Date(2023, time.January, 15, i, 0, 0, 0, time.UTC)),
+			CreateAxdrDoubleLongUnsigned(uint32(i) * 1000),
+			CreateAxdrLong(-int16(i)),
+			CreateAxdrVisibleString("tariff"),
+			CreateAxdrBitString("1010"),
+		}))
+	}
+
+	return CreateAxdrArray(entries)
+}
+
+func BenchmarkEncode(b *testing.B) {
+	data := benchmarkData()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := data.Encode(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAppendEncode(b *testing.B) {
+	data := benchmarkData()
+	b.ReportAllocs()
+
+	var buf []byte
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = data.AppendEncode(buf[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 // ---------- decoding tests
 
 func TestDecodeLength(t *testing.T) {
@@ -1169,3 +1255,24 @@ func TestDecoderComplexCompactArray(t *testing.T) {
 	assert.Equal(t, d7.Value, t5[2].Value)
 	assert.Equal(t, d8.Value, t5[3].Value)
 }
+
+func FuzzDecode(f *testing.F) {
+	f.Add([]byte{0x02, 0x02, 0x12, 0x00, 0x08, 0x09, 0x06, 0x00, 0x00, 0x01, 0x00, 0x00, 0xff})
+	f.Add([]byte{0x01, 0x82, 0x01, 0x00, 0x04, 0x0a, 0xff, 0xff})
+	f.Add([]byte{0x13, 0x02, 0x02, 0x11, 0x10, 0x00, 0x03, 0x01, 0xff, 0x00, 0x02})
+	f.Add([]byte{0x19, 0x07, 0xe0, 0x04, 0x01, 0x05, 0x0a, 0x00, 0x00, 0x32, 0xff, 0xc4, 0x00})
+	f.Add([]byte{0x13, 0x02, 0x02, 0x01, 0xff, 0xff, 0x01, 0xff, 0xff, 0x00, 0x11, 0x01, 0x05})
+
+	f.Fuzz(func(t *testing.T, src []byte) {
+		length := len(src)
+		dec := NewDataDecoder(&src)
+		if _, err := dec.Decode(&src); err != nil {
+			return
+		}
+
+		// Only an empty source gives data without reading it
+		if length > 0 && len(src) >= length {
+			t.Errorf("no bytes were decoded from %d", length)
+		}
+	})
+}
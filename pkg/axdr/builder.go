@@ -0,0 +1,193 @@
+package axdr
+
+import (
+	"fmt"
+	"time"
+)
+
+// Builder builds nested data with chained calls. Array, Structure and
+// CompactArray open a container that receives the following values until
+// the matching End:
+//
+//	data, err := NewBuilder().
+//		Structure().
+//		LongUnsigned(8).
+//		OctetString("0.0.1.0.0.255").
+//		Integer(2).
+//		End().
+//		Build()
+type Builder struct {
+	open  []*DlmsData
+	built []*DlmsData
+	err   error
+}
+
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Add adds data to the open container, or as the built value if none is open.
+func (b *Builder) Add(data *DlmsData) *Builder {
+	if data == nil {
+		return b.fail(fmt.Errorf("cannot add nil data"))
+	}
+
+	if len(b.open) == 0 {
+		b.built = append(b.built, data)
+		return b
+	}
+
+	parent := b.open[len(b.open)-1]
+	parent.Value = append(parent.Value.([]*DlmsData), data)
+
+	return b
+}
+
+// Array opens an array.
+func (b *Builder) Array() *Builder {
+	return b.begin(TagArray)
+}
+
+// Structure opens a structure.
+func (b *Builder) Structure() *Builder {
+	return b.begin(TagStructure)
+}
+
+// CompactArray opens a compact-array, whose elements must share the type of the first.
+func (b *Builder) CompactArray() *Builder {
+	return b.begin(TagCompactArray)
+}
+
+// End closes the last opened container.
+func (b *Builder) End() *Builder {
+	if len(b.open) == 0 {
+		return b.fail(fmt.Errorf("end without an open array or structure"))
+	}
+
+	b.open = b.open[:len(b.open)-1]
+
+	return b
+}
+
+// Build returns the built value. It fails if a container was left open, if no
+// value or more than one was added at the top level, or on the first error of
+// a previous call.
+func (b *Builder) Build() (*DlmsData, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if len(b.open) > 0 {
+		return nil, fmt.Errorf("%d array or structure left open", len(b.open))
+	}
+
+	if len(b.built) != 1 {
+		return nil, fmt.Errorf("built %d values, expected 1", len(b.built))
+	}
+
+	return b.built[0], nil
+}
+
+func (b *Builder) Null() *Builder {
+	return b.Add(CreateAxdrNull())
+}
+
+func (b *Builder) Boolean(v bool) *Builder {
+	return b.Add(CreateAxdrBoolean(v))
+}
+
+func (b *Builder) BitString(v string) *Builder {
+	if _, err := EncodeBitString(v); err != nil {
+		return b.fail(err)
+	}
+
+	return b.Add(CreateAxdrBitString(v))
+}
+
+func (b *Builder) DoubleLong(v int32) *Builder {
+	return b.Add(CreateAxdrDoubleLong(v))
+}
+
+func (b *Builder) DoubleLongUnsigned(v uint32) *Builder {
+	return b.Add(CreateAxdrDoubleLongUnsigned(v))
+}
+
+// OctetString adds an octet-string from a hex string, an Obis code (a.b.c.d.e.f)
+// or a time.Time encoded as date-time.
+func (b *Builder) OctetString(v interface{}) *Builder {
+	return b.Add(CreateAxdrOctetString(v))
+}
+
+func (b *Builder) VisibleString(v string) *Builder {
+	return b.Add(CreateAxdrVisibleString(v))
+}
+
+func (b *Builder) UTF8String(v string) *Builder {
+	return b.Add(CreateAxdrUTF8String(v))
+}
+
+func (b *Builder) Integer(v int8) *Builder {
+	return b.Add(CreateAxdrInteger(v))
+}
+
+func (b *Builder) Long(v int16) *Builder {
+	return b.Add(CreateAxdrLong(v))
+}
+
+func (b *Builder) Unsigned(v uint8) *Builder {
+	return b.Add(CreateAxdrUnsigned(v))
+}
+
+func (b *Builder) LongUnsigned(v uint16) *Builder {
+	return b.Add(CreateAxdrLongUnsigned(v))
+}
+
+func (b *Builder) Long64(v int64) *Builder {
+	return b.Add(CreateAxdrLong64(v))
+}
+
+func (b *Builder) Long64Unsigned(v uint64) *Builder {
+	return b.Add(CreateAxdrLong64Unsigned(v))
+}
+
+func (b *Builder) Enum(v uint8) *Builder {
+	return b.Add(CreateAxdrEnum(v))
+}
+
+func (b *Builder) Float32(v float32) *Builder {
+	return b.Add(CreateAxdrFloat32(v))
+}
+
+func (b *Builder) Float64(v float64) *Builder {
+	return b.Add(CreateAxdrFloat64(v))
+}
+
+func (b *Builder) DateTime(v time.Time) *Builder {
+	return b.Add(CreateAxdrDateTime(v))
+}
+
+func (b *Builder) Date(v time.Time) *Builder {
+	return b.Add(CreateAxdrDate(v))
+}
+
+func (b *Builder) Time(v time.Time) *Builder {
+	return b.Add(CreateAxdrTime(v))
+}
+
+// begin adds an empty container and opens it
+func (b *Builder) begin(tag dataTag) *Builder {
+	data := &DlmsData{Tag: tag, Value: []*DlmsData{}}
+	b.Add(data)
+	b.open = append(b.open, data)
+
+	return b
+}
+
+// fail keeps the first error, returned by Build
+func (b *Builder) fail(err error) *Builder {
+	if b.err == nil {
+		b.err = err
+	}
+
+	return b
+}
@@ -0,0 +1,65 @@
+package axdr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	dt := time.Date(2023, time.January, 15, 10, 20, 30, 0, time.UTC)
+
+	got, err := NewBuilder().
+		Structure().
+		LongUnsigned(8).
+		OctetString("0.0.1.0.0.255").
+		Integer(2).
+		Array().
+		Structure().Unsigned(1).Boolean(true).End().
+		Structure().Unsigned(2).Null().End().
+		End().
+		DateTime(dt).
+		End().
+		Build()
+	assert.NoError(t, err)
+
+	want := CreateAxdrStructure([]*DlmsData{
+		CreateAxdrLongUnsigned(8),
+		CreateAxdrOctetString("0.0.1.0.0.255"),
+		CreateAxdrInteger(2),
+		CreateAxdrArray([]*DlmsData{
+			CreateAxdrStructure([]*DlmsData{CreateAxdrUnsigned(1), CreateAxdrBoolean(true)}),
+			CreateAxdrStructure([]*DlmsData{CreateAxdrUnsigned(2), CreateAxdrNull()}),
+		}),
+		CreateAxdrDateTime(dt),
+	})
+	assert.Equal(t, want, got)
+
+	got, err = NewBuilder().Array().End().Build()
+	assert.NoError(t, err)
+	assert.Equal(t, CreateAxdrArray([]*DlmsData{}), got)
+
+	got, err = NewBuilder().Long64(-1).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, CreateAxdrLong64(-1), got)
+}
+
+func TestBuilderError(t *testing.T) {
+	tables := []struct {
+		name    string
+		builder *Builder
+	}{
+		{"empty", NewBuilder()},
+		{"two values", NewBuilder().Unsigned(1).Unsigned(2)},
+		{"left open", NewBuilder().Structure().Array().End()},
+		{"end without open", NewBuilder().Unsigned(1).End()},
+		{"nil data", NewBuilder().Structure().Add(nil).End()},
+		{"bit-string", NewBuilder().Structure().BitString("102").End()},
+	}
+
+	for _, table := range tables {
+		_, err := table.builder.Build()
+		assert.Error(t, err, table.name)
+	}
+}
@@ -0,0 +1,401 @@
+package axdr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+const (
+	// maxTypeDescriptionDepth limits the nesting of arrays and structures in the type
+	// description of a compact array.
+	maxTypeDescriptionDepth = 16
+	// maxCompactArrayValues limits the values (including those of the nested arrays
+	// and structures) decoded from a compact array, as structures of many null-data
+	// can describe far more values than bytes the contents have.
+	maxCompactArrayValues = 1 << 20
+)
+
+// TypeDescription is the type of the elements of a compact array. Arrays have a
+// fixed number of elements of the same type and structures one type per element.
+type TypeDescription struct {
+	Tag dataTag
+	// Count is the number of elements of an array
+	Count uint16
+	// Elements are the types of the elements of a structure, or the only type of
+	// the elements of an array
+	Elements []TypeDescription
+}
+
+// TypeDescriptionOf returns the type description of data, as used in a compact
+// array of elements like it.
+func TypeDescriptionOf(data *DlmsData) (td TypeDescription, err error) {
+	td.Tag = data.Tag
+
+	switch data.Tag {
+	case TagArray:
+		elements, ok := data.Value.([]*DlmsData)
+		if !ok || len(elements) > 0xFFFF {
+			return td, fmt.Errorf("invalid array in compact array")
+		}
+
+		td.Count = uint16(len(elements))
+		if len(elements) == 0 {
+			td.Elements = []TypeDescription{{Tag: TagNull}}
+			return td, nil
+		}
+
+		element, err := TypeDescriptionOf(elements[0])
+		if err != nil {
+			return td, err
+		}
+		td.Elements = []TypeDescription{element}
+	case TagStructure:
+		elements, ok := data.Value.([]*DlmsData)
+		if !ok {
+			return td, fmt.Errorf("invalid structure in compact array")
+		}
+
+		td.Elements = make([]TypeDescription, len(elements))
+		for i, e := range elements {
+			if td.Elements[i], err = TypeDescriptionOf(e); err != nil {
+				return td, err
+			}
+		}
+	case TagCompactArray, TagDontCare:
+		return td, fmt.Errorf("type %d not allowed in compact array", data.Tag)
+	}
+
+	return td, nil
+}
+
+// Encode returns the bytes of the type description
+func (td TypeDescription) Encode() ([]byte, error) {
+	var out bytes.Buffer
+	if err := td.encode(&out); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func (td TypeDescription) encode(out *bytes.Buffer) error {
+	out.WriteByte(byte(td.Tag))
+
+	switch td.Tag {
+	case TagArray:
+		if len(td.Elements) != 1 {
+			return fmt.Errorf("array type description must have one element type")
+		}
+
+		out.Write(binary.BigEndian.AppendUint16(nil, td.Count))
+
+		return td.Elements[0].encode(out)
+	case TagStructure:
+		length, err := EncodeLength(len(td.Elements))
+		if err != nil {
+			return err
+		}
+		out.Write(length)
+
+		for _, e := range td.Elements {
+			if err := e.encode(out); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DecodeTypeDescription decodes the type description at the start of src
+func DecodeTypeDescription(src *[]byte) (td TypeDescription, err error) {
+	return decodeTypeDescription(src, 0)
+}
+
+func decodeTypeDescription(src *[]byte, depth int) (td TypeDescription, err error) {
+	if depth > maxTypeDescriptionDepth {
+		return td, fmt.Errorf("type description nested too deep")
+	}
+
+	if len(*src) < 1 {
+		return td, ErrLengthLess
+	}
+
+	td.Tag, err = getDataTag((*src)[0])
+	if err != nil {
+		return
+	}
+	*src = (*src)[1:]
+
+	switch td.Tag {
+	case TagArray:
+		if len(*src) < 2 {
+			return td, ErrLengthLess
+		}
+		td.Count = binary.BigEndian.Uint16(*src)
+		*src = (*src)[2:]
+
+		element, err := decodeTypeDescription(src, depth+1)
+		if err != nil {
+			return td, err
+		}
+
+		// Otherwise any number of elements could be decoded from no contents at all
+		if td.Count > 0 && element.minContentsSize() == 0 {
+			return td, fmt.Errorf("array of %d elements without contents", td.Count)
+		}
+		td.Elements = []TypeDescription{element}
+	case TagStructure:
+		if len(*src) < 1 {
+			return td, ErrLengthLess
+		}
+
+		_, n, err := DecodeLength(src)
+		if err != nil {
+			return td, err
+		}
+
+		// Each element takes at least one byte
+		if n > uint64(len(*src)) {
+			return td, ErrLengthLess
+		}
+
+		td.Elements = make([]TypeDescription, n)
+		for i := range td.Elements {
+			if td.Elements[i], err = decodeTypeDescription(src, depth+1); err != nil {
+				return td, err
+			}
+		}
+	case TagCompactArray, TagDontCare:
+		return td, fmt.Errorf("type %d not allowed in compact array", td.Tag)
+	}
+
+	return td, nil
+}
+
+// minContentsSize returns the minimum number of bytes an element described by td
+// takes in the contents of a compact array, up to math.MaxInt32.
+func (td TypeDescription) minContentsSize() int {
+	switch td.Tag {
+	case TagNull:
+		return 0
+	case TagArray:
+		if len(td.Elements) != 1 {
+			return 0
+		}
+
+		size := td.Elements[0].minContentsSize()
+		if size > 0 && int(td.Count) > math.MaxInt32/size {
+			return math.MaxInt32
+		}
+
+		return int(td.Count) * size
+	case TagStructure:
+		size := 0
+		for _, e := range td.Elements {
+			size += e.minContentsSize()
+			if size >= math.MaxInt32 || size < 0 {
+				return math.MaxInt32
+			}
+		}
+
+		return size
+	default:
+		return 1
+	}
+}
+
+// decodeContents decodes one element described by td from the contents of a
+// compact array, where the values have no tag. Each value decoded takes one from
+// values, and decoding fails when none are left.
+func (td TypeDescription) decodeContents(src *[]byte, values *int) (*DlmsData, error) {
+	if *values <= 0 {
+		return nil, fmt.Errorf("more than %d values in compact array", maxCompactArrayValues)
+	}
+	*values--
+
+	switch td.Tag {
+	case TagArray, TagStructure:
+		n := len(td.Elements)
+		if td.Tag == TagArray {
+			n = int(td.Count)
+		}
+
+		if td.minContentsSize() > len(*src) {
+			return nil, ErrLengthLess
+		}
+
+		elements := make([]*DlmsData, 0, min(n, len(*src)+1))
+		for i := 0; i < n; i++ {
+			element := td.Elements[0]
+			if td.Tag == TagStructure {
+				element = td.Elements[i]
+			}
+
+			e, err := element.decodeContents(src, values)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, e)
+		}
+
+		return &DlmsData{Tag: td.Tag, Value: elements}, nil
+	case TagNull:
+		return CreateAxdrNull(), nil
+	}
+
+	if len(*src) == 0 {
+		return nil, ErrLengthLess
+	}
+
+	dec := Decoder{tag: td.Tag}
+	data, err := dec.Decode(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// encodeContents writes data, described by td, without tags as in the contents of
+// a compact array.
+func (td TypeDescription) encodeContents(data *DlmsData, out *bytes.Buffer) error {
+	if data.Tag != td.Tag {
+		return fmt.Errorf("element of type %d in compact array of type %d", data.Tag, td.Tag)
+	}
+
+	switch td.Tag {
+	case TagArray, TagStructure:
+		elements, ok := data.Value.([]*DlmsData)
+		if !ok {
+			return fmt.Errorf("invalid value %v of type %d", data.Value, data.Tag)
+		}
+
+		n := len(td.Elements)
+		if td.Tag == TagArray {
+			n = int(td.Count)
+		}
+
+		if len(elements) != n {
+			return fmt.Errorf("element of %d items in compact array of %d", len(elements), n)
+		}
+
+		for i, e := range elements {
+			element := td.Elements[0]
+			if td.Tag == TagStructure {
+				element = td.Elements[i]
+			}
+
+			if err := element.encodeContents(e, out); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case TagNull:
+		return nil
+	}
+
+	encoded, err := data.Encode()
+	if err != nil {
+		return err
+	}
+	out.Write(encoded[1:])
+
+	return nil
+}
+
+// DecodeCompactArray decodes the type description and the contents of a compact
+// array. Each element is decoded as the DlmsData it would be in an array.
+func DecodeCompactArray(src []byte) (outByte []byte, outVal interface{}, err error) {
+	// make carbon copy of src to calc rawValue later
+	temp := src
+
+	td, err := DecodeTypeDescription(&temp)
+	if err != nil {
+		return
+	}
+
+	// After the type description, the contents are an octet-string with all the values
+	if len(temp) < 1 {
+		err = ErrLengthLess
+		return
+	}
+
+	_, length, err := DecodeLength(&temp)
+	if err != nil {
+		return
+	}
+
+	if length > uint64(len(temp)) {
+		err = ErrLengthLess
+		return
+	}
+
+	contents := temp[:length]
+	temp = temp[length:]
+
+	values := maxCompactArrayValues
+
+	elements := make([]*DlmsData, 0)
+	for len(contents) > 0 {
+		before := len(contents)
+
+		element, errElement := td.decodeContents(&contents, &values)
+		if errElement != nil {
+			err = fmt.Errorf("invalid compact array element %d: %w", len(elements), errElement)
+			return
+		}
+
+		// Elements without contents (e.g. null-data) would never end
+		if len(contents) == before {
+			err = fmt.Errorf("compact array element without contents")
+			return
+		}
+		elements = append(elements, element)
+	}
+
+	return src[:len(src)-len(temp)], elements, nil
+}
+
+// EncodeCompactArray encodes the elements as a compact array, whose type
+// description is the one of the first element. All the elements must have it.
+func EncodeCompactArray(elements []*DlmsData) ([]byte, error) {
+	td := TypeDescription{Tag: TagNull}
+	if len(elements) > 0 {
+		var err error
+		if td, err = TypeDescriptionOf(elements[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	return EncodeCompactArrayWithType(td, elements)
+}
+
+// EncodeCompactArrayWithType encodes the elements as a compact array with the given
+// type description, needed for empty arrays or arrays whose first element has
+// empty nested arrays.
+func EncodeCompactArrayWithType(td TypeDescription, elements []*DlmsData) ([]byte, error) {
+	var out bytes.Buffer
+	if err := td.encode(&out); err != nil {
+		return nil, err
+	}
+
+	var contents bytes.Buffer
+	for i, e := range elements {
+		if err := td.encodeContents(e, &contents); err != nil {
+			return nil, fmt.Errorf("compact array element %d: %w", i, err)
+		}
+	}
+
+	length, err := EncodeLength(contents.Len())
+	if err != nil {
+		return nil, err
+	}
+	out.Write(length)
+	out.Write(contents.Bytes())
+
+	return out.Bytes(), nil
+}
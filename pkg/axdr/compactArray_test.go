@@ -0,0 +1,176 @@
+package axdr
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeDescription(t *testing.T) {
+	// structure { double-long-unsigned, array[3] of long, structure { octet-string } }
+	td := TypeDescription{Tag: TagStructure, Elements: []TypeDescription{
+		{Tag: TagDoubleLongUnsigned},
+		{Tag: TagArray, Count: 3, Elements: []TypeDescription{{Tag: TagLong}}},
+		{Tag: TagStructure, Elements: []TypeDescription{{Tag: TagOctetString}}},
+	}}
+
+	encoded, err := td.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, "02030601000310020109", hex.EncodeToString(encoded))
+
+	decoded, err := DecodeTypeDescription(&encoded)
+	require.NoError(t, err)
+	assert.Equal(t, td, decoded)
+	assert.Empty(t, encoded)
+
+	data := CreateAxdrStructure([]*DlmsData{
+		CreateAxdrDoubleLongUnsigned(1),
+		CreateAxdrArray([]*DlmsData{CreateAxdrLong(1), CreateAxdrLong(2), CreateAxdrLong(3)}),
+		CreateAxdrStructure([]*DlmsData{CreateAxdrOctetString("01")}),
+	})
+	described, err := TypeDescriptionOf(data)
+	require.NoError(t, err)
+	assert.Equal(t, td, described)
+
+	// Truncated, nested too deep and not allowed in compact arrays
+	for _, s := range []string{"", "01", "0100", "0203", "0102", "13", "ff", "2a"} {
+		src := decodeHexString(s)
+		_, err = DecodeTypeDescription(&src)
+		assert.Error(t, err, s)
+	}
+
+	deep := make([]byte, 0, 3*(maxTypeDescriptionDepth+2))
+	for i := 0; i < maxTypeDescriptionDepth+2; i++ {
+		deep = append(deep, 0x01, 0x00, 0x01)
+	}
+	_, err = DecodeTypeDescription(&deep)
+	assert.ErrorContains(t, err, "too deep")
+}
+
+func TestCompactArrayNested(t *testing.T) {
+	rows := []*DlmsData{
+		CreateAxdrStructure([]*DlmsData{
+			CreateAxdrOctetString("0102"),
+			CreateAxdrArray([]*DlmsData{CreateAxdrLongUnsigned(1), CreateAxdrLongUnsigned(2)}),
+			CreateAxdrStructure([]*DlmsData{CreateAxdrBoolean(true), CreateAxdrVisibleString("ab")}),
+		}),
+		CreateAxdrStructure([]*DlmsData{
+			CreateAxdrOctetString("030405"),
+			CreateAxdrArray([]*DlmsData{CreateAxdrLongUnsigned(3), CreateAxdrLongUnsigned(4)}),
+			CreateAxdrStructure([]*DlmsData{CreateAxdrBoolean(false), CreateAxdrVisibleString("")}),
+		}),
+	}
+
+	encoded, err := CreateAxdrCompactArray(rows).Encode()
+	require.NoError(t, err)
+	assert.Equal(t, "13"+"0203"+"09"+"01000212"+"0202030a"+
+		"15"+"020102"+"00010002"+"01"+"026162"+
+		"03030405"+"00030004"+"00"+"00", hex.EncodeToString(encoded))
+
+	dec := NewDataDecoder(&encoded)
+	decoded, err := dec.Decode(&encoded)
+	require.NoError(t, err)
+	assert.Empty(t, encoded)
+	assert.Equal(t, TagCompactArray, decoded.Tag)
+	assert.Equal(t, rows, decoded.Value)
+}
+
+func TestCompactArrayEncodeFail(t *testing.T) {
+	// Elements of different types
+	_, err := CreateAxdrCompactArray([]*DlmsData{CreateAxdrUnsigned(1), CreateAxdrLong(1)}).Encode()
+	assert.Error(t, err)
+
+	// Arrays of different lengths
+	_, err = CreateAxdrCompactArray([]*DlmsData{
+		CreateAxdrArray([]*DlmsData{CreateAxdrUnsigned(1)}),
+		CreateAxdrArray([]*DlmsData{CreateAxdrUnsigned(1), CreateAxdrUnsigned(2)}),
+	}).Encode()
+	assert.Error(t, err)
+
+	// Empty compact array
+	encoded, err := CreateAxdrCompactArray([]*DlmsData{}).Encode()
+	require.NoError(t, err)
+	assert.Equal(t, "130000", hex.EncodeToString(encoded))
+
+	// Empty with a type
+	encoded, err = EncodeCompactArrayWithType(TypeDescription{Tag: TagLongUnsigned}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1200", hex.EncodeToString(encoded))
+}
+
+func TestCompactArrayDecodeFail(t *testing.T) {
+	for _, s := range []string{
+		"13",               // no type description
+		"1312",             // no contents
+		"131204000100",     // contents shorter than their length
+		"131203000100",     // element truncated
+		"13020209120500",   // variable-length element truncated
+		"1300020000",       // elements without contents
+		"1301000212020001", // array element truncated
+		"13010002000100",   // array of null-data
+		"1301000A12020001", // contents shorter than the elements of the array
+		// structure { array[65535] of array[65535] of null-data, unsigned }
+		"13020201FFFF01FFFF00110105",
+	} {
+		src := decodeHexString(s)
+		dec := NewDataDecoder(&src)
+		_, err := dec.Decode(&src)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCompactArrayTooManyValues(t *testing.T) {
+	// structure { 2000 null-data, unsigned }, whose 600 elements have 1.2 million values
+	src := []byte{0x13, 0x02, 0x82, 0x07, 0xd1}
+	src = append(src, make([]byte, 2000)...)
+	src = append(src, 0x11, 0x82, 0x02, 0x58)
+	src = append(src, make([]byte, 600)...)
+
+	dec := NewDataDecoder(&src)
+	_, err := dec.Decode(&src)
+	assert.ErrorContains(t, err, "values in compact array")
+}
+
+func TestCompactArrayMarshal(t *testing.T) {
+	type row struct {
+		Value  uint32
+		Status uint8 `axdr:"enum"`
+		Name   string
+	}
+
+	rows := []row{{Value: 1, Status: 2, Name: "0a"}, {Value: 3, Status: 4, Name: "0b0c"}}
+
+	data, err := MarshalCompactArray(rows)
+	require.NoError(t, err)
+
+	encoded, err := data.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, "1302030616090f0000000102010a0000000304020b0c", hex.EncodeToString(encoded))
+
+	dec := NewDataDecoder(&encoded)
+	decoded, err := dec.Decode(&encoded)
+	require.NoError(t, err)
+
+	var result []row
+	require.NoError(t, UnmarshalData(decoded, &result))
+	assert.Equal(t, rows, result)
+
+	// As a field
+	var profile struct {
+		Rows []row `axdr:"compact_array"`
+	}
+	profile.Rows = rows
+
+	data, err = MarshalData(profile)
+	require.NoError(t, err)
+	assert.Equal(t, TagCompactArray, data.Value.([]*DlmsData)[0].Tag)
+
+	profile.Rows = nil
+	require.NoError(t, UnmarshalData(*data, &profile))
+	assert.Equal(t, rows, profile.Rows)
+
+	_, err = MarshalCompactArray(1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,310 @@
+package axdr
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClockStatus is the status byte of a date-time
+type ClockStatus uint8
+
+const (
+	ClockStatusInvalid              ClockStatus = 0x01
+	ClockStatusDoubtful             ClockStatus = 0x02
+	ClockStatusDifferentBase        ClockStatus = 0x04
+	ClockStatusInvalidStatus        ClockStatus = 0x08
+	ClockStatusDaylightSavingActive ClockStatus = 0x80
+	ClockStatusNotSpecified         ClockStatus = 0xFF
+)
+
+func (s ClockStatus) IsSpecified() bool {
+	return s != ClockStatusNotSpecified
+}
+
+func (s ClockStatus) IsInvalid() bool {
+	return s.IsSpecified() && s&ClockStatusInvalid != 0
+}
+
+func (s ClockStatus) IsDoubtful() bool {
+	return s.IsSpecified() && s&ClockStatusDoubtful != 0
+}
+
+func (s ClockStatus) IsDaylightSavingActive() bool {
+	return s.IsSpecified() && s&ClockStatusDaylightSavingActive != 0
+}
+
+// Wildcards and special values of the fields of a date-time
+const (
+	YearNotSpecified          uint16 = 0xFFFF
+	NotSpecified              uint8  = 0xFF
+	MonthDaylightSavingsEnd   uint8  = 0xFD
+	MonthDaylightSavingsBegin uint8  = 0xFE
+	DaySecondLastOfMonth      uint8  = 0xFD
+	DayLastOfMonth            uint8  = 0xFE
+	DeviationNotSpecified     int16  = -0x8000
+)
+
+// CosemDateTime is a date-time as sent by the meter, keeping the fields that
+// cannot be represented by a time.Time: wildcards, the day of week, the raw
+// deviation and the clock status.
+type CosemDateTime struct {
+	Year       uint16
+	Month      uint8
+	Day        uint8
+	DayOfWeek  uint8 // 1 is Monday and 7 is Sunday
+	Hour       uint8
+	Minute     uint8
+	Second     uint8
+	Hundredths uint8
+	// Deviation in minutes between local time and UTC, whose sign depends on the
+	// TimeZone convention of the meter.
+	Deviation int16
+	Status    ClockStatus
+}
+
+// NewCosemDateTime returns the date-time of t, with its deviation according to tz.
+func NewCosemDateTime(t time.Time, tz TimeZone) CosemDateTime {
+	d := CosemDateTime{
+		Year:       uint16(t.Year()),
+		Month:      uint8(t.Month()),
+		Day:        uint8(t.Day()),
+		DayOfWeek:  dayOfWeek(t),
+		Hour:       uint8(t.Hour()),
+		Minute:     uint8(t.Minute()),
+		Second:     uint8(t.Second()),
+		Hundredths: uint8(t.Nanosecond() / 10000000),
+	}
+
+	_, offset := t.Zone()
+	switch tz {
+	case TimeZoneIgnored:
+		d.Deviation = DeviationNotSpecified
+	case TimeZoneReversed:
+		d.Deviation = int16(offset / 60)
+	default:
+		d.Deviation = int16(-offset / 60)
+	}
+
+	if t.IsDST() {
+		d.Status |= ClockStatusDaylightSavingActive
+	}
+
+	return d
+}
+
+// DecodeCosemDateTime decodes the 12 bytes of a date-time
+func DecodeCosemDateTime(src *[]byte) (outByte []byte, outVal CosemDateTime, err error) {
+	if len(*src) < 12 {
+		err = ErrLengthLess
+		return
+	}
+	outByte = (*src)[:12]
+
+	outVal = CosemDateTime{
+		Year:       binary.BigEndian.Uint16(outByte[0:2]),
+		Month:      outByte[2],
+		Day:        outByte[3],
+		DayOfWeek:  outByte[4],
+		Hour:       outByte[5],
+		Minute:     outByte[6],
+		Second:     outByte[7],
+		Hundredths: outByte[8],
+		Deviation:  int16(binary.BigEndian.Uint16(outByte[9:11])),
+		Status:     ClockStatus(outByte[11]),
+	}
+
+	(*src) = (*src)[12:]
+	return
+}
+
+// Encode returns the 12 bytes of the date-time
+func (d CosemDateTime) Encode() []byte {
+	return d.AppendEncode(make([]byte, 0, 12))
+}
+
+// AppendEncode appends the 12 bytes of the date-time to dst
+func (d CosemDateTime) AppendEncode(dst []byte) []byte {
+	dst = binary.BigEndian.AppendUint16(dst, d.Year)
+	dst = append(dst, d.Month, d.Day, d.DayOfWeek, d.Hour, d.Minute, d.Second, d.Hundredths)
+	dst = binary.BigEndian.AppendUint16(dst, uint16(d.Deviation))
+
+	return append(dst, byte(d.Status))
+}
+
+// HasWildcards returns whether any field of the date (but the day of week) or the
+// time (but the hundredths) is not specified or has a special value.
+func (d CosemDateTime) HasWildcards() bool {
+	return d.Year == YearNotSpecified || d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > 31 ||
+		d.Hour == NotSpecified || d.Minute == NotSpecified || d.Second == NotSpecified
+}
+
+// Time returns the date-time as a time.Time, interpreting the deviation according
+// to tz. It fails if the date-time has wildcards or is not a valid date.
+func (d CosemDateTime) Time(tz TimeZone) (time.Time, error) {
+	if d.HasWildcards() {
+		return time.Time{}, fmt.Errorf("date-time %s is not specified", d.String())
+	}
+
+	hundredths := int(d.Hundredths)
+	if d.Hundredths == NotSpecified {
+		hundredths = 0
+	}
+
+	str := fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02d.%02dZ", d.Year, d.Month, d.Day, d.Hour, d.Minute, d.Second, hundredths)
+	if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+		return time.Time{}, fmt.Errorf("invalid date-time %s", d.String())
+	}
+
+	return time.Date(int(d.Year), time.Month(d.Month), int(d.Day), int(d.Hour), int(d.Minute), int(d.Second), hundredths*10000000, d.location(tz)), nil
+}
+
+func (d CosemDateTime) location(tz TimeZone) *time.Location {
+	if d.Deviation == DeviationNotSpecified || tz == TimeZoneIgnored {
+		return time.Local
+	}
+
+	if d.Deviation == 0 {
+		return time.UTC
+	}
+
+	offset := int(d.Deviation)
+	if tz == TimeZoneStandard {
+		offset = -offset
+	}
+
+	utc := "UTC"
+	if offset > 0 {
+		utc += "+" + strconv.Itoa(offset/60)
+	} else {
+		utc += "-" + strconv.Itoa(-offset/60)
+	}
+
+	return time.FixedZone(utc, offset*60)
+}
+
+// String returns the date-time as "2006-01-02 15:04:05.00 (1) -60 80", with
+// the day of week, the deviation and the clock status, and "*" in the fields not
+// specified.
+func (d CosemDateTime) String() string {
+	field := func(value uint8, width int) string {
+		if value == NotSpecified {
+			return strings.Repeat("*", width)
+		}
+
+		return fmt.Sprintf("%0*d", width, value)
+	}
+
+	var sb strings.Builder
+
+	if d.Year == YearNotSpecified {
+		sb.WriteString("****")
+	} else {
+		fmt.Fprintf(&sb, "%04d", d.Year)
+	}
+
+	fmt.Fprintf(&sb, "-%s-%s %s:%s:%s.%s (%s) ", field(d.Month, 2), field(d.Day, 2),
+		field(d.Hour, 2), field(d.Minute, 2), field(d.Second, 2), field(d.Hundredths, 2), field(d.DayOfWeek, 1))
+
+	if d.Deviation == DeviationNotSpecified {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(strconv.Itoa(int(d.Deviation)))
+	}
+
+	if d.Status.IsSpecified() {
+		fmt.Fprintf(&sb, " %02X", uint8(d.Status))
+	} else {
+		sb.WriteString(" **")
+	}
+
+	return sb.String()
+}
+
+// CosemDate is a date as sent by the meter, with wildcards (e.g. in special days)
+type CosemDate struct {
+	Year      uint16
+	Month     uint8
+	Day       uint8
+	DayOfWeek uint8 // 1 is Monday and 7 is Sunday
+}
+
+// NewCosemDate returns the date of t
+func NewCosemDate(t time.Time) CosemDate {
+	return CosemDate{Year: uint16(t.Year()), Month: uint8(t.Month()), Day: uint8(t.Day()), DayOfWeek: dayOfWeek(t)}
+}
+
+// DecodeCosemDate decodes the 5 bytes of a date
+func DecodeCosemDate(src *[]byte) (outByte []byte, outVal CosemDate, err error) {
+	if len(*src) < 5 {
+		err = ErrLengthLess
+		return
+	}
+	outByte = (*src)[:5]
+
+	outVal = CosemDate{
+		Year:      binary.BigEndian.Uint16(outByte[0:2]),
+		Month:     outByte[2],
+		Day:       outByte[3],
+		DayOfWeek: outByte[4],
+	}
+
+	(*src) = (*src)[5:]
+	return
+}
+
+// Encode returns the 5 bytes of the date
+func (d CosemDate) Encode() []byte {
+	output := make([]byte, 5)
+
+	binary.BigEndian.PutUint16(output[:2], d.Year)
+	output[2] = d.Month
+	output[3] = d.Day
+	output[4] = d.DayOfWeek
+
+	return output
+}
+
+// CosemTime is a time of day as sent by the meter, with wildcards (e.g. in day
+// profiles)
+type CosemTime struct {
+	Hour       uint8
+	Minute     uint8
+	Second     uint8
+	Hundredths uint8
+}
+
+// NewCosemTime returns the time of day of t
+func NewCosemTime(t time.Time) CosemTime {
+	return CosemTime{Hour: uint8(t.Hour()), Minute: uint8(t.Minute()), Second: uint8(t.Second()), Hundredths: uint8(t.Nanosecond() / 10000000)}
+}
+
+// DecodeCosemTime decodes the 4 bytes of a time
+func DecodeCosemTime(src *[]byte) (outByte []byte, outVal CosemTime, err error) {
+	if len(*src) < 4 {
+		err = ErrLengthLess
+		return
+	}
+	outByte = (*src)[:4]
+
+	outVal = CosemTime{Hour: outByte[0], Minute: outByte[1], Second: outByte[2], Hundredths: outByte[3]}
+
+	(*src) = (*src)[4:]
+	return
+}
+
+// Encode returns the 4 bytes of the time
+func (t CosemTime) Encode() []byte {
+	return []byte{t.Hour, t.Minute, t.Second, t.Hundredths}
+}
+
+// dayOfWeek returns the day of week of t, from 1 to 7, where 1 is Monday
+func dayOfWeek(t time.Time) uint8 {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+
+	return uint8(t.Weekday())
+}
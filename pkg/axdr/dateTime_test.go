@@ -0,0 +1,138 @@
+package axdr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCosemDateTime(t *testing.T) {
+	src := decodeHexString("07E40701030A000000FF8880")
+
+	bt, dt, err := DecodeCosemDateTime(&src)
+	require.NoError(t, err)
+	assert.Len(t, bt, 12)
+	assert.Empty(t, src)
+	assert.Equal(t, CosemDateTime{
+		Year: 2020, Month: 7, Day: 1, DayOfWeek: 3, Hour: 10, Minute: 0, Second: 0, Hundredths: 0,
+		Deviation: -120, Status: ClockStatusDaylightSavingActive,
+	}, dt)
+	assert.True(t, dt.Status.IsDaylightSavingActive())
+	assert.False(t, dt.Status.IsInvalid())
+	assert.False(t, dt.HasWildcards())
+	assert.Equal(t, "2020-07-01 10:00:00.00 (3) -120 80", dt.String())
+	assert.Equal(t, decodeHexString("07E40701030A000000FF8880"), dt.Encode())
+
+	tm, err := dt.Time(TimeZoneStandard)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, time.July, 1, 8, 0, 0, 0, time.UTC).Unix(), tm.Unix())
+
+	tm, err = dt.Time(TimeZoneReversed)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, time.July, 1, 12, 0, 0, 0, time.UTC).Unix(), tm.Unix())
+
+	src = decodeHexString("07E4")
+	_, _, err = DecodeCosemDateTime(&src)
+	assert.Error(t, err)
+}
+
+func TestCosemDateTimeWithWildcards(t *testing.T) {
+	// Last Sunday of March at 02:00, every year
+	src := decodeHexString("FFFF03FE0702000000800003")
+
+	_, dt, err := DecodeCosemDateTime(&src)
+	require.NoError(t, err)
+	assert.Equal(t, YearNotSpecified, dt.Year)
+	assert.Equal(t, DayLastOfMonth, dt.Day)
+	assert.Equal(t, DeviationNotSpecified, dt.Deviation)
+	assert.True(t, dt.Status.IsInvalid())
+	assert.True(t, dt.Status.IsDoubtful())
+	assert.True(t, dt.HasWildcards())
+	assert.Equal(t, "****-03-254 02:00:00.00 (7) * 03", dt.String())
+	assert.Equal(t, decodeHexString("FFFF03FE0702000000800003"), dt.Encode())
+
+	_, err = dt.Time(TimeZoneStandard)
+	assert.Error(t, err)
+
+	dt = CosemDateTime{Year: 2023, Month: 2, Day: 30, DayOfWeek: NotSpecified, Hour: 0, Minute: 0, Second: 0, Hundredths: NotSpecified, Status: ClockStatusNotSpecified}
+	assert.Equal(t, "2023-02-30 00:00:00.** (*) 0 **", dt.String())
+	assert.False(t, dt.Status.IsInvalid())
+
+	_, err = dt.Time(TimeZoneStandard)
+	assert.Error(t, err)
+}
+
+func TestNewCosemDateTime(t *testing.T) {
+	tm := time.Date(2023, time.January, 15, 10, 20, 30, 400000000, time.FixedZone("CET", 3600))
+
+	assert.Equal(t, CosemDateTime{
+		Year: 2023, Month: 1, Day: 15, DayOfWeek: 7, Hour: 10, Minute: 20, Second: 30, Hundredths: 40,
+		Deviation: -60,
+	}, NewCosemDateTime(tm, TimeZoneStandard))
+	assert.Equal(t, int16(60), NewCosemDateTime(tm, TimeZoneReversed).Deviation)
+	assert.Equal(t, DeviationNotSpecified, NewCosemDateTime(tm, TimeZoneIgnored).Deviation)
+
+	for _, tz := range []TimeZone{TimeZoneStandard, TimeZoneReversed} {
+		decoded, err := NewCosemDateTime(tm, tz).Time(tz)
+		assert.NoError(t, err)
+		assert.True(t, tm.Equal(decoded))
+	}
+}
+
+func TestDateTimeWithTimeZone(t *testing.T) {
+	type TestData struct {
+		Time     time.Time
+		DateTime CosemDateTime
+	}
+
+	data := CreateAxdrStructure([]*DlmsData{
+		CreateAxdrOctetString("07d00106040f003000003c00"),
+		CreateAxdrOctetString("ffff0afe0703000000800000"),
+	})
+
+	var result TestData
+	err := UnmarshalDataWithTimeZone(*data, &result, TimeZoneReversed)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2000, time.January, 6, 15, 0, 48, 0, time.FixedZone("UTC+1", 3600)).Unix(), result.Time.Unix())
+	assert.Equal(t, uint8(10), result.DateTime.Month)
+	assert.Equal(t, DayLastOfMonth, result.DateTime.Day)
+
+	marshaled, err := MarshalDataWithTimeZone(result, TimeZoneReversed)
+	assert.NoError(t, err)
+	assert.Equal(t, data, marshaled)
+
+	err = UnmarshalData(*CreateAxdrOctetString("07D0"), &result.DateTime)
+	assert.Error(t, err)
+}
+
+func TestCosemDateAndTime(t *testing.T) {
+	type TestData struct {
+		Date CosemDate
+		Time CosemTime
+	}
+
+	data := CreateAxdrStructure([]*DlmsData{
+		CreateAxdrOctetString("ffff0c19ff"),
+		CreateAxdrOctetString("16000000"),
+	})
+
+	var result TestData
+	err := UnmarshalData(*data, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, TestData{
+		Date: CosemDate{Year: YearNotSpecified, Month: 12, Day: 25, DayOfWeek: NotSpecified},
+		Time: CosemTime{Hour: 22},
+	}, result)
+
+	marshaled, err := MarshalData(result)
+	assert.NoError(t, err)
+	assert.Equal(t, data, marshaled)
+
+	err = UnmarshalData(*CreateAxdrOctetString("16000000"), &result.Date)
+	assert.Error(t, err)
+
+	err = UnmarshalData(*CreateAxdrOctetString("ffff0c19ff"), &result.Time)
+	assert.Error(t, err)
+}
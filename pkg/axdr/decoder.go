@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -14,19 +13,24 @@ import (
 
 type Decoder struct {
 	tag dataTag
+	err error // error reading the tag, returned by Decode
 }
 
 type TimeZone int
 
+// TimeZone is the convention used by a meter for the deviation of date-times
 const (
-	TimeZoneStandard = 0
-	TimeZoneReversed = 1
-	TimeZoneIgnored  = 2
+	TimeZoneStandard TimeZone = 0 // The deviation is UTC minus local time, in minutes.
+	TimeZoneReversed TimeZone = 1 // The deviation is local time minus UTC, in minutes.
+	TimeZoneIgnored  TimeZone = 2 // The deviation is not used and date-times are local.
 )
 
+// TimeZoneDeviation is the convention used when none is given.
+//
+// Deprecated: set it per client with dlms.Settings.TimeZone.
+//
 //nolint:gochecknoglobals
 var TimeZoneDeviation TimeZone = TimeZoneStandard
-var errUnknownTag = errors.New("unknown DLMS tag")
 
 var ErrLengthLess = errors.New("not enough byte length provided")
 
@@ -69,14 +73,15 @@ func getDataTag(in uint8) (t dataTag, err error) {
 	return
 }
 
-// Create new decode from either supplied byte slice pointer. It will remove first byte from source
+// Create new decode from either supplied byte slice pointer. It will remove first byte from source.
+// An empty source is decoded as null-data, while for an unknown tag Decode returns the error.
 func NewDataDecoder(ori *[]byte) *Decoder {
 	if len(*ori) < 1 {
 		return &Decoder{tag: TagNull}
 	}
 	tag, err := getDataTag((*ori)[0])
 	if err != nil {
-		return &Decoder{tag: TagNull}
+		return &Decoder{tag: TagNull, err: err}
 	}
 	(*ori) = (*ori)[1:]
 	return &Decoder{tag: tag}
@@ -84,6 +89,11 @@ func NewDataDecoder(ori *[]byte) *Decoder {
 
 // Decode expect byte second after tag byte.
 func (dec *Decoder) Decode(ori *[]byte) (r DlmsData, err error) {
+	if dec.err != nil {
+		err = dec.err
+		return
+	}
+
 	lengthAfterTag := map[dataTag]bool{
 		TagNull:               false,
 		TagArray:              true,
@@ -133,6 +143,11 @@ func (dec *Decoder) Decode(ori *[]byte) (r DlmsData, err error) {
 		rawValue = []byte{}
 		value = nil
 	case TagArray:
+		// Each element has at least its tag
+		if lengthInt > uint64(len(src)) {
+			err = ErrLengthLess
+			return
+		}
 		output := make([]*DlmsData, lengthInt)
 		// make carbon copy of src to calc rawValue later
 		temp := src
@@ -150,6 +165,10 @@ func (dec *Decoder) Decode(ori *[]byte) (r DlmsData, err error) {
 
 	case TagStructure:
 		// same same as array
+		if lengthInt > uint64(len(src)) {
+			err = ErrLengthLess
+			return
+		}
 		output := make([]*DlmsData, lengthInt)
 		// make carbon copy of src to calc rawValue later
 		temp := src
@@ -230,35 +249,12 @@ func (dec *Decoder) Decode(ori *[]byte) (r DlmsData, err error) {
 	return
 }
 
-func getCompactArrayDecoders(src *[]byte) (outVal []Decoder, err error) {
-	initType := (*src)[0]
-	if initType != byte(TagStructure) {
-		thisDecoder := NewDataDecoder(src)
-		if thisDecoder.tag == TagNull {
-			return nil, errUnknownTag
-		}
-
-		outVal = []Decoder{*thisDecoder}
+func DecodeLength(src *[]byte) (outByte []byte, outVal uint64, err error) {
+	if len(*src) < 1 {
+		err = ErrLengthLess
 		return
 	}
 
-	numberOfStructParams := (*src)[1]
-	outVal = make([]Decoder, numberOfStructParams)
-
-	(*src) = (*src)[2:]
-	for i := 0; i < int(numberOfStructParams); i++ {
-		thisDecoder := NewDataDecoder(src)
-		if thisDecoder.tag == TagNull {
-			return nil, errUnknownTag
-		}
-
-		outVal[i] = *thisDecoder
-	}
-
-	return
-}
-
-func DecodeLength(src *[]byte) (outByte []byte, outVal uint64, err error) {
 	if (*src)[0] > byte(128) {
 		lOfLength := int((*src)[0]) - 128 // L-of-length part
 		if len((*src)) < lOfLength+1 {
@@ -403,6 +399,10 @@ func DecodeUTF8String(src *[]byte, length uint64) (outByte []byte, outVal string
 }
 
 func DecodeBCD(src *[]byte) (outByte []byte, outVal int8, err error) {
+	if len(*src) < 1 {
+		err = ErrLengthLess
+		return
+	}
 	outByte = (*src)[:1]
 	outVal = int8(outByte[0])
 	(*src) = (*src)[1:]
@@ -455,57 +455,6 @@ func DecodeLongUnsigned(src *[]byte) (outByte []byte, outVal uint16, err error)
 	return
 }
 
-func DecodeCompactArray(src []byte) (outByte []byte, outVal interface{}, err error) {
-	// make carbon copy of src to calc rawValue later
-	temp := src
-
-	decoders, errDecoders := getCompactArrayDecoders(&temp)
-	if errDecoders != nil {
-		err = errDecoders
-
-		return
-	}
-
-	// After the element types, the next data is the total length of the parameters in bytes.
-	_, lengthInt, err := DecodeLength(&temp)
-	if err != nil {
-		return
-	}
-
-	lengthOfHeaders := len(src) - len(temp)
-	fullContainer := make([]*DlmsData, 0)
-	parsedBytes := uint64(0)
-
-	for parsedBytes < lengthInt {
-		var singleStruct DlmsData
-		numOfDecoders := len(decoders)
-
-		if numOfDecoders > 1 {
-			singleStructContent := make([]*DlmsData, numOfDecoders)
-			for j := 0; j < numOfDecoders; j++ {
-				thisDlmsData, thisError := decoders[j].Decode(&temp)
-				if thisError != nil {
-					err = thisError
-					return
-				}
-				singleStructContent[j] = &thisDlmsData
-			}
-			singleStruct.Tag = TagStructure
-			singleStruct.Value = singleStructContent
-		} else {
-			singleStruct, err = decoders[0].Decode(&temp)
-			if err != nil {
-				return
-			}
-		}
-
-		fullContainer = append(fullContainer, &singleStruct)
-		parsedBytes = uint64(len(src) - len(temp) - lengthOfHeaders)
-	}
-
-	return src[:len(src)-len(temp)], fullContainer, err
-}
-
 func DecodeLong64(src *[]byte) (outByte []byte, outVal int64, err error) {
 	if len(*src) < 8 {
 		err = ErrLengthLess
@@ -543,6 +492,10 @@ func DecodeLong64Unsigned(src *[]byte) (outByte []byte, outVal uint64, err error
 }
 
 func DecodeEnum(src *[]byte) (outByte []byte, outVal uint8, err error) {
+	if len(*src) < 1 {
+		err = ErrLengthLess
+		return
+	}
 	outByte = (*src)[:1]
 	outVal = outByte[0]
 	(*src) = (*src)[1:]
@@ -632,57 +585,18 @@ func DecodeTime(src *[]byte) (outByte []byte, outVal time.Time, err error) {
 // deviation lowbyte,
 // clock status
 func DecodeDateTime(src *[]byte) (outByte []byte, outVal time.Time, err error) {
-	if len(*src) < 12 {
-		err = ErrLengthLess
-		return
-	}
-	outByte = (*src)[:12]
-
-	// if (outByte[11] & 0x01) != 0 {
-	// 	err = fmt.Errorf("invalid clock value (%02X)", outByte[11])
-	// 	return
-	// }
-
-	year := int(binary.BigEndian.Uint16(outByte[0:2]))
-	month := int(outByte[2])
-	day := int(outByte[3])
-	// weekday := int(outByte[4])
-	hour := int(outByte[5])
-	minute := int(outByte[6])
-	second := int(outByte[7])
-	hundredths := int(outByte[8])
-	if hundredths == 0xFF {
-		hundredths = 0
-	}
-
-	deviation := binary.BigEndian.Uint16(outByte[9:11])
-	location := time.UTC
-	if deviation == 0x8000 || TimeZoneDeviation == TimeZoneIgnored {
-		location = time.Local
-	} else if deviation != 0 {
-		d := int(int16(deviation))
-
-		if TimeZoneDeviation == TimeZoneStandard {
-			d = -d
-		}
-
-		utc := "UTC"
-		if d > 0 {
-			utc += "+" + strconv.Itoa(d/60)
-		} else if d < 0 {
-			utc += "-" + strconv.Itoa(-d/60)
-		}
+	return DecodeDateTimeWithTimeZone(src, TimeZoneDeviation)
+}
 
-		location = time.FixedZone(utc, d*60)
+// DecodeDateTimeWithTimeZone decodes a date-time interpreting its deviation
+// according to tz. Date-times not specified or invalid are decoded as a zero time.
+func DecodeDateTimeWithTimeZone(src *[]byte, tz TimeZone) (outByte []byte, outVal time.Time, err error) {
+	outByte, dt, err := DecodeCosemDateTime(src)
+	if err != nil {
+		return
 	}
 
-	str := fmt.Sprintf("%04d-%02d-%02dT%02d:%02d:%02d.%02dZ", year, month, day, hour, minute, second, hundredths)
-	if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
-		outVal = time.Time{}
-	} else {
-		outVal = time.Date(year, time.Month(month), day, hour, minute, second, hundredths*10000000, location)
-	}
+	outVal, _ = dt.Time(tz)
 
-	(*src) = (*src)[12:]
 	return
 }
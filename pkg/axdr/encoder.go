@@ -96,18 +96,19 @@ func EncodeLength(dataLength interface{}) ([]byte, error) {
 	return output, nil
 }
 
+// Encodes the elements of an array or structure one after the other,
+// without the length. Returns the error of the first element that fails.
 func EncodeArray(data []*DlmsData) ([]byte, error) {
-	var output bytes.Buffer
+	var output []byte
 
 	for _, d := range data {
-		res, err := d.Encode()
-		if err != nil {
-			return []byte{}, nil
+		var err error
+		if output, err = d.AppendEncode(output); err != nil {
+			return []byte{}, err
 		}
-		output.Write(res)
 	}
 
-	return output.Bytes(), nil
+	return output, nil
 }
 
 func EncodeStructure(data []*DlmsData) ([]byte, error) {
@@ -355,37 +356,5 @@ func EncodeTime(data time.Time) ([]byte, error) {
 // deviation lowbyte,
 // clock status -- 0x00 means ok, 0xFF means not specified
 func EncodeDateTime(data time.Time) ([]byte, error) {
-	output := make([]byte, 12)
-	_, offset := data.Zone()
-
-	if TimeZoneDeviation == TimeZoneStandard {
-		offset = -offset
-	}
-
-	// Day of week is from 1 to 7, and 1 is Monday
-	weekday := data.Weekday()
-	if weekday == time.Sunday {
-		weekday = 7
-	}
-
-	binary.BigEndian.PutUint16(output[:2], uint16(data.Year()))
-	output[2] = byte(int(data.Month()))
-	output[3] = byte(data.Day())
-	output[4] = byte(weekday)
-	output[5] = byte(data.Hour())
-	output[6] = byte(data.Minute())
-	output[7] = byte(data.Second())
-	output[8] = byte(data.Nanosecond() / 10000000)
-	if TimeZoneDeviation == TimeZoneIgnored {
-		binary.BigEndian.PutUint16(output[9:11], uint16(0x8000))
-	} else {
-		binary.BigEndian.PutUint16(output[9:11], uint16(offset/60))
-	}
-	output[11] = 0x00
-
-	if data.IsDST() {
-		output[11] |= 0x80
-	}
-
-	return output, nil
+	return NewCosemDateTime(data, TimeZoneDeviation).Encode(), nil
 }
@@ -0,0 +1,333 @@
+package axdr
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// JSON layouts of the values of dates and times
+const (
+	jsonDateLayout = "2006-01-02"
+	jsonTimeLayout = "15:04:05.999999999"
+)
+
+// jsonData is the JSON representation of DlmsData
+type jsonData struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// jsonTypes are the names of the DLMS data types in JSON, which are those of the
+// axdr tags and the types that cannot be used in them.
+func jsonTypes() map[string]dataTag {
+	types := tagNames()
+	types["null_data"] = TagNull
+	types["floating_point"] = TagFloatingPoint
+	types["bcd"] = TagBCD
+
+	return types
+}
+
+func jsonTypeName(t dataTag) (string, error) {
+	for name, dataType := range jsonTypes() {
+		if dataType == t {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("type %d cannot be encoded to JSON", t)
+}
+
+// MarshalJSON encodes d as an object with the name of its type and its value, e.g.
+// {"type":"long_unsigned","value":230}. The values are encoded as:
+//
+//   - null_data: null
+//   - array, structure and compact_array: array of the objects of the elements
+//   - boolean: true or false
+//   - integers, enum and floats: number
+//   - octet_string: hex string
+//   - bit_string: string of 0 and 1, e.g. "0101"
+//   - visible_string and utf8_string: string
+//   - date_time: RFC 3339 string
+//   - date: string as 2006-01-02
+//   - time: string as 15:04:05.999999999
+//
+// Decoding with UnmarshalJSON gives back data with the same encoding. Floats that
+// are NaN or infinite cannot be encoded.
+func (d DlmsData) MarshalJSON() ([]byte, error) {
+	name, err := jsonTypeName(d.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := d.jsonValue()
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s to JSON: %w", name, err)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode %s to JSON: %w", name, err)
+	}
+
+	return json.Marshal(jsonData{Type: name, Value: raw})
+}
+
+// jsonValue returns the value of d as it is encoded to JSON.
+func (d DlmsData) jsonValue() (interface{}, error) {
+	errDataType := fmt.Errorf("unexpected value of type %T", d.Value)
+
+	switch d.Tag {
+	case TagNull:
+		return nil, nil
+	case TagArray, TagStructure, TagCompactArray:
+		elements, ok := d.Value.([]*DlmsData)
+		if !ok {
+			return nil, errDataType
+		}
+
+		values := make([]DlmsData, len(elements))
+		for i, element := range elements {
+			if element == nil {
+				return nil, fmt.Errorf("element %d is nil", i)
+			}
+			values[i] = *element
+		}
+
+		return values, nil
+	case TagOctetString:
+		// Given as the encoded bytes, which are the same for OBIS codes and
+		// date-times
+		var raw []byte
+		var err error
+		switch value := d.Value.(type) {
+		case string:
+			raw, err = EncodeOctetString(value)
+		case time.Time:
+			raw, err = EncodeDateTime(value)
+		default:
+			return nil, errDataType
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return hex.EncodeToString(raw), nil
+	case TagFloatingPoint, TagFloat32, TagFloat64:
+		if _, err := d.Encode(); err != nil {
+			return nil, err
+		}
+
+		f, _ := d.Value.(float64)
+		if f32, ok := d.Value.(float32); ok {
+			f = float64(f32)
+		}
+
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("unsupported value %v", f)
+		}
+
+		return d.Value, nil
+	case TagDateTime, TagDate, TagTime:
+		t, err := d.timeValue()
+		if err != nil {
+			return nil, err
+		}
+
+		switch d.Tag {
+		case TagDate:
+			return t.Format(jsonDateLayout), nil
+		case TagTime:
+			return t.Format(jsonTimeLayout), nil
+		default:
+			return t.Format(time.RFC3339Nano), nil
+		}
+	default:
+		// Only the values of the type of the tag can be encoded
+		if _, err := d.Encode(); err != nil {
+			return nil, err
+		}
+
+		return d.Value, nil
+	}
+}
+
+// timeValue returns the value of a date-time, date or time, which can also be given
+// as a string in the layouts accepted by Encode.
+func (d DlmsData) timeValue() (time.Time, error) {
+	switch value := d.Value.(type) {
+	case time.Time:
+		return value, nil
+	case string:
+		layout := "2006-01-02 15:04:05"
+		switch d.Tag {
+		case TagDate:
+			layout = "2006-01-02"
+		case TagTime:
+			layout = "15:04:05"
+		}
+
+		return time.Parse(layout, value)
+	default:
+		return time.Time{}, fmt.Errorf("unexpected value of type %T", d.Value)
+	}
+}
+
+// UnmarshalJSON decodes the object given by MarshalJSON. The value must be valid
+// for the type, e.g. 300 is not an unsigned and "0x01" is not an octet_string.
+func (d *DlmsData) UnmarshalJSON(b []byte) error {
+	var raw jsonData
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	tag, ok := jsonTypes()[raw.Type]
+	if !ok {
+		return fmt.Errorf("unknown DLMS data type %q", raw.Type)
+	}
+
+	if len(raw.Value) == 0 {
+		raw.Value = json.RawMessage("null")
+	}
+
+	data, err := unmarshalJSONValue(tag, raw.Value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %s: %w", raw.Type, raw.Value, err)
+	}
+
+	*d = *data
+
+	return nil
+}
+
+// unmarshalJSONValue decodes the JSON value of a type.
+func unmarshalJSONValue(tag dataTag, raw json.RawMessage) (*DlmsData, error) {
+	switch tag {
+	case TagNull:
+		if string(raw) != "null" {
+			return nil, fmt.Errorf("expected null")
+		}
+
+		return CreateAxdrNull(), nil
+	case TagArray, TagStructure, TagCompactArray:
+		var elements []*DlmsData
+		if err := unmarshalJSONStrict(raw, &elements); err != nil {
+			return nil, err
+		}
+
+		if elements == nil {
+			elements = []*DlmsData{}
+		}
+
+		for i, element := range elements {
+			if element == nil {
+				return nil, fmt.Errorf("element %d is null", i)
+			}
+		}
+
+		return &DlmsData{Tag: tag, Value: elements}, nil
+	case TagBoolean:
+		return unmarshalJSONAs[bool](tag, raw)
+	case TagBitString:
+		var s string
+		if err := unmarshalJSONStrict(raw, &s); err != nil {
+			return nil, err
+		}
+
+		if _, err := EncodeBitString(s); err != nil {
+			return nil, err
+		}
+
+		return CreateAxdrBitString(s), nil
+	case TagDoubleLong:
+		return unmarshalJSONAs[int32](tag, raw)
+	case TagDoubleLongUnsigned:
+		return unmarshalJSONAs[uint32](tag, raw)
+	case TagFloatingPoint, TagFloat32:
+		return unmarshalJSONAs[float32](tag, raw)
+	case TagFloat64:
+		return unmarshalJSONAs[float64](tag, raw)
+	case TagOctetString:
+		var s string
+		if err := unmarshalJSONStrict(raw, &s); err != nil {
+			return nil, err
+		}
+
+		if _, err := hex.DecodeString(s); err != nil {
+			return nil, err
+		}
+
+		return CreateAxdrOctetString(s), nil
+	case TagVisibleString:
+		var s string
+		if err := unmarshalJSONStrict(raw, &s); err != nil {
+			return nil, err
+		}
+
+		if _, err := EncodeVisibleString(s); err != nil {
+			return nil, err
+		}
+
+		return CreateAxdrVisibleString(s), nil
+	case TagUTF8String:
+		return unmarshalJSONAs[string](tag, raw)
+	case TagBCD, TagInteger:
+		return unmarshalJSONAs[int8](tag, raw)
+	case TagLong:
+		return unmarshalJSONAs[int16](tag, raw)
+	case TagUnsigned, TagEnum:
+		return unmarshalJSONAs[uint8](tag, raw)
+	case TagLongUnsigned:
+		return unmarshalJSONAs[uint16](tag, raw)
+	case TagLong64:
+		return unmarshalJSONAs[int64](tag, raw)
+	case TagLong64Unsigned:
+		return unmarshalJSONAs[uint64](tag, raw)
+	case TagDateTime, TagDate, TagTime:
+		var s string
+		if err := unmarshalJSONStrict(raw, &s); err != nil {
+			return nil, err
+		}
+
+		layout := time.RFC3339Nano
+		switch tag {
+		case TagDate:
+			layout = jsonDateLayout
+		case TagTime:
+			layout = jsonTimeLayout
+		}
+
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return nil, err
+		}
+
+		return &DlmsData{Tag: tag, Value: t}, nil
+	default:
+		return nil, fmt.Errorf("type %d cannot be decoded from JSON", tag)
+	}
+}
+
+// unmarshalJSONAs decodes a value of type T, which is stored as is in the data.
+func unmarshalJSONAs[T any](tag dataTag, raw json.RawMessage) (*DlmsData, error) {
+	var value T
+	if err := unmarshalJSONStrict(raw, &value); err != nil {
+		return nil, err
+	}
+
+	return &DlmsData{Tag: tag, Value: value}, nil
+}
+
+// unmarshalJSONStrict is json.Unmarshal failing with null, which would otherwise
+// leave v as it is.
+func unmarshalJSONStrict(raw json.RawMessage, v interface{}) error {
+	if string(raw) == "null" {
+		return fmt.Errorf("unexpected null")
+	}
+
+	return json.Unmarshal(raw, v)
+}
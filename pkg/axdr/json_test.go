@@ -0,0 +1,136 @@
+package axdr
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDlmsDataJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data *DlmsData
+		json string
+	}{
+		{"null", CreateAxdrNull(), `{"type":"null_data","value":null}`},
+		{"boolean", CreateAxdrBoolean(true), `{"type":"boolean","value":true}`},
+		{"bit string", CreateAxdrBitString("0101"), `{"type":"bit_string","value":"0101"}`},
+		{"double long", CreateAxdrDoubleLong(-100000), `{"type":"double_long","value":-100000}`},
+		{"double long unsigned", CreateAxdrDoubleLongUnsigned(4000000000), `{"type":"double_long_unsigned","value":4000000000}`},
+		{"floating point", CreateAxdrFloatingPoint(1.5), `{"type":"floating_point","value":1.5}`},
+		{"octet string", CreateAxdrOctetString("0102ff"), `{"type":"octet_string","value":"0102ff"}`},
+		{"visible string", CreateAxdrVisibleString("abc"), `{"type":"visible_string","value":"abc"}`},
+		{"utf8 string", CreateAxdrUTF8String("àbc"), `{"type":"utf8_string","value":"àbc"}`},
+		{"bcd", CreateAxdrBCD(12), `{"type":"bcd","value":12}`},
+		{"integer", CreateAxdrInteger(-1), `{"type":"integer","value":-1}`},
+		{"long", CreateAxdrLong(-300), `{"type":"long","value":-300}`},
+		{"unsigned", CreateAxdrUnsigned(255), `{"type":"unsigned","value":255}`},
+		{"long unsigned", CreateAxdrLongUnsigned(230), `{"type":"long_unsigned","value":230}`},
+		{"long64", CreateAxdrLong64(math.MinInt64), `{"type":"long64","value":-9223372036854775808}`},
+		{"long64 unsigned", CreateAxdrLong64Unsigned(math.MaxUint64), `{"type":"long64_unsigned","value":18446744073709551615}`},
+		{"enum", CreateAxdrEnum(3), `{"type":"enum","value":3}`},
+		{"float32", CreateAxdrFloat32(0.1), `{"type":"float32","value":0.1}`},
+		{"float64", CreateAxdrFloat64(0.1), `{"type":"float64","value":0.1}`},
+		{"date time", CreateAxdrDateTime(time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)), `{"type":"date_time","value":"2024-03-10T12:30:00Z"}`},
+		{"date", CreateAxdrDate(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)), `{"type":"date","value":"2024-03-10"}`},
+		{"time", CreateAxdrTime(time.Date(0, 1, 1, 12, 30, 15, 0, time.UTC)), `{"type":"time","value":"12:30:15"}`},
+		{"structure", CreateAxdrStructure([]*DlmsData{
+			CreateAxdrOctetString("0100010800ff"),
+			CreateAxdrArray([]*DlmsData{CreateAxdrLongUnsigned(1), CreateAxdrLongUnsigned(2)}),
+			CreateAxdrStructure([]*DlmsData{}),
+		}), `{"type":"structure","value":[` +
+			`{"type":"octet_string","value":"0100010800ff"},` +
+			`{"type":"array","value":[{"type":"long_unsigned","value":1},{"type":"long_unsigned","value":2}]},` +
+			`{"type":"structure","value":[]}]}`},
+		{"compact array", CreateAxdrCompactArray([]*DlmsData{CreateAxdrUnsigned(1), CreateAxdrUnsigned(2)}),
+			`{"type":"compact_array","value":[{"type":"unsigned","value":1},{"type":"unsigned","value":2}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := json.Marshal(tt.data)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.json, string(out))
+
+			var data DlmsData
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &data))
+			assert.Equal(t, *tt.data, data)
+
+			want, err := tt.data.Encode()
+			require.NoError(t, err)
+			got, err := data.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestDlmsDataJSONDecoded(t *testing.T) {
+	// Values as given by the decoder round trip with the same encoding
+	src := decodeHexString("0205" +
+		"090c07e8030a0712300000ff8880" + // date-time with deviation and status
+		"1a07e8030aff" + // date
+		"1b0c1e0f2a" + // time with hundredths
+		"0906000001000cff" + // OBIS code
+		"04050a")
+	dec := NewDataDecoder(&src)
+	data, err := dec.Decode(&src)
+	require.NoError(t, err)
+
+	out, err := json.Marshal(data)
+	require.NoError(t, err)
+
+	var decoded DlmsData
+	require.NoError(t, json.Unmarshal(out, &decoded))
+
+	want, err := data.Encode()
+	require.NoError(t, err)
+	got, err := decoded.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// Octet strings are always hex
+	out, err = json.Marshal(CreateAxdrOctetString("1.0.1.8.0.255"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"octet_string","value":"0100010800ff"}`, string(out))
+}
+
+func TestDlmsDataJSONFail(t *testing.T) {
+	for _, s := range []string{
+		`[]`,
+		`{"type":"unknown","value":1}`,
+		`{"type":"unsigned","value":256}`,
+		`{"type":"unsigned","value":-1}`,
+		`{"type":"unsigned","value":1.5}`,
+		`{"type":"unsigned","value":"1"}`,
+		`{"type":"long","value":null}`,
+		`{"type":"boolean"}`,
+		`{"type":"null_data","value":0}`,
+		`{"type":"octet_string","value":"0x01"}`,
+		`{"type":"visible_string","value":"à"}`,
+		`{"type":"bit_string","value":"012"}`,
+		`{"type":"date_time","value":"2024-03-10 12:30:00"}`,
+		`{"type":"array","value":{}}`,
+		`{"type":"array","value":[null]}`,
+		`{"type":"structure","value":[{"type":"unsigned","value":300}]}`,
+	} {
+		var data DlmsData
+		assert.Error(t, json.Unmarshal([]byte(s), &data), s)
+	}
+
+	for _, data := range []*DlmsData{
+		{Tag: TagDontCare},
+		CreateAxdrFloat64(math.NaN()),
+		CreateAxdrFloat32(float32(math.Inf(1))),
+		{Tag: TagUnsigned, Value: nil},
+		{Tag: TagOctetString, Value: 1},
+		CreateAxdrArray([]*DlmsData{nil}),
+	} {
+		_, err := json.Marshal(data)
+		assert.Error(t, err, data)
+	}
+}
@@ -1,25 +1,80 @@
 package axdr
 
 import (
+	"encoding/hex"
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 )
 
+// Marshaler is implemented by types that marshal themselves into DlmsData.
+type Marshaler interface {
+	MarshalAXDR() (*DlmsData, error)
+}
+
+// MarshalData converts v into DlmsData. Each Go kind is mapped to a fixed DLMS
+// type (e.g. string to octet-string and slices to arrays), which can be changed
+// for struct fields with the axdr tag (see tagOptions). Types implementing
+// Marshaler are encoded by their MarshalAXDR method, and DlmsData values are kept
+// as they are.
 func MarshalData(v interface{}) (*DlmsData, error) {
 	rv := eindirect(reflect.ValueOf(v))
-	return encode(rv)
+	return encode(rv, nil, tagOptions{})
+}
+
+// MarshalDataWithTimeZone is MarshalData encoding the deviation of date-times
+// according to tz.
+func MarshalDataWithTimeZone(v interface{}, tz TimeZone) (*DlmsData, error) {
+	rv := eindirect(reflect.ValueOf(v))
+	return encode(rv, &tz, tagOptions{})
 }
 
-func encode(rv reflect.Value) (data *DlmsData, err error) {
+// MarshalCompactArray is MarshalData encoding a slice or array (e.g. the rows of a
+// buffer as a []struct) as a compact array instead of an array.
+func MarshalCompactArray(v interface{}) (*DlmsData, error) {
+	rv := eindirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("compact array of unsupported type: %s", rv.Kind())
+	}
+
+	return encode(rv, nil, tagOptions{dataType: TagCompactArray, name: "compact_array"})
+}
+
+// encode marshals rv as the type given by the tag, if any. Date-times are encoded
+// with tz or, if it is nil, with TimeZoneDeviation when the data is encoded.
+func encode(rv reflect.Value, tz *TimeZone, tag tagOptions) (data *DlmsData, err error) {
 	if !rv.IsValid() {
 		return nil, fmt.Errorf("invalid value")
 	}
 
-	_, isTime := rv.Interface().(time.Time)
-	if isTime {
-		data = CreateAxdrOctetString(rv.Interface().(time.Time))
-		return
+	if m, ok := marshaler(rv); ok {
+		return m.MarshalAXDR()
+	}
+
+	if tag.appliesTo(rv.Type()) {
+		if rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+			return encodeElement(rv.Elem(), tz, tag)
+		}
+
+		return encodeTagged(rv, tz, tag)
+	}
+
+	switch v := rv.Interface().(type) {
+	case DlmsData:
+		return &v, nil
+	case time.Time:
+		if tz == nil {
+			return CreateAxdrOctetString(v), nil
+		}
+
+		return CreateAxdrOctetString(hex.EncodeToString(NewCosemDateTime(v, *tz).Encode())), nil
+	case CosemDateTime:
+		return CreateAxdrOctetString(hex.EncodeToString(v.Encode())), nil
+	case CosemDate:
+		return CreateAxdrOctetString(hex.EncodeToString(v.Encode())), nil
+	case CosemTime:
+		return CreateAxdrOctetString(hex.EncodeToString(v.Encode())), nil
 	}
 
 	k := rv.Kind()
@@ -51,23 +106,43 @@ func encode(rv reflect.Value) (data *DlmsData, err error) {
 	case reflect.Array, reflect.Slice:
 		axdrArray := make([]*DlmsData, rv.Len())
 		for i := 0; i < rv.Len(); i++ {
-			axdrArray[i], err = encode(rv.Index(i))
+			axdrArray[i], err = encodeElement(rv.Index(i), tz, tag.element())
 			if err != nil {
 				return nil, fmt.Errorf("element[%d]: %w", i, err)
 			}
 		}
-		data = CreateAxdrArray(axdrArray)
+
+		switch tag.dataType {
+		case TagStructure:
+			data = CreateAxdrStructure(axdrArray)
+		case TagCompactArray:
+			data = CreateAxdrCompactArray(axdrArray)
+		default:
+			data = CreateAxdrArray(axdrArray)
+		}
 	case reflect.Struct:
-		axdrStruct := make([]*DlmsData, rv.NumField())
+		axdrStruct := make([]*DlmsData, 0, rv.NumField())
 		for i := 0; i < rv.NumField(); i++ {
-			axdrStruct[i], err = encode(rv.Field(i))
+			field := rv.Type().Field(i)
+
+			fieldTag, err := parseTag(field.Tag.Get("axdr"))
 			if err != nil {
-				return nil, fmt.Errorf("field[%s]: %w", rv.Type().Field(i).Name, err)
+				return nil, fmt.Errorf("field[%s]: %w", field.Name, err)
 			}
+
+			if fieldTag.skip || (fieldTag.omitNull && isNil(rv.Field(i))) {
+				continue
+			}
+
+			element, err := encodeElement(rv.Field(i), tz, fieldTag)
+			if err != nil {
+				return nil, fmt.Errorf("field[%s]: %w", field.Name, err)
+			}
+			axdrStruct = append(axdrStruct, element)
 		}
 		data = CreateAxdrStructure(axdrStruct)
 	case reflect.Ptr, reflect.Interface:
-		data, err = encode(rv.Elem())
+		data, err = encode(rv.Elem(), tz, tag)
 	default:
 		return nil, fmt.Errorf("unsupported type: %s", k)
 	}
@@ -75,6 +150,176 @@ func encode(rv reflect.Value) (data *DlmsData, err error) {
 	return
 }
 
+// marshaler returns the Marshaler of rv, if its type or a pointer to it implements it.
+func marshaler(rv reflect.Value) (Marshaler, bool) {
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, false
+	}
+
+	if m, ok := rv.Interface().(Marshaler); ok {
+		return m, true
+	}
+
+	if rv.CanAddr() {
+		m, ok := rv.Addr().Interface().(Marshaler)
+		return m, ok
+	}
+
+	return nil, false
+}
+
+// encodeElement marshals a field or an element, encoding nil pointers as null-data.
+func encodeElement(rv reflect.Value, tz *TimeZone, tag tagOptions) (*DlmsData, error) {
+	if (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return CreateAxdrNull(), nil
+	}
+
+	return encode(rv, tz, tag)
+}
+
+// encodeTagged marshals a value into the simple type given by the tag.
+func encodeTagged(rv reflect.Value, tz *TimeZone, tag tagOptions) (*DlmsData, error) {
+	k := rv.Kind()
+
+	switch tag.dataType {
+	case TagBoolean:
+		if k == reflect.Bool {
+			return CreateAxdrBoolean(rv.Bool()), nil
+		}
+	case TagEnum, TagUnsigned, TagLongUnsigned, TagDoubleLongUnsigned, TagLong64Unsigned,
+		TagInteger, TagLong, TagDoubleLong, TagLong64:
+		return encodeTaggedNumber(rv, tag)
+	case TagFloat32:
+		if k == reflect.Float32 || k == reflect.Float64 {
+			return CreateAxdrFloat32(float32(rv.Float())), nil
+		}
+	case TagFloat64:
+		if k == reflect.Float32 || k == reflect.Float64 {
+			return CreateAxdrFloat64(rv.Float()), nil
+		}
+	case TagVisibleString:
+		if k == reflect.String {
+			return CreateAxdrVisibleString(rv.String()), nil
+		}
+	case TagUTF8String:
+		if k == reflect.String {
+			return CreateAxdrUTF8String(rv.String()), nil
+		}
+	case TagOctetString:
+		if k == reflect.String {
+			return CreateAxdrOctetString(rv.String()), nil
+		}
+
+		if k == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return CreateAxdrOctetString(hex.EncodeToString(rv.Bytes())), nil
+		}
+	case TagBitString:
+		if k == reflect.String {
+			if !isBinary(rv.String()) {
+				return nil, fmt.Errorf("invalid bit-string %q", rv.String())
+			}
+
+			return CreateAxdrBitString(rv.String()), nil
+		}
+	case TagDate:
+		switch v := rv.Interface().(type) {
+		case time.Time:
+			return CreateAxdrOctetString(hex.EncodeToString(NewCosemDate(v).Encode())), nil
+		case CosemDate:
+			return CreateAxdrOctetString(hex.EncodeToString(v.Encode())), nil
+		}
+	case TagTime:
+		switch v := rv.Interface().(type) {
+		case time.Time:
+			return CreateAxdrOctetString(hex.EncodeToString(NewCosemTime(v).Encode())), nil
+		case CosemTime:
+			return CreateAxdrOctetString(hex.EncodeToString(v.Encode())), nil
+		}
+	case TagDateTime:
+		switch rv.Interface().(type) {
+		case time.Time, CosemDateTime:
+			return encode(rv, tz, tagOptions{})
+		}
+	}
+
+	return nil, fmt.Errorf("%s cannot be encoded as %s", rv.Type(), tag.name)
+}
+
+func encodeTaggedNumber(rv reflect.Value, tag tagOptions) (*DlmsData, error) {
+	var signed int64
+	var unsigned uint64
+	var negative bool
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		signed = rv.Int()
+		unsigned = uint64(signed)
+		negative = signed < 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		unsigned = rv.Uint()
+		signed = int64(unsigned)
+	default:
+		return nil, fmt.Errorf("%s cannot be encoded as %s", rv.Type(), tag.name)
+	}
+
+	inRange := func(min int64, max uint64) bool {
+		if negative {
+			return signed >= min
+		}
+
+		return unsigned <= max
+	}
+
+	var data *DlmsData
+	var ok bool
+
+	switch tag.dataType {
+	case TagEnum:
+		data, ok = CreateAxdrEnum(uint8(unsigned)), inRange(0, math.MaxUint8)
+	case TagUnsigned:
+		data, ok = CreateAxdrUnsigned(uint8(unsigned)), inRange(0, math.MaxUint8)
+	case TagLongUnsigned:
+		data, ok = CreateAxdrLongUnsigned(uint16(unsigned)), inRange(0, math.MaxUint16)
+	case TagDoubleLongUnsigned:
+		data, ok = CreateAxdrDoubleLongUnsigned(uint32(unsigned)), inRange(0, math.MaxUint32)
+	case TagLong64Unsigned:
+		data, ok = CreateAxdrLong64Unsigned(unsigned), inRange(0, math.MaxUint64)
+	case TagInteger:
+		data, ok = CreateAxdrInteger(int8(signed)), inRange(math.MinInt8, math.MaxInt8)
+	case TagLong:
+		data, ok = CreateAxdrLong(int16(signed)), inRange(math.MinInt16, math.MaxInt16)
+	case TagDoubleLong:
+		data, ok = CreateAxdrDoubleLong(int32(signed)), inRange(math.MinInt32, math.MaxInt32)
+	default:
+		data, ok = CreateAxdrLong64(signed), inRange(math.MinInt64, math.MaxInt64)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%v out of range of %s", rv.Interface(), tag.name)
+	}
+
+	return data, nil
+}
+
+func isNil(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+func isBinary(s string) bool {
+	for _, r := range s {
+		if r != '0' && r != '1' {
+			return false
+		}
+	}
+
+	return true
+}
+
 func eindirect(v reflect.Value) reflect.Value {
 	switch v.Kind() {
 	case reflect.Ptr, reflect.Interface:
@@ -1,6 +1,8 @@
 package axdr
 
 import (
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -163,3 +165,128 @@ func TestMarshalData(t *testing.T) {
 		})
 	}
 }
+
+func TestMarshalDataWithTags(t *testing.T) {
+	value := uint32(5)
+
+	type tagged struct {
+		Unit     int       `axdr:"enum"`
+		Name     string    `axdr:"visible_string"`
+		Password []byte    `axdr:"octet_string"`
+		Flags    string    `axdr:"bit_string"`
+		Day      time.Time `axdr:"date"`
+		Start    time.Time `axdr:"time"`
+		Scaler   int       `axdr:"integer"`
+		Modes    []uint8   `axdr:"enum"`
+		Pair     []uint16  `axdr:"structure"`
+		Value    *uint32   `axdr:"long_unsigned,omitnull"`
+		Missing  *uint32   `axdr:",omitnull"`
+		Null     *uint32
+		Internal string `axdr:"-"`
+	}
+
+	got, err := MarshalData(tagged{
+		Unit:     30,
+		Name:     "meter",
+		Password: []byte{0x12, 0xab},
+		Flags:    "1010",
+		Day:      time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC),
+		Start:    time.Date(2024, 3, 10, 6, 30, 0, 0, time.UTC),
+		Scaler:   -3,
+		Modes:    []uint8{1, 2},
+		Pair:     []uint16{1, 2},
+		Value:    &value,
+		Internal: "ignored",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, CreateAxdrStructure([]*DlmsData{
+		CreateAxdrEnum(30),
+		CreateAxdrVisibleString("meter"),
+		CreateAxdrOctetString("12ab"),
+		CreateAxdrBitString("1010"),
+		CreateAxdrOctetString("07e8030a07"),
+		CreateAxdrOctetString("061e0000"),
+		CreateAxdrInteger(-3),
+		CreateAxdrArray([]*DlmsData{CreateAxdrEnum(1), CreateAxdrEnum(2)}),
+		CreateAxdrStructure([]*DlmsData{CreateAxdrLongUnsigned(1), CreateAxdrLongUnsigned(2)}),
+		CreateAxdrLongUnsigned(5),
+		CreateAxdrNull(),
+	}), got)
+
+	// Out of range
+	_, err = MarshalData(struct {
+		Value int `axdr:"unsigned"`
+	}{Value: 256})
+	assert.Error(t, err)
+
+	// Type not encodable as the one of the tag
+	_, err = MarshalData(struct {
+		Value string `axdr:"long"`
+	}{Value: "1"})
+	assert.Error(t, err)
+
+	// Invalid bit-string
+	_, err = MarshalData(struct {
+		Value string `axdr:"bit_string"`
+	}{Value: "12"})
+	assert.Error(t, err)
+
+	// Unknown type
+	_, err = MarshalData(struct {
+		Value uint8 `axdr:"unknown"`
+	}{Value: 1})
+	assert.Error(t, err)
+}
+
+// testFlags marshals itself as a bit-string of 8 flags, the first one the highest bit
+type testFlags uint8
+
+func (f testFlags) MarshalAXDR() (*DlmsData, error) {
+	return CreateAxdrBitString(fmt.Sprintf("%08b", uint8(f))), nil
+}
+
+func (f *testFlags) UnmarshalAXDR(data DlmsData) error {
+	bits, ok := data.Value.(string)
+	if data.Tag != TagBitString || !ok || len(bits) != 8 {
+		return fmt.Errorf("flags must be a bit-string of 8 bits")
+	}
+
+	v, err := strconv.ParseUint(bits, 2, 8)
+	*f = testFlags(v)
+
+	return err
+}
+
+// testCounter has a marshaler with a pointer receiver
+type testCounter struct {
+	value uint16
+}
+
+func (c *testCounter) MarshalAXDR() (*DlmsData, error) {
+	return CreateAxdrLongUnsigned(c.value), nil
+}
+
+func TestMarshalDataWithMarshaler(t *testing.T) {
+	got, err := MarshalData(testFlags(0xA0))
+	assert.NoError(t, err)
+	assert.Equal(t, CreateAxdrBitString("10100000"), got)
+
+	// Pointer receivers are only found in addressable values, as in encoding/json
+	got, err = MarshalData(&struct {
+		Flags   testFlags
+		Counter testCounter
+		List    []testFlags
+		Missing *testFlags
+	}{Flags: 0x01, Counter: testCounter{value: 7}, List: []testFlags{0x80}})
+	assert.NoError(t, err)
+	assert.Equal(t, CreateAxdrStructure([]*DlmsData{
+		CreateAxdrBitString("00000001"),
+		CreateAxdrLongUnsigned(7),
+		CreateAxdrArray([]*DlmsData{CreateAxdrBitString("10000000")}),
+		CreateAxdrNull(),
+	}), got)
+
+	got, err = MarshalData(&testCounter{value: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, CreateAxdrLongUnsigned(3), got)
+}
@@ -0,0 +1,295 @@
+package axdr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// TokenKind is the kind of a token read by a StreamDecoder
+type TokenKind uint8
+
+const (
+	TokenArray     TokenKind = iota + 1 // Start of an array, followed by its elements
+	TokenStructure                      // Start of a structure, followed by its elements
+	TokenValue                          // A complete value which is not an array or structure
+)
+
+// Token is an element of an encoded value. Arrays and structures are given by
+// their start and number of elements, which are read as the next tokens. Compact
+// arrays are read whole, as values.
+type Token struct {
+	Kind   TokenKind
+	Length int
+	Data   DlmsData
+}
+
+// StreamDecoder decodes A-XDR data from a reader as it arrives, without needing
+// the whole encoded value in memory. It reads from r only the bytes it decodes.
+type StreamDecoder struct {
+	r io.Reader
+}
+
+func NewStreamDecoder(r io.Reader) *StreamDecoder {
+	return &StreamDecoder{r: r}
+}
+
+// fixedLengths are the lengths of the values of the types without length byte
+func fixedLengths() map[dataTag]int {
+	return map[dataTag]int{
+		TagNull:               0,
+		TagBoolean:            1,
+		TagDoubleLong:         4,
+		TagDoubleLongUnsigned: 4,
+		TagFloatingPoint:      4,
+		TagBCD:                1,
+		TagInteger:            1,
+		TagLong:               2,
+		TagUnsigned:           1,
+		TagLongUnsigned:       2,
+		TagLong64:             8,
+		TagLong64Unsigned:     8,
+		TagEnum:               1,
+		TagFloat32:            4,
+		TagFloat64:            8,
+		TagDateTime:           12,
+		TagDate:               5,
+		TagTime:               4,
+	}
+}
+
+// Token reads the next token. It returns io.EOF if the reader ends before it, and
+// io.ErrUnexpectedEOF if it ends in the middle of it.
+func (d *StreamDecoder) Token() (Token, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return Token{}, err
+	}
+
+	tag, err := getDataTag(b)
+	if err != nil {
+		return Token{}, err
+	}
+
+	switch tag {
+	case TagArray, TagStructure:
+		_, length, err := d.readLength(nil)
+		if err != nil {
+			return Token{}, unexpectedEOF(err)
+		}
+
+		if length > math.MaxInt32 {
+			return Token{}, fmt.Errorf("length %d of %s too big", length, tagName(tag))
+		}
+
+		kind := TokenArray
+		if tag == TagStructure {
+			kind = TokenStructure
+		}
+
+		return Token{Kind: kind, Length: int(length)}, nil
+	case TagCompactArray:
+		data, err := d.readCompactArray()
+		if err != nil {
+			return Token{}, unexpectedEOF(err)
+		}
+
+		return Token{Kind: TokenValue, Data: data}, nil
+	}
+
+	raw, err := d.readValue(tag)
+	if err != nil {
+		return Token{}, unexpectedEOF(err)
+	}
+
+	dec := Decoder{tag: tag}
+	data, err := dec.Decode(&raw)
+	if err != nil {
+		return Token{}, err
+	}
+
+	return Token{Kind: TokenValue, Data: data}, nil
+}
+
+// Decode reads the next complete value, including all the elements of arrays and
+// structures.
+func (d *StreamDecoder) Decode() (DlmsData, error) {
+	token, err := d.Token()
+	if err != nil {
+		return DlmsData{}, err
+	}
+
+	if token.Kind == TokenValue {
+		return token.Data, nil
+	}
+
+	// The elements are added as they are decoded, so a wrong length does not
+	// allocate more than the data received
+	elements := make([]*DlmsData, 0)
+	for i := 0; i < token.Length; i++ {
+		element, err := d.Decode()
+		if err != nil {
+			return DlmsData{}, unexpectedEOF(err)
+		}
+		elements = append(elements, &element)
+	}
+
+	if token.Kind == TokenArray {
+		return *CreateAxdrArray(elements), nil
+	}
+
+	return *CreateAxdrStructure(elements), nil
+}
+
+// readValue reads the encoding of a value of type tag, without the tag.
+func (d *StreamDecoder) readValue(tag dataTag) ([]byte, error) {
+	if n, ok := fixedLengths()[tag]; ok {
+		return d.readN(nil, uint64(n))
+	}
+
+	switch tag {
+	case TagOctetString, TagVisibleString, TagUTF8String:
+		raw, length, err := d.readLength(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.readN(raw, length)
+	case TagBitString:
+		raw, length, err := d.readLength(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.readN(raw, (length+7)/8)
+	default:
+		return nil, fmt.Errorf("cannot decode %s", tagName(tag))
+	}
+}
+
+// readCompactArray reads and decodes a compact array, whose tag was already read.
+func (d *StreamDecoder) readCompactArray() (DlmsData, error) {
+	raw, err := d.readTypeDescription(nil, 0)
+	if err != nil {
+		return DlmsData{}, err
+	}
+
+	raw, length, err := d.readLength(raw)
+	if err != nil {
+		return DlmsData{}, err
+	}
+
+	if raw, err = d.readN(raw, length); err != nil {
+		return DlmsData{}, err
+	}
+
+	_, value, err := DecodeCompactArray(raw)
+	if err != nil {
+		return DlmsData{}, err
+	}
+
+	return *CreateAxdrCompactArray(value.([]*DlmsData)), nil
+}
+
+// readTypeDescription reads the bytes of a type description, appended to raw.
+func (d *StreamDecoder) readTypeDescription(raw []byte, depth int) ([]byte, error) {
+	if depth > maxTypeDescriptionDepth {
+		return nil, fmt.Errorf("type description nested too deep")
+	}
+
+	b, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+	raw = append(raw, b)
+
+	switch dataTag(b) {
+	case TagArray:
+		if raw, err = d.readN(raw, 2); err != nil {
+			return nil, err
+		}
+
+		return d.readTypeDescription(raw, depth+1)
+	case TagStructure:
+		var n uint64
+		if raw, n, err = d.readLength(raw); err != nil {
+			return nil, err
+		}
+
+		for i := uint64(0); i < n; i++ {
+			if raw, err = d.readTypeDescription(raw, depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return raw, nil
+}
+
+// readLength reads an A-XDR length, whose bytes are appended to raw.
+func (d *StreamDecoder) readLength(raw []byte) ([]byte, uint64, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return nil, 0, err
+	}
+	raw = append(raw, b)
+
+	if b <= 128 {
+		return raw, uint64(b), nil
+	}
+
+	n := int(b) - 128
+	if n > 8 {
+		return nil, 0, fmt.Errorf("length of %d bytes too big", n)
+	}
+
+	start := len(raw)
+	if raw, err = d.readN(raw, uint64(n)); err != nil {
+		return nil, 0, err
+	}
+
+	var length uint64
+	for _, b := range raw[start:] {
+		length = length<<8 | uint64(b)
+	}
+
+	return raw, length, nil
+}
+
+// readN reads n bytes, appended to raw. The buffer grows as the data arrives, so
+// a wrong length does not allocate more than the data received.
+func (d *StreamDecoder) readN(raw []byte, n uint64) ([]byte, error) {
+	buf := bytes.NewBuffer(raw)
+
+	read, err := io.CopyN(buf, d.r, int64(min(n, math.MaxInt64)))
+	if err != nil {
+		if errors.Is(err, io.EOF) && read < int64(n) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (d *StreamDecoder) readByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(d.r, b[:]); err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+// unexpectedEOF converts io.EOF into io.ErrUnexpectedEOF, for ends in the middle
+// of a value.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,101 @@
+package axdr
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamDecoder(t *testing.T) {
+	values := []*DlmsData{
+		CreateAxdrArray([]*DlmsData{
+			CreateAxdrStructure([]*DlmsData{
+				CreateAxdrDoubleLongUnsigned(1),
+				CreateAxdrOctetString("0102030405"),
+				CreateAxdrBitString("1010101011"),
+				CreateAxdrVisibleString("abc"),
+			}),
+			CreateAxdrStructure([]*DlmsData{
+				CreateAxdrLong64(-2),
+				CreateAxdrEnum(3),
+				CreateAxdrBoolean(true),
+				CreateAxdrFloat64(1.5),
+			}),
+		}),
+		CreateAxdrCompactArray([]*DlmsData{CreateAxdrLongUnsigned(1), CreateAxdrLongUnsigned(2)}),
+		CreateAxdrOctetString(string(bytes.Repeat([]byte("ab"), 300))),
+	}
+
+	var src []byte
+	for _, v := range values {
+		encoded, err := v.Encode()
+		require.NoError(t, err)
+		src = append(src, encoded...)
+	}
+
+	// Read one byte at a time, as if each one were a block
+	dec := NewStreamDecoder(iotest.OneByteReader(bytes.NewReader(src)))
+
+	for _, v := range values {
+		expected, err := v.Encode()
+		require.NoError(t, err)
+
+		expectedData := NewDataDecoder(&expected)
+		want, err := expectedData.Decode(&expected)
+		require.NoError(t, err)
+
+		got, err := dec.Decode()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := dec.Decode()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestStreamDecoderTokens(t *testing.T) {
+	r := bytes.NewReader(decodeHexString("0102020211011200020202110211030303"))
+	dec := NewStreamDecoder(r)
+
+	kinds := []TokenKind{TokenArray, TokenStructure, TokenValue, TokenValue, TokenStructure, TokenValue, TokenValue}
+	for _, kind := range kinds {
+		token, err := dec.Token()
+		require.NoError(t, err)
+		assert.Equal(t, kind, token.Kind)
+
+		if kind != TokenValue {
+			assert.Equal(t, 2, token.Length)
+		}
+	}
+
+	// The rest is not read until needed
+	rest, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x03, 0x03}, rest)
+}
+
+func TestStreamDecoderFail(t *testing.T) {
+	for _, s := range []string{
+		"01",           // length missing
+		"010211",       // element missing
+		"0102110111",   // element truncated
+		"0905010203",   // octet-string truncated
+		"090401",       // length of the octet-string bigger than the data
+		"0408",         // bit-string without data
+		"1312030001",   // compact array truncated
+		"0a8401",       // long length truncated
+		"0989010203",   // length too big
+		"08",           // unknown tag
+		"ff",           // don't care
+		"0182ffffffff", // many elements without data
+	} {
+		dec := NewStreamDecoder(bytes.NewReader(decodeHexString(s)))
+		_, err := dec.Decode()
+		assert.Error(t, err, s)
+		assert.NotErrorIs(t, err, io.EOF, s)
+	}
+}
@@ -0,0 +1,124 @@
+package axdr
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagOptions are the options of an axdr struct tag, which has the form
+// `axdr:"type,omitnull"`. The type is the name of a DLMS data type (e.g. enum,
+// long_unsigned, visible_string or date_time); for slices and arrays it applies to
+// their elements unless it is structure, array or compact_array. The omitnull option leaves nil
+// fields out when marshaling and lets them be missing at the end of a structure
+// when unmarshaling. Fields tagged with "-" are ignored.
+type tagOptions struct {
+	dataType dataTag
+	name     string
+	omitNull bool
+	skip     bool
+}
+
+func tagNames() map[string]dataTag {
+	return map[string]dataTag{
+		"array":                TagArray,
+		"structure":            TagStructure,
+		"compact_array":        TagCompactArray,
+		"boolean":              TagBoolean,
+		"bit_string":           TagBitString,
+		"double_long":          TagDoubleLong,
+		"double_long_unsigned": TagDoubleLongUnsigned,
+		"octet_string":         TagOctetString,
+		"visible_string":       TagVisibleString,
+		"utf8_string":          TagUTF8String,
+		"integer":              TagInteger,
+		"long":                 TagLong,
+		"unsigned":             TagUnsigned,
+		"long_unsigned":        TagLongUnsigned,
+		"long64":               TagLong64,
+		"long64_unsigned":      TagLong64Unsigned,
+		"enum":                 TagEnum,
+		"float32":              TagFloat32,
+		"float64":              TagFloat64,
+		"date_time":            TagDateTime,
+		"date":                 TagDate,
+		"time":                 TagTime,
+	}
+}
+
+// tagName returns the name of a DLMS data type, as used in axdr tags.
+func tagName(t dataTag) string {
+	for name, dataType := range tagNames() {
+		if dataType == t {
+			return name
+		}
+	}
+
+	if t == TagNull {
+		return "null_data"
+	}
+
+	return fmt.Sprintf("type %d", t)
+}
+
+func parseTag(tag string) (opts tagOptions, err error) {
+	if tag == "-" {
+		opts.skip = true
+		return
+	}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] != "" {
+		dataType, ok := tagNames()[parts[0]]
+		if !ok {
+			return opts, fmt.Errorf("unknown axdr type %q", parts[0])
+		}
+		opts.dataType = dataType
+		opts.name = parts[0]
+	}
+
+	for _, option := range parts[1:] {
+		switch option {
+		case "omitnull":
+			opts.omitNull = true
+		default:
+			return opts, fmt.Errorf("unknown axdr option %q", option)
+		}
+	}
+
+	return
+}
+
+func (t tagOptions) isContainer() bool {
+	return t.dataType == TagArray || t.dataType == TagStructure || t.dataType == TagCompactArray
+}
+
+// element returns the options of the elements of a slice or array with these options.
+func (t tagOptions) element() tagOptions {
+	if t.isContainer() {
+		return tagOptions{}
+	}
+
+	return tagOptions{dataType: t.dataType, name: t.name}
+}
+
+// appliesTo returns whether the type given by the tag is the one of the values of
+// type typ, instead of the one of their elements.
+func (t tagOptions) appliesTo(typ reflect.Type) bool {
+	if t.dataType == 0 || t.isContainer() {
+		return false
+	}
+
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice:
+		return typ.Elem().Kind() == reflect.Uint8 && t.dataType == TagOctetString
+	case reflect.Array:
+		return false
+	default:
+		return true
+	}
+}
@@ -3,48 +3,78 @@ package axdr
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 )
 
+// Unmarshaler is implemented by types that unmarshal themselves from DlmsData.
+type Unmarshaler interface {
+	UnmarshalAXDR(data DlmsData) error
+}
+
+// UnmarshalData stores data into the value pointed by v. Types implementing
+// Unmarshaler (through a pointer) are decoded by their UnmarshalAXDR method.
 func UnmarshalData(data DlmsData, v interface{}) error {
+	return UnmarshalDataWithTimeZone(data, v, TimeZoneDeviation)
+}
+
+// UnmarshalDataWithTimeZone is UnmarshalData interpreting the deviation of
+// date-times according to tz.
+func UnmarshalDataWithTimeZone(data DlmsData, v interface{}, tz TimeZone) error {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return fmt.Errorf("v must be a non-nil pointer")
 	}
 
-	return unify(&data, reflect.Indirect(rv))
+	return unify(&data, reflect.Indirect(rv), tz, tagOptions{})
 }
 
-func unify(data *DlmsData, rv reflect.Value) error {
+// unify sets rv from data, which must be of the type given by the tag, if any.
+func unify(data *DlmsData, rv reflect.Value, tz TimeZone, tag tagOptions) error {
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() {
+		if u, ok := rv.Addr().Interface().(Unmarshaler); ok {
+			return u.UnmarshalAXDR(*data)
+		}
+	}
+
+	if tag.appliesTo(rv.Type()) && rv.Kind() != reflect.Ptr {
+		return unifyTagged(data, rv, tz, tag)
+	}
+
 	expectedKind := rv.Kind()
 	gotKind := reflect.ValueOf(data.Value).Kind()
 
 	_, isTime := rv.Interface().(time.Time)
 	_, isDlmsData := rv.Interface().(DlmsData)
+	_, isCosemDateTime := rv.Interface().(CosemDateTime)
+	_, isCosemDate := rv.Interface().(CosemDate)
+	_, isCosemTime := rv.Interface().(CosemTime)
 
 	switch {
 	case expectedKind == reflect.Ptr:
 		elem := reflect.New(rv.Type().Elem())
-		err := unify(data, reflect.Indirect(elem))
+		err := unify(data, reflect.Indirect(elem), tz, tag)
 		if err != nil {
 			return err
 		}
 		rv.Set(elem)
 	case isDlmsData:
 		rv.Set(reflect.ValueOf(*data))
+	case (isCosemDateTime || isCosemDate || isCosemTime) && gotKind == reflect.String:
+		return unifyCosemDateTime(data, rv)
 	case expectedKind == reflect.Slice && gotKind == reflect.Slice:
-		return unifySlice(data, rv)
+		return unifySlice(data, rv, tz, tag.element())
 	case expectedKind == reflect.Struct && gotKind == reflect.Slice:
-		return unifyStruct(data, rv)
+		return unifyStruct(data, rv, tz)
 	case expectedKind == reflect.Int && (gotKind >= reflect.Int && gotKind <= reflect.Int64):
 		return unifyInt(data, rv)
 	case expectedKind == reflect.Uint && (gotKind >= reflect.Uint && gotKind <= reflect.Uint64):
 		return unifyUint(data, rv)
 	case isTime && gotKind == reflect.String:
-		return unifyDateTime(data, rv)
+		return unifyDateTime(data, rv, tz)
 	case expectedKind == gotKind:
-		rv.Set(reflect.ValueOf(data.Value))
+		return unifyValue(data, rv)
 	default:
 		return fmt.Errorf("expected %s, got %s", expectedKind, gotKind)
 	}
@@ -52,13 +82,29 @@ func unify(data *DlmsData, rv reflect.Value) error {
 	return nil
 }
 
-func unifyDateTime(data *DlmsData, rv reflect.Value) error {
+// unifyValue sets a value of the same kind, converting it when the destination is a
+// named type (e.g. an enumeration defined as uint8).
+func unifyValue(data *DlmsData, rv reflect.Value) error {
+	value := reflect.ValueOf(data.Value)
+	if value.Type() != rv.Type() {
+		if !value.Type().ConvertibleTo(rv.Type()) {
+			return fmt.Errorf("cannot convert %s to %s", value.Type(), rv.Type())
+		}
+		value = value.Convert(rv.Type())
+	}
+
+	rv.Set(value)
+
+	return nil
+}
+
+func unifyDateTime(data *DlmsData, rv reflect.Value, tz TimeZone) error {
 	v, err := hex.DecodeString(data.Value.(string))
 	if err != nil {
 		return fmt.Errorf("invalid date time: %w", err)
 	}
 
-	_, t, err := DecodeDateTime(&v)
+	_, t, err := DecodeDateTimeWithTimeZone(&v, tz)
 	if err != nil {
 		return fmt.Errorf("invalid date time: %w", err)
 	}
@@ -67,7 +113,140 @@ func unifyDateTime(data *DlmsData, rv reflect.Value) error {
 	return nil
 }
 
-func unifySlice(data *DlmsData, rv reflect.Value) error {
+// unifyCosemDateTime sets a CosemDateTime, CosemDate or CosemTime from its octet-string
+func unifyCosemDateTime(data *DlmsData, rv reflect.Value) error {
+	v, err := hex.DecodeString(data.Value.(string))
+	if err != nil {
+		return fmt.Errorf("invalid date time: %w", err)
+	}
+
+	var value interface{}
+	length := len(v)
+
+	switch rv.Interface().(type) {
+	case CosemDate:
+		_, value, err = DecodeCosemDate(&v)
+	case CosemTime:
+		_, value, err = DecodeCosemTime(&v)
+	default:
+		_, value, err = DecodeCosemDateTime(&v)
+	}
+
+	if err != nil || len(v) != 0 {
+		return fmt.Errorf("invalid %s length %d", rv.Type().Name(), length)
+	}
+	rv.Set(reflect.ValueOf(value))
+
+	return nil
+}
+
+// unifyTagged sets a value whose type is given by the tag, checking the type of the data.
+func unifyTagged(data *DlmsData, rv reflect.Value, tz TimeZone, tag tagOptions) error {
+	switch tag.dataType {
+	case TagDate, TagTime, TagDateTime:
+		// Dates and times are usually sent as octet-strings
+		if data.Tag == tag.dataType {
+			if _, ok := rv.Interface().(time.Time); ok {
+				return unifyValue(data, rv)
+			}
+		}
+
+		if data.Tag != TagOctetString {
+			return fmt.Errorf("expected %s, got %s", tag.name, tagName(data.Tag))
+		}
+
+		if _, ok := rv.Interface().(time.Time); ok {
+			return unifyTaggedTime(data, rv, tz, tag)
+		}
+	case TagOctetString:
+		if data.Tag != TagOctetString {
+			return fmt.Errorf("expected %s, got %s", tag.name, tagName(data.Tag))
+		}
+
+		if rv.Kind() == reflect.Slice {
+			v, err := hex.DecodeString(data.Value.(string))
+			if err != nil {
+				return fmt.Errorf("invalid octet-string: %w", err)
+			}
+			rv.SetBytes(v)
+
+			return nil
+		}
+	default:
+		if data.Tag != tag.dataType {
+			return fmt.Errorf("expected %s, got %s", tag.name, tagName(data.Tag))
+		}
+
+		if rv.Kind() >= reflect.Int && rv.Kind() <= reflect.Uint64 {
+			return unifyTaggedNumber(data, rv)
+		}
+	}
+
+	return unify(data, rv, tz, tagOptions{})
+}
+
+// unifyTaggedNumber sets an integer of any kind, checking that the value fits in it.
+func unifyTaggedNumber(data *DlmsData, rv reflect.Value) error {
+	value := reflect.ValueOf(data.Value)
+
+	switch {
+	case value.CanInt():
+		v := value.Int()
+		if rv.CanInt() && !rv.OverflowInt(v) {
+			rv.SetInt(v)
+			return nil
+		}
+
+		if rv.CanUint() && v >= 0 && !rv.OverflowUint(uint64(v)) {
+			rv.SetUint(uint64(v))
+			return nil
+		}
+	case value.CanUint():
+		v := value.Uint()
+		if rv.CanUint() && !rv.OverflowUint(v) {
+			rv.SetUint(v)
+			return nil
+		}
+
+		if rv.CanInt() && v <= math.MaxInt64 && !rv.OverflowInt(int64(v)) {
+			rv.SetInt(int64(v))
+			return nil
+		}
+	default:
+		return fmt.Errorf("unexpected type %T", data.Value)
+	}
+
+	return fmt.Errorf("%v out of range of %s", data.Value, rv.Type())
+}
+
+// unifyTaggedTime sets a time.Time from the octet-string of a date, time or date-time.
+func unifyTaggedTime(data *DlmsData, rv reflect.Value, tz TimeZone, tag tagOptions) error {
+	v, err := hex.DecodeString(data.Value.(string))
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", tag.name, err)
+	}
+
+	length := len(v)
+
+	var t time.Time
+	switch tag.dataType {
+	case TagDate:
+		_, t, err = DecodeDate(&v)
+	case TagTime:
+		_, t, err = DecodeTime(&v)
+	default:
+		_, t, err = DecodeDateTimeWithTimeZone(&v, tz)
+	}
+
+	if err != nil || len(v) != 0 {
+		return fmt.Errorf("invalid %s length %d", tag.name, length)
+	}
+	rv.Set(reflect.ValueOf(t))
+
+	return nil
+}
+
+func unifySlice(data *DlmsData, rv reflect.Value, tz TimeZone, tag tagOptions) error {
 	slice := data.Value.([]*DlmsData)
 
 	n := len(slice)
@@ -78,7 +257,7 @@ func unifySlice(data *DlmsData, rv reflect.Value) error {
 
 	for i := 0; i < n; i++ {
 		sliceval := reflect.Indirect(rv.Index(i))
-		if err := unify(slice[i], sliceval); err != nil {
+		if err := unify(slice[i], sliceval, tz, tag); err != nil {
 			return fmt.Errorf("slice error in field %d: %w", i, err)
 		}
 	}
@@ -86,30 +265,54 @@ func unifySlice(data *DlmsData, rv reflect.Value) error {
 	return nil
 }
 
-func unifyStruct(data *DlmsData, rv reflect.Value) error {
+// unifyStruct sets the fields of a struct from the elements of a structure, in
+// order. Fields tagged with "-" are ignored, and trailing omitnull fields may be
+// missing in the data.
+func unifyStruct(data *DlmsData, rv reflect.Value, tz TimeZone) error {
 	slice := data.Value.([]*DlmsData)
 	n := len(slice)
 
-	if rv.NumField() != n {
-		return fmt.Errorf("struct has %d fields, but data has %d fields", rv.NumField(), n)
+	fields := make([]int, 0, rv.NumField())
+	tags := make([]tagOptions, 0, rv.NumField())
+	for i := 0; i < rv.NumField(); i++ {
+		tag, err := parseTag(rv.Type().Field(i).Tag.Get("axdr"))
+		if err != nil {
+			return fmt.Errorf("struct error in field %s: %w", rv.Type().Field(i).Name, err)
+		}
+
+		if !tag.skip {
+			fields = append(fields, i)
+			tags = append(tags, tag)
+		}
 	}
 
-	for i := 0; i < n; i++ {
-		field := rv.Field(i)
+	if len(fields) < n {
+		return fmt.Errorf("struct has %d fields, but data has %d fields", len(fields), n)
+	}
+
+	for j := n; j < len(fields); j++ {
+		if !tags[j].omitNull {
+			return fmt.Errorf("struct has %d fields, but data has %d fields", len(fields), n)
+		}
+		rv.Field(fields[j]).Set(reflect.Zero(rv.Field(fields[j]).Type()))
+	}
+
+	for j := 0; j < n; j++ {
+		field := rv.Field(fields[j])
 
 		if field.Kind() == reflect.Ptr {
-			if slice[i].Tag != TagNull && field.IsNil() {
+			if slice[j].Tag != TagNull && field.IsNil() {
 				field.Set(reflect.New(field.Type().Elem()))
 			}
 
-			if slice[i].Tag == TagNull && !field.IsNil() {
+			if slice[j].Tag == TagNull && !field.IsNil() {
 				field.Set(reflect.Zero(field.Type()))
 			}
 		}
 
-		if slice[i].Tag != TagNull {
-			if err := unify(slice[i], reflect.Indirect(field)); err != nil {
-				return fmt.Errorf("struct error in field %s: %w", rv.Type().Field(i).Name, err)
+		if slice[j].Tag != TagNull {
+			if err := unify(slice[j], reflect.Indirect(field), tz, tags[j]); err != nil {
+				return fmt.Errorf("struct error in field %s: %w", rv.Type().Field(fields[j]).Name, err)
 			}
 		}
 	}
@@ -35,6 +35,28 @@ func TestUnmarshalData(t *testing.T) {
 	assert.Equal(t, uint(0x02), result[1].Value3)
 }
 
+func TestUnmarshalDataWithNamedTypes(t *testing.T) {
+	type Mode uint8
+	type Name string
+
+	type TestData struct {
+		Mode  Mode
+		Name  Name
+		Modes []Mode
+	}
+
+	data := CreateAxdrStructure([]*DlmsData{
+		CreateAxdrEnum(3),
+		CreateAxdrVisibleString("meter"),
+		CreateAxdrArray([]*DlmsData{CreateAxdrEnum(1), CreateAxdrEnum(2)}),
+	})
+
+	var result TestData
+	err := UnmarshalData(*data, &result)
+	assert.NoError(t, err)
+	assert.Equal(t, TestData{Mode: 3, Name: "meter", Modes: []Mode{1, 2}}, result)
+}
+
 func TestUnmarshalDataWithNull(t *testing.T) {
 	type TestData struct {
 		Value1 *uint16
@@ -125,3 +147,100 @@ func decodeHexString(s string) []byte {
 	b, _ := hex.DecodeString(s)
 	return b
 }
+
+func TestUnmarshalDataWithTags(t *testing.T) {
+	type tagged struct {
+		Unit     int       `axdr:"enum"`
+		Name     string    `axdr:"visible_string"`
+		Password []byte    `axdr:"octet_string"`
+		Day      time.Time `axdr:"date"`
+		Start    time.Time `axdr:"time"`
+		Modes    []uint8   `axdr:"enum"`
+		Internal string    `axdr:"-"`
+		Value    *uint16   `axdr:"long_unsigned,omitnull"`
+	}
+
+	data := CreateAxdrStructure([]*DlmsData{
+		CreateAxdrEnum(30),
+		CreateAxdrVisibleString("meter"),
+		CreateAxdrOctetString("12ab"),
+		CreateAxdrOctetString("07e8030a07"),
+		CreateAxdrOctetString("061e0000"),
+		CreateAxdrArray([]*DlmsData{CreateAxdrEnum(1), CreateAxdrEnum(2)}),
+	})
+
+	result := tagged{Internal: "kept"}
+	err := UnmarshalData(*data, &result)
+	require.NoError(t, err)
+	assert.Equal(t, tagged{
+		Unit:     30,
+		Name:     "meter",
+		Password: []byte{0x12, 0xab},
+		Day:      time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		Start:    time.Date(0, 1, 1, 6, 30, 0, 0, time.UTC),
+		Modes:    []uint8{1, 2},
+		Internal: "kept",
+	}, result)
+
+	// Trailing omitnull field present
+	elements := data.Value.([]*DlmsData)
+	data = CreateAxdrStructure(append(elements, CreateAxdrLongUnsigned(7)))
+	err = UnmarshalData(*data, &result)
+	require.NoError(t, err)
+	require.NotNil(t, result.Value)
+	assert.Equal(t, uint16(7), *result.Value)
+
+	// Data of another type than the tag
+	var wrongType struct {
+		Unit uint8 `axdr:"enum"`
+	}
+	err = UnmarshalData(*CreateAxdrStructure([]*DlmsData{CreateAxdrUnsigned(1)}), &wrongType)
+	assert.ErrorContains(t, err, "expected enum, got unsigned")
+
+	// Missing field not omitnull
+	var missing struct {
+		Unit  uint8 `axdr:"enum"`
+		Value uint8
+	}
+	err = UnmarshalData(*CreateAxdrStructure([]*DlmsData{CreateAxdrEnum(1)}), &missing)
+	assert.Error(t, err)
+
+	// Date of invalid length
+	var date struct {
+		Day time.Time `axdr:"date"`
+	}
+	err = UnmarshalData(*CreateAxdrStructure([]*DlmsData{CreateAxdrOctetString("07e8030a")}), &date)
+	assert.Error(t, err)
+}
+
+func TestUnmarshalDataWithUnmarshaler(t *testing.T) {
+	var flags testFlags
+	err := UnmarshalData(*CreateAxdrBitString("10100000"), &flags)
+	require.NoError(t, err)
+	assert.Equal(t, testFlags(0xA0), flags)
+
+	var result struct {
+		Flags   testFlags
+		List    []testFlags
+		Pointer *testFlags
+	}
+	data := CreateAxdrStructure([]*DlmsData{
+		CreateAxdrBitString("00000001"),
+		CreateAxdrArray([]*DlmsData{CreateAxdrBitString("10000000"), CreateAxdrBitString("00000010")}),
+		CreateAxdrBitString("11111111"),
+	})
+	err = UnmarshalData(*data, &result)
+	require.NoError(t, err)
+	assert.Equal(t, testFlags(0x01), result.Flags)
+	assert.Equal(t, []testFlags{0x80, 0x02}, result.List)
+	require.NotNil(t, result.Pointer)
+	assert.Equal(t, testFlags(0xFF), *result.Pointer)
+
+	// The error of the unmarshaler is returned
+	err = UnmarshalData(*CreateAxdrStructure([]*DlmsData{
+		CreateAxdrUnsigned(1),
+		CreateAxdrArray(nil),
+		CreateAxdrNull(),
+	}), &result)
+	assert.ErrorContains(t, err, "flags must be a bit-string of 8 bits")
+}
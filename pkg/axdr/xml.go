@@ -0,0 +1,469 @@
+package axdr
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// xmlNames are the names of the elements of the DLMS data types in the XML
+// representation of the Green Book
+func xmlNames() map[dataTag]string {
+	return map[dataTag]string{
+		TagNull:               "NullData",
+		TagArray:              "Array",
+		TagStructure:          "Structure",
+		TagBoolean:            "Boolean",
+		TagBitString:          "BitString",
+		TagDoubleLong:         "DoubleLong",
+		TagDoubleLongUnsigned: "DoubleLongUnsigned",
+		TagFloatingPoint:      "FloatingPoint",
+		TagOctetString:        "OctetString",
+		TagVisibleString:      "VisibleString",
+		TagUTF8String:         "Utf8String",
+		TagBCD:                "Bcd",
+		TagInteger:            "Integer",
+		TagLong:               "Long",
+		TagUnsigned:           "Unsigned",
+		TagLongUnsigned:       "LongUnsigned",
+		TagCompactArray:       "CompactArray",
+		TagLong64:             "Long64",
+		TagLong64Unsigned:     "Long64Unsigned",
+		TagEnum:               "Enum",
+		TagFloat32:            "Float32",
+		TagFloat64:            "Float64",
+		TagDateTime:           "DateTime",
+		TagDate:               "Date",
+		TagTime:               "Time",
+	}
+}
+
+func xmlTag(name string) (dataTag, error) {
+	for tag, n := range xmlNames() {
+		if n == name {
+			return tag, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown DLMS data type %q", name)
+}
+
+// MarshalXML encodes d as the element of its type in the XML representation of
+// the Green Book, e.g. <LongUnsigned Value="00E6"></LongUnsigned>. The name of
+// start is not used. The Value attribute holds the hex contents of the A-XDR
+// encoding, without tag and length, except for booleans, given as true or false,
+// and bit-strings, given as their bits (e.g. 0101). Arrays and structures hold
+// their elements, and their number in the hex Qty attribute. Compact arrays hold
+// the ContentsDescription, with the element of the type description, and the hex
+// ArrayContents.
+func (d DlmsData) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	name, ok := xmlNames()[d.Tag]
+	if !ok {
+		return fmt.Errorf("type %d cannot be encoded to XML", d.Tag)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	switch d.Tag {
+	case TagNull:
+		return emptyXMLElement(e, start)
+	case TagArray, TagStructure:
+		elements, ok := d.Value.([]*DlmsData)
+		if !ok {
+			return fmt.Errorf("cannot encode value %v with tag %v", d.Value, d.Tag)
+		}
+
+		start.Attr = []xml.Attr{xmlQty(len(elements))}
+		if err := e.EncodeToken(start); err != nil {
+			return err
+		}
+
+		for i, element := range elements {
+			if element == nil {
+				return fmt.Errorf("element %d of %s is nil", i, name)
+			}
+
+			if err := element.MarshalXML(e, xml.StartElement{}); err != nil {
+				return err
+			}
+		}
+
+		return e.EncodeToken(start.End())
+	case TagCompactArray:
+		return d.marshalCompactArrayXML(e, start)
+	case TagBoolean:
+		value, ok := d.Value.(bool)
+		if !ok {
+			return fmt.Errorf("cannot encode value %v with tag %v", d.Value, d.Tag)
+		}
+
+		start.Attr = []xml.Attr{xmlValue(strconv.FormatBool(value))}
+	case TagBitString:
+		if _, err := d.Encode(); err != nil {
+			return err
+		}
+
+		start.Attr = []xml.Attr{xmlValue(strings.ReplaceAll(d.Value.(string), " ", ""))}
+	default:
+		encoded, err := d.Encode()
+		if err != nil {
+			return err
+		}
+
+		contents := encoded[1:]
+		if xmlHasLength(d.Tag) {
+			if _, _, err := DecodeLength(&contents); err != nil {
+				return err
+			}
+		}
+
+		start.Attr = []xml.Attr{xmlValue(strings.ToUpper(hex.EncodeToString(contents)))}
+	}
+
+	return emptyXMLElement(e, start)
+}
+
+func (d DlmsData) marshalCompactArrayXML(e *xml.Encoder, start xml.StartElement) error {
+	encoded, err := d.Encode()
+	if err != nil {
+		return err
+	}
+
+	contents := encoded[1:]
+	td, err := DecodeTypeDescription(&contents)
+	if err != nil {
+		return err
+	}
+
+	if _, _, err = DecodeLength(&contents); err != nil {
+		return err
+	}
+
+	if err = e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	description := xml.StartElement{Name: xml.Name{Local: "ContentsDescription"}}
+	if err = e.EncodeToken(description); err != nil {
+		return err
+	}
+
+	if err = td.marshalXML(e); err != nil {
+		return err
+	}
+
+	if err = e.EncodeToken(description.End()); err != nil {
+		return err
+	}
+
+	err = emptyXMLElement(e, xml.StartElement{
+		Name: xml.Name{Local: "ArrayContents"},
+		Attr: []xml.Attr{xmlValue(strings.ToUpper(hex.EncodeToString(contents)))},
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// marshalXML encodes the type description as the elements of the types, where
+// arrays have the number of elements in the Qty attribute.
+func (td TypeDescription) marshalXML(e *xml.Encoder) error {
+	name, ok := xmlNames()[td.Tag]
+	if !ok {
+		return fmt.Errorf("type %d cannot be encoded to XML", td.Tag)
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	switch td.Tag {
+	case TagArray:
+		start.Attr = []xml.Attr{xmlQty(int(td.Count))}
+	case TagStructure:
+		start.Attr = []xml.Attr{xmlQty(len(td.Elements))}
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, element := range td.Elements {
+		if err := element.marshalXML(e); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// UnmarshalXML decodes the element given by MarshalXML. The values are decoded as
+// their A-XDR encoding, so they must have the length of their type.
+func (d *DlmsData) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	tag, err := xmlTag(start.Name.Local)
+	if err != nil {
+		return err
+	}
+
+	switch tag {
+	case TagArray, TagStructure:
+		return d.unmarshalElementsXML(dec, start, tag)
+	case TagCompactArray:
+		return d.unmarshalCompactArrayXML(dec, start)
+	case TagNull:
+		*d = *CreateAxdrNull()
+		return dec.Skip()
+	}
+
+	value, ok := xmlAttr(start, "Value")
+	if !ok {
+		return fmt.Errorf("%s without value", start.Name.Local)
+	}
+
+	switch tag {
+	case TagBoolean:
+		b, err := xmlBool(value)
+		if err != nil {
+			return err
+		}
+
+		*d = *CreateAxdrBoolean(b)
+	case TagBitString:
+		if _, err := EncodeBitString(value); err != nil {
+			return err
+		}
+
+		*d = *CreateAxdrBitString(value)
+	default:
+		contents, err := hex.DecodeString(strings.ReplaceAll(value, " ", ""))
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q: %w", start.Name.Local, value, err)
+		}
+
+		src := contents
+		if xmlHasLength(tag) {
+			length, err := EncodeLength(len(contents))
+			if err != nil {
+				return err
+			}
+			src = append(length, contents...)
+		}
+
+		if *d, err = decodeXMLContents(tag, src); err != nil {
+			return fmt.Errorf("invalid %s value %q: %w", start.Name.Local, value, err)
+		}
+	}
+
+	return dec.Skip()
+}
+
+func (d *DlmsData) unmarshalElementsXML(dec *xml.Decoder, start xml.StartElement, tag dataTag) error {
+	elements := make([]*DlmsData, 0)
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			var element DlmsData
+			if err := element.UnmarshalXML(dec, t); err != nil {
+				return err
+			}
+			elements = append(elements, &element)
+		case xml.EndElement:
+			if err := checkXMLQty(start, len(elements)); err != nil {
+				return err
+			}
+
+			*d = DlmsData{Tag: tag, Value: elements}
+
+			return nil
+		}
+	}
+}
+
+func (d *DlmsData) unmarshalCompactArrayXML(dec *xml.Decoder, start xml.StartElement) error {
+	var td *TypeDescription
+	var contents []byte
+	var haveContents bool
+
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "ContentsDescription":
+				description, err := unmarshalTypeDescriptionsXML(dec)
+				if err != nil {
+					return err
+				}
+
+				if len(description) != 1 {
+					return fmt.Errorf("contents description with %d types", len(description))
+				}
+				td = &description[0]
+			case "ArrayContents":
+				value, _ := xmlAttr(t, "Value")
+				if contents, err = hex.DecodeString(strings.ReplaceAll(value, " ", "")); err != nil {
+					return fmt.Errorf("invalid array contents: %w", err)
+				}
+				haveContents = true
+
+				if err = dec.Skip(); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unexpected %s in %s", t.Name.Local, start.Name.Local)
+			}
+		case xml.EndElement:
+			if td == nil || !haveContents {
+				return fmt.Errorf("%s without contents description or array contents", start.Name.Local)
+			}
+
+			src, err := td.Encode()
+			if err != nil {
+				return err
+			}
+
+			length, err := EncodeLength(len(contents))
+			if err != nil {
+				return err
+			}
+			src = append(append(src, length...), contents...)
+
+			*d, err = decodeXMLContents(TagCompactArray, src)
+
+			return err
+		}
+	}
+}
+
+// unmarshalTypeDescriptionsXML decodes the type descriptions until the end of
+// their parent element.
+func unmarshalTypeDescriptionsXML(dec *xml.Decoder) ([]TypeDescription, error) {
+	var tds []TypeDescription
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			tag, err := xmlTag(t.Name.Local)
+			if err != nil {
+				return nil, err
+			}
+
+			td := TypeDescription{Tag: tag}
+			if td.Elements, err = unmarshalTypeDescriptionsXML(dec); err != nil {
+				return nil, err
+			}
+
+			switch tag {
+			case TagArray:
+				qty, ok := xmlAttr(t, "Qty")
+				count, err := strconv.ParseUint(qty, 16, 16)
+				if !ok || err != nil {
+					return nil, fmt.Errorf("array type without valid quantity")
+				}
+				td.Count = uint16(count)
+
+				if len(td.Elements) != 1 {
+					return nil, fmt.Errorf("array type with %d types", len(td.Elements))
+				}
+			case TagStructure:
+				if err := checkXMLQty(t, len(td.Elements)); err != nil {
+					return nil, err
+				}
+			default:
+				if len(td.Elements) > 0 {
+					return nil, fmt.Errorf("unexpected types in %s", t.Name.Local)
+				}
+				td.Elements = nil
+			}
+
+			tds = append(tds, td)
+		case xml.EndElement:
+			return tds, nil
+		}
+	}
+}
+
+// decodeXMLContents decodes src, the A-XDR encoding without the tag, which must be
+// all of it.
+func decodeXMLContents(tag dataTag, src []byte) (DlmsData, error) {
+	dec := Decoder{tag: tag}
+	data, err := dec.Decode(&src)
+	if err != nil {
+		return DlmsData{}, err
+	}
+
+	if len(src) > 0 {
+		return DlmsData{}, fmt.Errorf("%d bytes too long", len(src))
+	}
+
+	return data, nil
+}
+
+func xmlHasLength(tag dataTag) bool {
+	return tag == TagOctetString || tag == TagVisibleString || tag == TagUTF8String
+}
+
+func xmlValue(value string) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: "Value"}, Value: value}
+}
+
+func xmlQty(n int) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: "Qty"}, Value: fmt.Sprintf("%02X", n)}
+}
+
+func xmlAttr(start xml.StartElement, name string) (string, bool) {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// checkXMLQty checks the Qty attribute, if any, of an element with n elements.
+func checkXMLQty(start xml.StartElement, n int) error {
+	qty, ok := xmlAttr(start, "Qty")
+	if !ok {
+		return nil
+	}
+
+	if v, err := strconv.ParseUint(qty, 16, 32); err != nil || v != uint64(n) {
+		return fmt.Errorf("%s with quantity %s and %d elements", start.Name.Local, qty, n)
+	}
+
+	return nil
+}
+
+func xmlBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "01":
+		return true, nil
+	case "false", "00":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid boolean %q", value)
+	}
+}
+
+func emptyXMLElement(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	return e.EncodeToken(start.End())
+}
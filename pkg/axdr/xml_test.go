@@ -0,0 +1,119 @@
+package axdr
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDlmsDataXML(t *testing.T) {
+	tests := []struct {
+		name string
+		data *DlmsData
+		xml  string
+	}{
+		{"null", CreateAxdrNull(), `<NullData></NullData>`},
+		{"boolean", CreateAxdrBoolean(true), `<Boolean Value="true"></Boolean>`},
+		{"bit string", CreateAxdrBitString("0101"), `<BitString Value="0101"></BitString>`},
+		{"double long", CreateAxdrDoubleLong(-2), `<DoubleLong Value="FFFFFFFE"></DoubleLong>`},
+		{"double long unsigned", CreateAxdrDoubleLongUnsigned(10000), `<DoubleLongUnsigned Value="00002710"></DoubleLongUnsigned>`},
+		{"floating point", CreateAxdrFloatingPoint(1), `<FloatingPoint Value="3F800000"></FloatingPoint>`},
+		{"octet string", CreateAxdrOctetString("0000010000ff"), `<OctetString Value="0000010000FF"></OctetString>`},
+		{"visible string", CreateAxdrVisibleString("ab"), `<VisibleString Value="6162"></VisibleString>`},
+		{"utf8 string", CreateAxdrUTF8String("à"), `<Utf8String Value="C3A0"></Utf8String>`},
+		{"bcd", CreateAxdrBCD(12), `<Bcd Value="0C"></Bcd>`},
+		{"integer", CreateAxdrInteger(-1), `<Integer Value="FF"></Integer>`},
+		{"long", CreateAxdrLong(-300), `<Long Value="FED4"></Long>`},
+		{"unsigned", CreateAxdrUnsigned(200), `<Unsigned Value="C8"></Unsigned>`},
+		{"long unsigned", CreateAxdrLongUnsigned(230), `<LongUnsigned Value="00E6"></LongUnsigned>`},
+		{"long64", CreateAxdrLong64(-1), `<Long64 Value="FFFFFFFFFFFFFFFF"></Long64>`},
+		{"long64 unsigned", CreateAxdrLong64Unsigned(1), `<Long64Unsigned Value="0000000000000001"></Long64Unsigned>`},
+		{"enum", CreateAxdrEnum(30), `<Enum Value="1E"></Enum>`},
+		{"float32", CreateAxdrFloat32(1), `<Float32 Value="3F800000"></Float32>`},
+		{"float64", CreateAxdrFloat64(1), `<Float64 Value="3FF0000000000000"></Float64>`},
+		{"date", CreateAxdrDate(time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)), `<Date Value="07E8030A07"></Date>`},
+		{"structure", CreateAxdrStructure([]*DlmsData{
+			CreateAxdrArray([]*DlmsData{CreateAxdrUnsigned(1), CreateAxdrUnsigned(2)}),
+			CreateAxdrStructure([]*DlmsData{}),
+		}), `<Structure Qty="02"><Array Qty="02"><Unsigned Value="01"></Unsigned><Unsigned Value="02"></Unsigned></Array>` +
+			`<Structure Qty="00"></Structure></Structure>`},
+		{"compact array", CreateAxdrCompactArray([]*DlmsData{
+			CreateAxdrStructure([]*DlmsData{CreateAxdrLongUnsigned(1), CreateAxdrOctetString("01")}),
+			CreateAxdrStructure([]*DlmsData{CreateAxdrLongUnsigned(2), CreateAxdrOctetString("0203")}),
+		}), `<CompactArray><ContentsDescription><Structure Qty="02"><LongUnsigned></LongUnsigned><OctetString></OctetString></Structure></ContentsDescription>` +
+			`<ArrayContents Value="000101010002020203"></ArrayContents></CompactArray>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data DlmsData
+			require.NoError(t, xml.Unmarshal([]byte(tt.xml), &data))
+
+			want, err := tt.data.Encode()
+			require.NoError(t, err)
+			got, err := data.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+
+			out, err := xml.Marshal(tt.data)
+			require.NoError(t, err)
+
+			var decoded DlmsData
+			require.NoError(t, xml.Unmarshal(out, &decoded))
+			got, err = decoded.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestDlmsDataXMLIndent(t *testing.T) {
+	data := CreateAxdrStructure([]*DlmsData{CreateAxdrOctetString("0000010000ff"), CreateAxdrBoolean(false)})
+
+	out, err := xml.MarshalIndent(data, "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, `<Structure Qty="02">
+  <OctetString Value="0000010000FF"></OctetString>
+  <Boolean Value="false"></Boolean>
+</Structure>`, string(out))
+
+	// Comments and self-closing elements are allowed
+	var decoded DlmsData
+	require.NoError(t, xml.Unmarshal([]byte(`<Structure Qty="02">
+  <!-- 0.0.1.0.0.255 -->
+  <OctetString Value="0000010000FF" />
+  <Boolean Value="00" />
+</Structure>`), &decoded))
+	assert.Equal(t, *data, decoded)
+}
+
+func TestDlmsDataXMLFail(t *testing.T) {
+	for _, s := range []string{
+		`<Unknown Value="00"/>`,
+		`<Unsigned/>`,
+		`<Unsigned Value="0001"/>`,
+		`<LongUnsigned Value="01"/>`,
+		`<OctetString Value="0x01"/>`,
+		`<Boolean Value="yes"/>`,
+		`<BitString Value="012"/>`,
+		`<Array Qty="02"><Unsigned Value="01"/></Array>`,
+		`<Array><Unknown/></Array>`,
+		`<Array>`,
+		`<CompactArray><ArrayContents Value="01"/></CompactArray>`,
+		`<CompactArray><ContentsDescription><Unsigned/></ContentsDescription><ArrayContents Value="0102 03" /><Other/></CompactArray>`,
+		`<CompactArray><ContentsDescription><Array><Unsigned/></Array></ContentsDescription><ArrayContents Value=""/></CompactArray>`,
+		`<CompactArray><ContentsDescription><LongUnsigned/></ContentsDescription><ArrayContents Value="010203"/></CompactArray>`,
+	} {
+		var data DlmsData
+		assert.Error(t, xml.Unmarshal([]byte(s), &data), s)
+	}
+
+	_, err := xml.Marshal(DlmsData{Tag: TagDontCare})
+	assert.Error(t, err)
+
+	_, err = xml.Marshal(DlmsData{Tag: TagUnsigned, Value: "1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,297 @@
+package cosem
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Activity Calendar (class ID 20, version 0) runs scripts (e.g. tariff changes) at
+// the times given by seasons, weeks and days. It has an active calendar in use and
+// a passive one, which replaces it when activated.
+const (
+	ActivityCalendarAttributeCalendarNameActive          int8 = 2
+	ActivityCalendarAttributeSeasonProfileActive         int8 = 3
+	ActivityCalendarAttributeWeekProfileTableActive      int8 = 4
+	ActivityCalendarAttributeDayProfileTableActive       int8 = 5
+	ActivityCalendarAttributeCalendarNamePassive         int8 = 6
+	ActivityCalendarAttributeSeasonProfilePassive        int8 = 7
+	ActivityCalendarAttributeWeekProfileTablePassive     int8 = 8
+	ActivityCalendarAttributeDayProfileTablePassive      int8 = 9
+	ActivityCalendarAttributeActivatePassiveCalendarTime int8 = 10
+
+	ActivityCalendarMethodActivatePassiveCalendar int8 = 1
+)
+
+// ActivityCalendarLogicalName is the logical name of the activity calendar of the meter
+const ActivityCalendarLogicalName = "0-0:13.0.0.255"
+
+// Season is the week profile in use from its start
+type Season struct {
+	Name []byte
+	// Start is usually given with wildcards (e.g. every year)
+	Start    axdr.CosemDateTime
+	WeekName []byte
+}
+
+// WeekProfile gives the day profile of each day of the week
+type WeekProfile struct {
+	Name []byte
+	// Days are the IDs of the day profiles, from Monday to Sunday
+	Days [7]uint8
+}
+
+// DayProfileAction is the script executed from a time of the day
+type DayProfileAction struct {
+	StartTime         axdr.CosemTime
+	ScriptLogicalName string
+	ScriptSelector    uint16
+}
+
+type DayProfile struct {
+	DayID   uint8
+	Actions []DayProfileAction
+}
+
+// Calendar is the active or passive calendar of an activity calendar
+type Calendar struct {
+	Name    []byte
+	Seasons []Season
+	Weeks   []WeekProfile
+	Days    []DayProfile
+}
+
+// Validate checks that the seasons refer to existing week profiles and these to
+// existing day profiles.
+func (cal *Calendar) Validate() error {
+	days := make(map[uint8]bool, len(cal.Days))
+	for _, d := range cal.Days {
+		if days[d.DayID] {
+			return fmt.Errorf("duplicated day profile %d", d.DayID)
+		}
+		days[d.DayID] = true
+	}
+
+	weeks := make(map[string]bool, len(cal.Weeks))
+	for _, w := range cal.Weeks {
+		name := hex.EncodeToString(w.Name)
+		if weeks[name] {
+			return fmt.Errorf("duplicated week profile %s", name)
+		}
+		weeks[name] = true
+
+		for _, d := range w.Days {
+			if !days[d] {
+				return fmt.Errorf("week profile %s refers to unknown day profile %d", name, d)
+			}
+		}
+	}
+
+	for _, s := range cal.Seasons {
+		if !weeks[hex.EncodeToString(s.WeekName)] {
+			return fmt.Errorf("season %s refers to unknown week profile %s", hex.EncodeToString(s.Name), hex.EncodeToString(s.WeekName))
+		}
+	}
+
+	return nil
+}
+
+func (cal *Calendar) seasonsData() *axdr.DlmsData {
+	seasons := make([]*axdr.DlmsData, len(cal.Seasons))
+	for i, s := range cal.Seasons {
+		seasons[i] = axdr.CreateAxdrStructure([]*axdr.DlmsData{
+			octetString(s.Name),
+			octetString(s.Start.Encode()),
+			octetString(s.WeekName),
+		})
+	}
+
+	return axdr.CreateAxdrArray(seasons)
+}
+
+func (cal *Calendar) weeksData() *axdr.DlmsData {
+	weeks := make([]*axdr.DlmsData, len(cal.Weeks))
+	for i, w := range cal.Weeks {
+		elements := []*axdr.DlmsData{octetString(w.Name)}
+		for _, d := range w.Days {
+			elements = append(elements, axdr.CreateAxdrUnsigned(d))
+		}
+		weeks[i] = axdr.CreateAxdrStructure(elements)
+	}
+
+	return axdr.CreateAxdrArray(weeks)
+}
+
+func (cal *Calendar) daysData() *axdr.DlmsData {
+	days := make([]*axdr.DlmsData, len(cal.Days))
+	for i, d := range cal.Days {
+		actions := make([]*axdr.DlmsData, len(d.Actions))
+		for j, a := range d.Actions {
+			actions[j] = axdr.CreateAxdrStructure([]*axdr.DlmsData{
+				octetString(a.StartTime.Encode()),
+				axdr.CreateAxdrOctetString(a.ScriptLogicalName),
+				axdr.CreateAxdrLongUnsigned(a.ScriptSelector),
+			})
+		}
+		days[i] = axdr.CreateAxdrStructure([]*axdr.DlmsData{axdr.CreateAxdrUnsigned(d.DayID), axdr.CreateAxdrArray(actions)})
+	}
+
+	return axdr.CreateAxdrArray(days)
+}
+
+// rawCalendar is a calendar as unmarshaled, with the octet-strings in hex
+type rawCalendar struct {
+	Name    string
+	Seasons []struct {
+		Name     string
+		Start    axdr.CosemDateTime
+		WeekName string
+	}
+	Weeks []struct {
+		Name                                                           string
+		Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday uint8
+	}
+	Days []struct {
+		DayID   uint8
+		Actions []struct {
+			StartTime         axdr.CosemTime
+			ScriptLogicalName string
+			ScriptSelector    uint16
+		}
+	}
+}
+
+func (raw *rawCalendar) calendar() (cal Calendar, err error) {
+	if cal.Name, err = hex.DecodeString(raw.Name); err != nil {
+		return cal, fmt.Errorf("invalid calendar name: %w", err)
+	}
+
+	for _, s := range raw.Seasons {
+		season := Season{Start: s.Start}
+		if season.Name, err = hex.DecodeString(s.Name); err != nil {
+			return cal, fmt.Errorf("invalid season name: %w", err)
+		}
+
+		if season.WeekName, err = hex.DecodeString(s.WeekName); err != nil {
+			return cal, fmt.Errorf("invalid week name: %w", err)
+		}
+		cal.Seasons = append(cal.Seasons, season)
+	}
+
+	for _, w := range raw.Weeks {
+		week := WeekProfile{Days: [7]uint8{w.Monday, w.Tuesday, w.Wednesday, w.Thursday, w.Friday, w.Saturday, w.Sunday}}
+		if week.Name, err = hex.DecodeString(w.Name); err != nil {
+			return cal, fmt.Errorf("invalid week name: %w", err)
+		}
+		cal.Weeks = append(cal.Weeks, week)
+	}
+
+	for _, d := range raw.Days {
+		day := DayProfile{DayID: d.DayID}
+		for _, a := range d.Actions {
+			logicalName, err := LogicalNameFromHex(a.ScriptLogicalName)
+			if err != nil {
+				return cal, fmt.Errorf("invalid action of day profile %d: %w", d.DayID, err)
+			}
+			day.Actions = append(day.Actions, DayProfileAction{StartTime: a.StartTime, ScriptLogicalName: logicalName, ScriptSelector: a.ScriptSelector})
+		}
+		cal.Days = append(cal.Days, day)
+	}
+
+	return cal, nil
+}
+
+type ActivityCalendar struct {
+	Object
+}
+
+// NewActivityCalendar returns the activity calendar with the given logical name,
+// or the activity calendar of the meter (ActivityCalendarLogicalName) if it is empty.
+func NewActivityCalendar(logicalName string) *ActivityCalendar {
+	if logicalName == "" {
+		logicalName = ActivityCalendarLogicalName
+	}
+
+	return &ActivityCalendar{Object{ClassID: ClassIDActivityCalendar, LogicalName: logicalName}}
+}
+
+// ReadActiveCalendar returns the calendar in use.
+func (a *ActivityCalendar) ReadActiveCalendar(c dlms.Client) (Calendar, error) {
+	return a.readCalendar(c, ActivityCalendarAttributeCalendarNameActive)
+}
+
+// ReadPassiveCalendar returns the calendar to be activated.
+func (a *ActivityCalendar) ReadPassiveCalendar(c dlms.Client) (Calendar, error) {
+	return a.readCalendar(c, ActivityCalendarAttributeCalendarNamePassive)
+}
+
+// readCalendar reads the name, seasons, weeks and days of a calendar, which are
+// consecutive attributes from first.
+func (a *ActivityCalendar) readCalendar(c dlms.Client, first int8) (Calendar, error) {
+	var raw rawCalendar
+
+	atts := []*dlms.AttributeDescriptor{
+		a.AttributeDescriptor(first),
+		a.AttributeDescriptor(first + 1),
+		a.AttributeDescriptor(first + 2),
+		a.AttributeDescriptor(first + 3),
+	}
+
+	if err := c.GetRequestWithList(atts, []interface{}{&raw.Name, &raw.Seasons, &raw.Weeks, &raw.Days}); err != nil {
+		return Calendar{}, err
+	}
+
+	cal, err := raw.calendar()
+	if err != nil {
+		return cal, dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("invalid calendar: %v", err))
+	}
+
+	return cal, nil
+}
+
+// WritePassiveCalendar writes the calendar to be activated. The day profiles are
+// written first, and the name last, so the meter never holds references to
+// profiles not written yet.
+func (a *ActivityCalendar) WritePassiveCalendar(c dlms.Client, cal Calendar) error {
+	if err := cal.Validate(); err != nil {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid calendar: %v", err))
+	}
+
+	if err := a.Set(c, ActivityCalendarAttributeDayProfileTablePassive, cal.daysData()); err != nil {
+		return err
+	}
+
+	if err := a.Set(c, ActivityCalendarAttributeWeekProfileTablePassive, cal.weeksData()); err != nil {
+		return err
+	}
+
+	if err := a.Set(c, ActivityCalendarAttributeSeasonProfilePassive, cal.seasonsData()); err != nil {
+		return err
+	}
+
+	return a.Set(c, ActivityCalendarAttributeCalendarNamePassive, octetString(cal.Name))
+}
+
+// ReadActivatePassiveCalendarTime returns when the passive calendar will be
+// activated, usually not specified (all wildcards) if no activation is pending.
+func (a *ActivityCalendar) ReadActivatePassiveCalendarTime(c dlms.Client) (dt axdr.CosemDateTime, err error) {
+	err = a.Get(c, ActivityCalendarAttributeActivatePassiveCalendarTime, &dt)
+	return
+}
+
+// WriteActivatePassiveCalendarTime schedules the activation of the passive calendar.
+func (a *ActivityCalendar) WriteActivatePassiveCalendarTime(c dlms.Client, dt axdr.CosemDateTime) error {
+	return a.Set(c, ActivityCalendarAttributeActivatePassiveCalendarTime, dt)
+}
+
+// ActivatePassiveCalendar copies the passive calendar into the active one now.
+func (a *ActivityCalendar) ActivatePassiveCalendar(c dlms.Client) error {
+	return a.Invoke(c, ActivityCalendarMethodActivatePassiveCalendar, integerZero())
+}
+
+// octetString returns the octet-string with the given bytes.
+func octetString(value []byte) *axdr.DlmsData {
+	return axdr.CreateAxdrOctetString(hex.EncodeToString(value))
+}
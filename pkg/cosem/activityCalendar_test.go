@@ -0,0 +1,117 @@
+package cosem_test
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+)
+
+const (
+	calendarName    = "090443414C31"
+	calendarSeasons = "0101" + "0203" + "090101" + "090CFFFF0101FF000000FF8000FF" + "090101"
+	calendarWeeks   = "0101" + "0208" + "090101" + "1101110111011101110111021102"
+	calendarDays    = "0102" +
+		"0202" + "1101" + "0102" +
+		"0203" + "090407000000" + "090600000A0064FF" + "120001" +
+		"0203" + "090411000000" + "090600000A0064FF" + "120002" +
+		"0202" + "1102" + "0101" +
+		"0203" + "090400000000" + "090600000A0064FF" + "120002"
+)
+
+func testCalendar() cosem.Calendar {
+	return cosem.Calendar{
+		Name: []byte("CAL1"),
+		Seasons: []cosem.Season{
+			{
+				Name: []byte{1},
+				Start: axdr.CosemDateTime{
+					Year: axdr.YearNotSpecified, Month: 1, Day: 1, DayOfWeek: axdr.NotSpecified,
+					Hundredths: axdr.NotSpecified, Deviation: axdr.DeviationNotSpecified, Status: axdr.ClockStatusNotSpecified,
+				},
+				WeekName: []byte{1},
+			},
+		},
+		Weeks: []cosem.WeekProfile{
+			{Name: []byte{1}, Days: [7]uint8{1, 1, 1, 1, 1, 2, 2}},
+		},
+		Days: []cosem.DayProfile{
+			{
+				DayID: 1,
+				Actions: []cosem.DayProfileAction{
+					{StartTime: axdr.CosemTime{Hour: 7}, ScriptLogicalName: "0.0.10.0.100.255", ScriptSelector: 1},
+					{StartTime: axdr.CosemTime{Hour: 17}, ScriptLogicalName: "0.0.10.0.100.255", ScriptSelector: 2},
+				},
+			},
+			{
+				DayID: 2,
+				Actions: []cosem.DayProfileAction{
+					{StartTime: axdr.CosemTime{}, ScriptLogicalName: "0.0.10.0.100.255", ScriptSelector: 2},
+				},
+			},
+		},
+	}
+}
+
+func TestActivityCalendar_ReadActiveCalendar(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 20, 0.0.13.0.0.255, 2 }"] = calendarName
+	c.attributes["{ 20, 0.0.13.0.0.255, 3 }"] = calendarSeasons
+	c.attributes["{ 20, 0.0.13.0.0.255, 4 }"] = calendarWeeks
+	c.attributes["{ 20, 0.0.13.0.0.255, 5 }"] = calendarDays
+
+	cal, err := cosem.NewActivityCalendar("").ReadActiveCalendar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, testCalendar(), cal)
+	assert.NoError(t, cal.Validate())
+}
+
+func TestActivityCalendar_WritePassiveCalendar(t *testing.T) {
+	c := newFakeClient()
+	a := cosem.NewActivityCalendar("")
+
+	err := a.WritePassiveCalendar(c, testCalendar())
+	require.NoError(t, err)
+
+	for attribute, want := range map[string]string{
+		"{ 20, 0.0.13.0.0.255, 6 }": calendarName,
+		"{ 20, 0.0.13.0.0.255, 7 }": calendarSeasons,
+		"{ 20, 0.0.13.0.0.255, 8 }": calendarWeeks,
+		"{ 20, 0.0.13.0.0.255, 9 }": calendarDays,
+	} {
+		require.Contains(t, c.written, attribute)
+		out, err := c.written[attribute].Encode()
+		assert.NoError(t, err)
+		assert.Equal(t, strings.ToLower(want), hex.EncodeToString(out), attribute)
+	}
+
+	err = a.WriteActivatePassiveCalendarTime(c, axdr.CosemDateTime{Year: 2024, Month: 1, Day: 1, DayOfWeek: 1, Status: axdr.ClockStatusNotSpecified})
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrOctetString("07e8010101000000000000ff"), c.written["{ 20, 0.0.13.0.0.255, 10 }"])
+
+	err = a.ActivatePassiveCalendar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrInteger(0), c.invoked["{ 20, 0.0.13.0.0.255, 1 }"])
+}
+
+func TestActivityCalendar_WritePassiveCalendarFail(t *testing.T) {
+	c := newFakeClient()
+	a := cosem.NewActivityCalendar("")
+
+	cal := testCalendar()
+	cal.Weeks[0].Days[6] = 3
+
+	err := a.WritePassiveCalendar(c, cal)
+	assert.ErrorContains(t, err, "unknown day profile 3")
+
+	cal = testCalendar()
+	cal.Seasons[0].WeekName = []byte{2}
+
+	err = a.WritePassiveCalendar(c, cal)
+	assert.ErrorContains(t, err, "unknown week profile 02")
+	assert.Empty(t, c.written)
+}
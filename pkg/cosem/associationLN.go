@@ -0,0 +1,184 @@
+package cosem
+
+import (
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Association LN (class ID 15, version 0 to 3) models the association between
+// the client and the server when using logical name referencing.
+const (
+	AssociationLNAttributeObjectList                  int8 = 2
+	AssociationLNAttributeAssociatedPartnersID        int8 = 3
+	AssociationLNAttributeApplicationContextName      int8 = 4
+	AssociationLNAttributeXDLMSContextInfo            int8 = 5
+	AssociationLNAttributeAuthenticationMechanismName int8 = 6
+	AssociationLNAttributeSecret                      int8 = 7
+	AssociationLNAttributeAssociationStatus           int8 = 8
+	AssociationLNAttributeSecuritySetupReference      int8 = 9
+	AssociationLNAttributeUserList                    int8 = 10
+	AssociationLNAttributeCurrentUser                 int8 = 11
+
+	AssociationLNMethodReplyToHLSAuthentication int8 = 1
+	AssociationLNMethodChangeHLSSecret          int8 = 2
+	AssociationLNMethodAddObject                int8 = 3
+	AssociationLNMethodRemoveObject             int8 = 4
+	AssociationLNMethodAddUser                  int8 = 5
+	AssociationLNMethodRemoveUser               int8 = 6
+)
+
+// AssociationLNLogicalName is the logical name of the current association
+const AssociationLNLogicalName = "0-0:40.0.0.255"
+
+// AssociationStatus is the state of an association
+type AssociationStatus uint8
+
+const (
+	AssociationStatusNonAssociated AssociationStatus = iota
+	AssociationStatusAssociationPending
+	AssociationStatusAssociated
+)
+
+// AttributeAccessMode is the access granted to an attribute in an association
+type AttributeAccessMode uint8
+
+const (
+	AttributeAccessNone AttributeAccessMode = iota
+	AttributeAccessRead
+	AttributeAccessWrite
+	AttributeAccessReadWrite
+	AttributeAccessAuthenticatedRead
+	AttributeAccessAuthenticatedWrite
+	AttributeAccessAuthenticatedReadWrite
+)
+
+// MethodAccessMode is the access granted to a method in an association
+type MethodAccessMode uint8
+
+const (
+	MethodAccessNone MethodAccessMode = iota
+	MethodAccessAllowed
+	MethodAccessAuthenticated
+)
+
+type AttributeAccess struct {
+	AttributeID     int8
+	AccessMode      AttributeAccessMode
+	AccessSelectors []int8
+}
+
+type MethodAccess struct {
+	MethodID   int8
+	AccessMode MethodAccessMode
+}
+
+type AccessRights struct {
+	Attributes []AttributeAccess
+	Methods    []MethodAccess
+}
+
+// ObjectListElement is an object visible in an association
+type ObjectListElement struct {
+	ClassID      uint16
+	Version      uint8
+	LogicalName  string
+	AccessRights AccessRights
+}
+
+type AssociationLN struct {
+	Object
+}
+
+// NewAssociationLN returns the association with the given logical name, or the
+// current one (AssociationLNLogicalName) if it is empty.
+func NewAssociationLN(logicalName string) *AssociationLN {
+	if logicalName == "" {
+		logicalName = AssociationLNLogicalName
+	}
+
+	return &AssociationLN{Object{ClassID: ClassIDAssociationLN, LogicalName: logicalName}}
+}
+
+// ReadObjectList returns the objects visible in the association and their access rights.
+func (a *AssociationLN) ReadObjectList(c dlms.Client) ([]ObjectListElement, error) {
+	var data axdr.DlmsData
+	if err := a.Get(c, AssociationLNAttributeObjectList, &data); err != nil {
+		return nil, err
+	}
+
+	return DecodeObjectList(data)
+}
+
+func (a *AssociationLN) ReadAssociationStatus(c dlms.Client) (status AssociationStatus, err error) {
+	err = a.Get(c, AssociationLNAttributeAssociationStatus, &status)
+	return
+}
+
+// DecodeObjectList decodes the object_list attribute. Versions 0 and later of
+// the class are accepted: the access mode of methods is a boolean in version 0.
+func DecodeObjectList(data axdr.DlmsData) ([]ObjectListElement, error) {
+	var raw []struct {
+		ClassID      uint16
+		Version      uint8
+		LogicalName  string
+		AccessRights struct {
+			Attributes []struct {
+				AttributeID     int8
+				AccessMode      AttributeAccessMode
+				AccessSelectors axdr.DlmsData
+			}
+			Methods []struct {
+				MethodID   int8
+				AccessMode axdr.DlmsData
+			}
+		}
+	}
+
+	if err := axdr.UnmarshalData(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid object list: %w", err)
+	}
+
+	objects := make([]ObjectListElement, len(raw))
+	for i, r := range raw {
+		logicalName, err := LogicalNameFromHex(r.LogicalName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid object %d: %w", i, err)
+		}
+
+		o := ObjectListElement{ClassID: r.ClassID, Version: r.Version, LogicalName: logicalName}
+
+		o.AccessRights.Attributes = make([]AttributeAccess, len(r.AccessRights.Attributes))
+		for j, att := range r.AccessRights.Attributes {
+			o.AccessRights.Attributes[j] = AttributeAccess{AttributeID: att.AttributeID, AccessMode: att.AccessMode}
+
+			if att.AccessSelectors.Tag != axdr.TagNull {
+				err = axdr.UnmarshalData(att.AccessSelectors, &o.AccessRights.Attributes[j].AccessSelectors)
+				if err != nil {
+					return nil, fmt.Errorf("invalid access selectors of %s attribute %d: %w", logicalName, att.AttributeID, err)
+				}
+			}
+		}
+
+		o.AccessRights.Methods = make([]MethodAccess, len(r.AccessRights.Methods))
+		for j, mth := range r.AccessRights.Methods {
+			o.AccessRights.Methods[j] = MethodAccess{MethodID: mth.MethodID}
+
+			switch v := mth.AccessMode.Value.(type) {
+			case bool:
+				if v {
+					o.AccessRights.Methods[j].AccessMode = MethodAccessAllowed
+				}
+			case uint8:
+				o.AccessRights.Methods[j].AccessMode = MethodAccessMode(v)
+			default:
+				return nil, fmt.Errorf("invalid access mode of %s method %d: %v", logicalName, mth.MethodID, mth.AccessMode.Value)
+			}
+		}
+
+		objects[i] = o
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,62 @@
+package cosem_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+)
+
+func TestAssociationLN_ReadObjectList(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 15, 0.0.40.0.0.255, 2 }"] = "0102" +
+		// Clock, version 0: access mode of methods is boolean
+		"0204120008110009060000010000FF" +
+		"0202" +
+		"0102" + "02030F01160100" + "02030F021603" + "01010F02" +
+		"0101" + "02020F060301" +
+		// Disconnect control, version 1: access mode of methods is enum
+		"0204120046110109060000600300FF" +
+		"0202" +
+		"0101" + "02030F02160100" +
+		"0101" + "02020F011602"
+	c.attributes["{ 15, 0.0.40.0.0.255, 8 }"] = "1602"
+
+	a := cosem.NewAssociationLN("")
+
+	objects, err := a.ReadObjectList(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []cosem.ObjectListElement{
+		{
+			ClassID:     8,
+			Version:     0,
+			LogicalName: "0.0.1.0.0.255",
+			AccessRights: cosem.AccessRights{
+				Attributes: []cosem.AttributeAccess{
+					{AttributeID: 1, AccessMode: cosem.AttributeAccessRead},
+					{AttributeID: 2, AccessMode: cosem.AttributeAccessReadWrite, AccessSelectors: []int8{2}},
+				},
+				Methods: []cosem.MethodAccess{
+					{MethodID: 6, AccessMode: cosem.MethodAccessAllowed},
+				},
+			},
+		},
+		{
+			ClassID:     70,
+			Version:     1,
+			LogicalName: "0.0.96.3.0.255",
+			AccessRights: cosem.AccessRights{
+				Attributes: []cosem.AttributeAccess{
+					{AttributeID: 2, AccessMode: cosem.AttributeAccessRead},
+				},
+				Methods: []cosem.MethodAccess{
+					{MethodID: 1, AccessMode: cosem.MethodAccessAuthenticated},
+				},
+			},
+		},
+	}, objects)
+
+	status, err := a.ReadAssociationStatus(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.AssociationStatusAssociated, status)
+}
@@ -0,0 +1,523 @@
+package cosem
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Capabilities is the model of the objects a meter exposes in an association, as
+// listed in its object_list, so requests can be checked before being sent.
+type Capabilities struct {
+	Model    string
+	Firmware string
+	Objects  []ObjectListElement
+
+	index map[string]int
+}
+
+// NewCapabilities returns the model of the objects given.
+func NewCapabilities(objects []ObjectListElement) *Capabilities {
+	m := &Capabilities{Objects: objects}
+	m.buildIndex()
+
+	return m
+}
+
+func (m *Capabilities) buildIndex() {
+	m.index = make(map[string]int, len(m.Objects))
+	for i, o := range m.Objects {
+		m.index[o.LogicalName] = i
+	}
+}
+
+// DiscoverCapabilities reads the object list of the association.
+func (a *AssociationLN) DiscoverCapabilities(c dlms.Client) (*Capabilities, error) {
+	objects, err := a.ReadObjectList(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCapabilities(objects), nil
+}
+
+// Find returns the object with the given logical name, in any notation accepted
+// by dlms.CreateObis.
+func (m *Capabilities) Find(logicalName string) (ObjectListElement, bool) {
+	i, ok := m.index[dlms.CreateObis(logicalName).String()]
+	if !ok {
+		return ObjectListElement{}, false
+	}
+
+	return m.Objects[i], true
+}
+
+// FindByClass returns the objects of an interface class.
+func (m *Capabilities) FindByClass(classID uint16) []ObjectListElement {
+	var objects []ObjectListElement
+	for _, o := range m.Objects {
+		if o.ClassID == classID {
+			objects = append(objects, o)
+		}
+	}
+
+	return objects
+}
+
+// Object returns the object with the given logical name with the interface class
+// the meter uses for it.
+func (m *Capabilities) Object(logicalName string) (Object, error) {
+	o, ok := m.Find(logicalName)
+	if !ok {
+		return Object{}, dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("object %s not found", logicalName))
+	}
+
+	return Object{ClassID: o.ClassID, LogicalName: o.LogicalName}, nil
+}
+
+func (m *Capabilities) attributeAccess(classID uint16, logicalName string, attribute int8) (AttributeAccessMode, error) {
+	o, ok := m.Find(logicalName)
+	if !ok {
+		return AttributeAccessNone, dlms.NewError(dlms.ErrorAccessDenied, fmt.Sprintf("object %s not found", logicalName))
+	}
+
+	if o.ClassID != classID {
+		return AttributeAccessNone, dlms.NewError(dlms.ErrorAccessDenied, fmt.Sprintf("object %s is of class %d, not %d", logicalName, o.ClassID, classID))
+	}
+
+	for _, att := range o.AccessRights.Attributes {
+		if att.AttributeID == attribute {
+			return att.AccessMode, nil
+		}
+	}
+
+	return AttributeAccessNone, nil
+}
+
+// CheckRead returns an error if the attribute cannot be read.
+func (m *Capabilities) CheckRead(att *dlms.AttributeDescriptor) error {
+	mode, err := m.attributeAccess(att.ClassID, att.InstanceID.String(), att.AttributeID)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case AttributeAccessRead, AttributeAccessReadWrite, AttributeAccessAuthenticatedRead, AttributeAccessAuthenticatedReadWrite:
+		return nil
+	default:
+		return dlms.NewError(dlms.ErrorAccessDenied, fmt.Sprintf("%s cannot be read", att.String()))
+	}
+}
+
+// CheckWrite returns an error if the attribute cannot be written.
+func (m *Capabilities) CheckWrite(att *dlms.AttributeDescriptor) error {
+	mode, err := m.attributeAccess(att.ClassID, att.InstanceID.String(), att.AttributeID)
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case AttributeAccessWrite, AttributeAccessReadWrite, AttributeAccessAuthenticatedWrite, AttributeAccessAuthenticatedReadWrite:
+		return nil
+	default:
+		return dlms.NewError(dlms.ErrorAccessDenied, fmt.Sprintf("%s cannot be written", att.String()))
+	}
+}
+
+// CheckInvoke returns an error if the method cannot be invoked.
+func (m *Capabilities) CheckInvoke(mth *dlms.MethodDescriptor) error {
+	logicalName := mth.InstanceID.String()
+
+	o, ok := m.Find(logicalName)
+	if !ok {
+		return dlms.NewError(dlms.ErrorAccessDenied, fmt.Sprintf("object %s not found", logicalName))
+	}
+
+	if o.ClassID != mth.ClassID {
+		return dlms.NewError(dlms.ErrorAccessDenied, fmt.Sprintf("object %s is of class %d, not %d", logicalName, o.ClassID, mth.ClassID))
+	}
+
+	for _, method := range o.AccessRights.Methods {
+		if method.MethodID == mth.MethodID && method.AccessMode != MethodAccessNone {
+			return nil
+		}
+	}
+
+	return dlms.NewError(dlms.ErrorAccessDenied, fmt.Sprintf("%s cannot be invoked", mth.String()))
+}
+
+// checkedClient checks the access rights of the requests before sending them.
+type checkedClient struct {
+	dlms.ContextClient
+	capabilities *Capabilities
+}
+
+// NewCheckedClient returns a client that rejects with dlms.ErrorAccessDenied the
+// get, set and action requests not allowed by the capabilities, without sending them.
+// The capabilities are indexed by logical name, so the read and write requests of
+// short name referencing are not checked.
+func NewCheckedClient(c dlms.Client, capabilities *Capabilities) dlms.ContextClient {
+	return &checkedClient{ContextClient: dlms.AsContextClient(c), capabilities: capabilities}
+}
+
+// checkList returns the first error of check on the attributes given.
+func checkList(atts []*dlms.AttributeDescriptor, check func(*dlms.AttributeDescriptor) error) error {
+	for _, att := range atts {
+		if err := check(att); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkStructOfElements checks the attributes tagged in the fields of a struct of
+// elements. Nested structs are checked if nested is true, and nil pointers are
+// skipped if skipNil is true, as the request given the same data would do.
+func checkStructOfElements(data interface{}, nested bool, skipNil bool, check func(*dlms.AttributeDescriptor) error) error {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		// Let the client report the invalid data
+		return nil
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		att, _, err := dlms.ParseObisTag(v.Type().Field(i).Tag.Get("obis"))
+		if err != nil {
+			return dlms.NewError(dlms.ErrorInvalidParameter, err.Error())
+		}
+
+		field := v.Field(i)
+
+		switch {
+		case att != nil:
+			if skipNil && field.Kind() == reflect.Ptr && field.IsNil() {
+				continue
+			}
+
+			if err := check(att); err != nil {
+				return err
+			}
+		case nested && field.Kind() == reflect.Struct:
+			if err := checkStructOfElements(field.Addr().Interface(), nested, skipNil, check); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// deniedRows yields only the error of a denied request of rows.
+func deniedRows(err error) iter.Seq2[axdr.DlmsData, error] {
+	return func(yield func(axdr.DlmsData, error) bool) {
+		yield(axdr.DlmsData{}, err)
+	}
+}
+
+func (c *checkedClient) GetRequest(att *dlms.AttributeDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequest(att, data)
+}
+
+func (c *checkedClient) GetRequestContext(ctx context.Context, att *dlms.AttributeDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestContext(ctx, att, data)
+}
+
+func (c *checkedClient) GetRequestWithSelectiveAccess(att *dlms.AttributeDescriptor, selectiveAccess axdr.DlmsData, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithSelectiveAccess(att, selectiveAccess, data)
+}
+
+func (c *checkedClient) GetRequestWithSelectiveAccessContext(ctx context.Context, att *dlms.AttributeDescriptor, selectiveAccess axdr.DlmsData, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithSelectiveAccessContext(ctx, att, selectiveAccess, data)
+}
+
+func (c *checkedClient) GetRequestWithRange(att *dlms.AttributeDescriptor, rd dlms.RangeDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithRange(att, rd, data)
+}
+
+func (c *checkedClient) GetRequestWithRangeContext(ctx context.Context, att *dlms.AttributeDescriptor, rd dlms.RangeDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithRangeContext(ctx, att, rd, data)
+}
+
+func (c *checkedClient) GetRequestWithEntries(att *dlms.AttributeDescriptor, ed dlms.EntryDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithEntries(att, ed, data)
+}
+
+func (c *checkedClient) GetRequestWithEntriesContext(ctx context.Context, att *dlms.AttributeDescriptor, ed dlms.EntryDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithEntriesContext(ctx, att, ed, data)
+}
+
+func (c *checkedClient) GetRequestWithSelectiveAccessByDate(att *dlms.AttributeDescriptor, start time.Time, end time.Time, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithSelectiveAccessByDate(att, start, end, data)
+}
+
+func (c *checkedClient) GetRequestWithSelectiveAccessByDateContext(ctx context.Context, att *dlms.AttributeDescriptor, start time.Time, end time.Time, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithSelectiveAccessByDateContext(ctx, att, start, end, data)
+}
+
+func (c *checkedClient) GetRequestWithSelectiveAccessByDateAndValues(att *dlms.AttributeDescriptor, start time.Time, end time.Time, values []dlms.AttributeDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithSelectiveAccessByDateAndValues(att, start, end, values, data)
+}
+
+func (c *checkedClient) GetRequestWithSelectiveAccessByDateAndValuesContext(ctx context.Context, att *dlms.AttributeDescriptor, start time.Time, end time.Time, values []dlms.AttributeDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithSelectiveAccessByDateAndValuesContext(ctx, att, start, end, values, data)
+}
+
+func (c *checkedClient) GetRequestWithList(atts []*dlms.AttributeDescriptor, data []interface{}) error {
+	if err := checkList(atts, c.capabilities.CheckRead); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithList(atts, data)
+}
+
+func (c *checkedClient) GetRequestWithListContext(ctx context.Context, atts []*dlms.AttributeDescriptor, data []interface{}) error {
+	if err := checkList(atts, c.capabilities.CheckRead); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithListContext(ctx, atts, data)
+}
+
+func (c *checkedClient) GetRequestWithStructOfElements(data interface{}) error {
+	if err := checkStructOfElements(data, true, false, c.capabilities.CheckRead); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithStructOfElements(data)
+}
+
+func (c *checkedClient) GetRequestWithStructOfElementsContext(ctx context.Context, data interface{}) error {
+	if err := checkStructOfElements(data, true, false, c.capabilities.CheckRead); err != nil {
+		return err
+	}
+
+	return c.ContextClient.GetRequestWithStructOfElementsContext(ctx, data)
+}
+
+func (c *checkedClient) GetRequestRows(att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error] {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return deniedRows(err)
+	}
+
+	return c.ContextClient.GetRequestRows(att, acc)
+}
+
+func (c *checkedClient) GetRequestRowsContext(ctx context.Context, att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error] {
+	if err := c.capabilities.CheckRead(att); err != nil {
+		return deniedRows(err)
+	}
+
+	return c.ContextClient.GetRequestRowsContext(ctx, att, acc)
+}
+
+func (c *checkedClient) SetRequest(att *dlms.AttributeDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckWrite(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.SetRequest(att, data)
+}
+
+func (c *checkedClient) SetRequestContext(ctx context.Context, att *dlms.AttributeDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckWrite(att); err != nil {
+		return err
+	}
+
+	return c.ContextClient.SetRequestContext(ctx, att, data)
+}
+
+func (c *checkedClient) SetRequestWithStructOfElements(data interface{}, continueOnSetRejected bool) error {
+	if err := checkStructOfElements(data, false, true, c.capabilities.CheckWrite); err != nil {
+		return err
+	}
+
+	return c.ContextClient.SetRequestWithStructOfElements(data, continueOnSetRejected)
+}
+
+func (c *checkedClient) SetRequestWithStructOfElementsContext(ctx context.Context, data interface{}, continueOnSetRejected bool) error {
+	if err := checkStructOfElements(data, false, true, c.capabilities.CheckWrite); err != nil {
+		return err
+	}
+
+	return c.ContextClient.SetRequestWithStructOfElementsContext(ctx, data, continueOnSetRejected)
+}
+
+func (c *checkedClient) CheckRequestWithStructOfElements(data interface{}) error {
+	if err := checkStructOfElements(data, true, true, c.capabilities.CheckRead); err != nil {
+		return err
+	}
+
+	return c.ContextClient.CheckRequestWithStructOfElements(data)
+}
+
+func (c *checkedClient) CheckRequestWithStructOfElementsContext(ctx context.Context, data interface{}) error {
+	if err := checkStructOfElements(data, true, true, c.capabilities.CheckRead); err != nil {
+		return err
+	}
+
+	return c.ContextClient.CheckRequestWithStructOfElementsContext(ctx, data)
+}
+
+func (c *checkedClient) ActionRequest(mth *dlms.MethodDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckInvoke(mth); err != nil {
+		return err
+	}
+
+	return c.ContextClient.ActionRequest(mth, data)
+}
+
+func (c *checkedClient) ActionRequestContext(ctx context.Context, mth *dlms.MethodDescriptor, data interface{}) error {
+	if err := c.capabilities.CheckInvoke(mth); err != nil {
+		return err
+	}
+
+	return c.ContextClient.ActionRequestContext(ctx, mth, data)
+}
+
+// capabilitiesFile returns the file of the capabilities of a meter model and
+// firmware in a cache directory. The name is derived from a hash of both, so
+// different pairs never share a file, after a readable prefix that only keeps
+// letters, digits and '-' (no path separators nor dots).
+func capabilitiesFile(dir string, model string, firmware string) string {
+	clean := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r == '-' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+				return r
+			}
+
+			return '_'
+		}, s)
+	}
+
+	sum := sha256.Sum256([]byte(model + "\x00" + firmware))
+
+	return filepath.Join(dir, clean(model)+"_"+clean(firmware)+"_"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// SaveCapabilities stores the capabilities of a meter model and firmware in a
+// cache directory.
+func SaveCapabilities(dir string, model string, firmware string, m *Capabilities) error {
+	m.Model = model
+	m.Firmware = firmware
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(capabilitiesFile(dir, model, firmware), data, 0o600)
+}
+
+// LoadCapabilities returns the capabilities of a meter model and firmware stored
+// in a cache directory. The error wraps fs.ErrNotExist if they are not there.
+func LoadCapabilities(dir string, model string, firmware string) (*Capabilities, error) {
+	data, err := os.ReadFile(capabilitiesFile(dir, model, firmware))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Capabilities{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid capabilities of %s %s: %w", model, firmware, err)
+	}
+	m.buildIndex()
+
+	return m, nil
+}
+
+// DiscoverCapabilitiesCached returns the capabilities of a meter model and firmware
+// from the cache directory, reading and storing them there the first time.
+func (a *AssociationLN) DiscoverCapabilitiesCached(c dlms.Client, dir string, model string, firmware string) (*Capabilities, error) {
+	m, err := LoadCapabilities(dir, model, firmware)
+	if err == nil {
+		return m, nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	m, err = a.DiscoverCapabilities(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SaveCapabilities(dir, model, firmware, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,179 @@
+package cosem_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func newCapabilitiesClient() *fakeClient {
+	c := newFakeClient()
+	c.attributes["{ 15, 0.0.40.0.0.255, 2 }"] = "0102" +
+		"0204120008110009060000010000FF" +
+		"0202" +
+		"0102" + "02030F01160100" + "02030F021603" + "01010F02" +
+		"0101" + "02020F060301" +
+		"0204120046110109060000600300FF" +
+		"0202" +
+		"0101" + "02030F02160100" +
+		"0101" + "02020F011602"
+
+	return c
+}
+
+func assertAccessDenied(t *testing.T, err error) {
+	t.Helper()
+
+	var dlmsError *dlms.Error
+	require.True(t, errors.As(err, &dlmsError))
+	assert.Equal(t, dlms.ErrorAccessDenied, dlmsError.Code())
+}
+
+func TestCapabilities(t *testing.T) {
+	c := newCapabilitiesClient()
+
+	caps, err := cosem.NewAssociationLN("").DiscoverCapabilities(c)
+	require.NoError(t, err)
+
+	o, ok := caps.Find("0-0:1.0.0.255")
+	assert.True(t, ok)
+	assert.Equal(t, uint16(cosem.ClassIDClock), o.ClassID)
+
+	_, ok = caps.Find("1.0.1.8.0.255")
+	assert.False(t, ok)
+
+	assert.Len(t, caps.FindByClass(cosem.ClassIDDisconnectControl), 1)
+
+	object, err := caps.Object("0.0.96.3.0.255")
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.Object{ClassID: cosem.ClassIDDisconnectControl, LogicalName: "0.0.96.3.0.255"}, object)
+
+	assert.NoError(t, caps.CheckRead(dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 2)))
+	assert.NoError(t, caps.CheckWrite(dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 2)))
+	assertAccessDenied(t, caps.CheckWrite(dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 1)))
+	assertAccessDenied(t, caps.CheckRead(dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 3)))
+	assertAccessDenied(t, caps.CheckRead(dlms.CreateAttributeDescriptor(1, "0.0.1.0.0.255", 2)))
+	assertAccessDenied(t, caps.CheckRead(dlms.CreateAttributeDescriptor(3, "1.0.1.8.0.255", 2)))
+
+	assert.NoError(t, caps.CheckInvoke(dlms.CreateMethodDescriptor(70, "0.0.96.3.0.255", 1)))
+	assertAccessDenied(t, caps.CheckInvoke(dlms.CreateMethodDescriptor(70, "0.0.96.3.0.255", 2)))
+}
+
+func TestNewCheckedClient(t *testing.T) {
+	c := newCapabilitiesClient()
+	c.attributes["{ 8, 0.0.1.0.0.255, 1 }"] = "09060000010000FF"
+	c.attributes["{ 8, 0.0.1.0.0.255, 3 }"] = "1000"
+
+	caps, err := cosem.NewAssociationLN("").DiscoverCapabilities(c)
+	require.NoError(t, err)
+
+	checked := cosem.NewCheckedClient(c, caps)
+
+	var ln axdr.DlmsData
+	assert.NoError(t, checked.GetRequest(dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 1), &ln))
+	assert.Equal(t, "0000010000ff", ln.Value)
+
+	var timeZone int16
+	assertAccessDenied(t, checked.GetRequest(dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 3), &timeZone))
+
+	assertAccessDenied(t, checked.SetRequest(dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 1), "x"))
+	assertAccessDenied(t, checked.ActionRequest(dlms.CreateMethodDescriptor(70, "0.0.96.3.0.255", 2), int8(0)))
+	assert.NoError(t, checked.ActionRequest(dlms.CreateMethodDescriptor(70, "0.0.96.3.0.255", 1), int8(0)))
+
+	assert.Empty(t, c.written)
+	assert.Len(t, c.invoked, 1)
+}
+
+func TestNewCheckedClientChecksEveryRequest(t *testing.T) {
+	c := newCapabilitiesClient()
+
+	caps, err := cosem.NewAssociationLN("").DiscoverCapabilities(c)
+	require.NoError(t, err)
+
+	checked := cosem.NewCheckedClient(c, caps)
+
+	logicalName := dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 1)
+	timeZone := dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 3)
+
+	var ln, tz axdr.DlmsData
+	assertAccessDenied(t, checked.GetRequestWithList([]*dlms.AttributeDescriptor{logicalName, timeZone}, []interface{}{&ln, &tz}))
+	assertAccessDenied(t, checked.GetRequestWithListContext(context.Background(), []*dlms.AttributeDescriptor{logicalName, timeZone}, []interface{}{&ln, &tz}))
+	assertAccessDenied(t, checked.GetRequestWithRange(timeZone, dlms.RangeDescriptor{}, &tz))
+	assertAccessDenied(t, checked.GetRequestWithEntries(timeZone, dlms.EntryDescriptor{}, &tz))
+
+	for _, err := range checked.GetRequestRows(timeZone, nil) {
+		assertAccessDenied(t, err)
+	}
+
+	var nested struct {
+		Clock struct {
+			TimeZone int16 `obis:"8,0.0.1.0.0.255,3"`
+		}
+	}
+	assertAccessDenied(t, checked.GetRequestWithStructOfElements(&nested))
+	assertAccessDenied(t, checked.CheckRequestWithStructOfElements(&nested))
+
+	// Nil fields are not written, but the logical name is read-only
+	var write struct {
+		LogicalName string `obis:"8,0.0.1.0.0.255,1"`
+		TimeZone    *int16 `obis:"8,0.0.1.0.0.255,3"`
+	}
+	assertAccessDenied(t, checked.SetRequestWithStructOfElements(&write, false))
+
+	assert.Empty(t, c.written)
+}
+
+func TestDiscoverCapabilitiesCached(t *testing.T) {
+	dir := t.TempDir()
+
+	c := newCapabilitiesClient()
+	a := cosem.NewAssociationLN("")
+
+	caps, err := a.DiscoverCapabilitiesCached(c, dir, "CIRWATT B/410", "1.0")
+	require.NoError(t, err)
+	assert.Len(t, caps.Objects, 2)
+
+	// The second time the meter is not read
+	delete(c.attributes, "{ 15, 0.0.40.0.0.255, 2 }")
+
+	cached, err := a.DiscoverCapabilitiesCached(c, dir, "CIRWATT B/410", "1.0")
+	require.NoError(t, err)
+	assert.Equal(t, caps.Objects, cached.Objects)
+	assert.NoError(t, cached.CheckRead(dlms.CreateAttributeDescriptor(8, "0.0.1.0.0.255", 2)))
+
+	_, err = a.DiscoverCapabilitiesCached(c, dir, "CIRWATT B/410", "2.0")
+	assert.Error(t, err)
+}
+
+func TestSaveCapabilitiesFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// Pairs that only differ in where the model ends do not share a file
+	require.NoError(t, cosem.SaveCapabilities(dir, "A_B", "C", &cosem.Capabilities{}))
+	require.NoError(t, cosem.SaveCapabilities(dir, "A", "B_C", &cosem.Capabilities{}))
+
+	caps, err := cosem.LoadCapabilities(dir, "A_B", "C")
+	require.NoError(t, err)
+	assert.Equal(t, "A_B", caps.Model)
+
+	caps, err = cosem.LoadCapabilities(dir, "A", "B_C")
+	require.NoError(t, err)
+	assert.Equal(t, "A", caps.Model)
+
+	// Path separators and dots stay in the cache directory
+	require.NoError(t, cosem.SaveCapabilities(dir, "../../model", "/..", &cosem.Capabilities{}))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+	for _, f := range files {
+		assert.NotContains(t, f.Name(), "..")
+	}
+}
@@ -0,0 +1,176 @@
+package cosem
+
+import (
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Clock (class ID 8, version 0) handles the date and time of the meter.
+const (
+	ClockAttributeTime              int8 = 2
+	ClockAttributeTimeZone          int8 = 3
+	ClockAttributeStatus            int8 = 4
+	ClockAttributeDaylightBegin     int8 = 5
+	ClockAttributeDaylightEnd       int8 = 6
+	ClockAttributeDaylightDeviation int8 = 7
+	ClockAttributeDaylightEnabled   int8 = 8
+	ClockAttributeClockBase         int8 = 9
+
+	ClockMethodAdjustToQuarter         int8 = 1
+	ClockMethodAdjustToMeasuringPeriod int8 = 2
+	ClockMethodAdjustToMinute          int8 = 3
+	ClockMethodAdjustToPresetTime      int8 = 4
+	ClockMethodPresetAdjustingTime     int8 = 5
+	ClockMethodShiftTime               int8 = 6
+)
+
+// ClockLogicalName is the logical name of the clock of the meter
+const ClockLogicalName = "0-0:1.0.0.255"
+
+// ClockBase is the source of the clock
+type ClockBase uint8
+
+const (
+	ClockBaseNotDefined ClockBase = iota
+	ClockBaseInternalCrystal
+	ClockBaseMainsFrequency50Hz
+	ClockBaseMainsFrequency60Hz
+	ClockBaseGPS
+	ClockBaseRadioControlled
+)
+
+type Clock struct {
+	Object
+}
+
+// NewClock returns the clock with the given logical name, or the clock of the
+// meter (ClockLogicalName) if it is empty.
+func NewClock(logicalName string) *Clock {
+	if logicalName == "" {
+		logicalName = ClockLogicalName
+	}
+
+	return &Clock{Object{ClassID: ClassIDClock, LogicalName: logicalName}}
+}
+
+// Read returns the time of the clock, with its deviation interpreted according to
+// the time zone of the client.
+func (k *Clock) Read(c dlms.Client) (t time.Time, err error) {
+	err = k.Get(c, ClockAttributeTime, &t)
+	return
+}
+
+// ReadDateTime returns the time of the clock as sent by the meter, with its
+// deviation and clock status.
+func (k *Clock) ReadDateTime(c dlms.Client) (dt axdr.CosemDateTime, err error) {
+	err = k.Get(c, ClockAttributeTime, &dt)
+	return
+}
+
+// Write sets the time of the clock, with its deviation encoded according to the
+// time zone of the client.
+func (k *Clock) Write(c dlms.Client, t time.Time) error {
+	return k.Set(c, ClockAttributeTime, t)
+}
+
+func (k *Clock) WriteDateTime(c dlms.Client, dt axdr.CosemDateTime) error {
+	return k.Set(c, ClockAttributeTime, dt)
+}
+
+// ReadTimeZone returns the deviation of local time to UTC in minutes.
+func (k *Clock) ReadTimeZone(c dlms.Client) (minutes int16, err error) {
+	err = k.Get(c, ClockAttributeTimeZone, &minutes)
+	return
+}
+
+func (k *Clock) WriteTimeZone(c dlms.Client, minutes int16) error {
+	return k.Set(c, ClockAttributeTimeZone, minutes)
+}
+
+func (k *Clock) ReadStatus(c dlms.Client) (status axdr.ClockStatus, err error) {
+	err = k.Get(c, ClockAttributeStatus, &status)
+	return
+}
+
+// ReadDaylightSavingsBegin returns when daylight savings begin, usually with
+// wildcards (e.g. the last Sunday of March every year).
+func (k *Clock) ReadDaylightSavingsBegin(c dlms.Client) (dt axdr.CosemDateTime, err error) {
+	err = k.Get(c, ClockAttributeDaylightBegin, &dt)
+	return
+}
+
+func (k *Clock) WriteDaylightSavingsBegin(c dlms.Client, dt axdr.CosemDateTime) error {
+	return k.Set(c, ClockAttributeDaylightBegin, dt)
+}
+
+// ReadDaylightSavingsEnd returns when daylight savings end, usually with wildcards.
+func (k *Clock) ReadDaylightSavingsEnd(c dlms.Client) (dt axdr.CosemDateTime, err error) {
+	err = k.Get(c, ClockAttributeDaylightEnd, &dt)
+	return
+}
+
+func (k *Clock) WriteDaylightSavingsEnd(c dlms.Client, dt axdr.CosemDateTime) error {
+	return k.Set(c, ClockAttributeDaylightEnd, dt)
+}
+
+// ReadDaylightSavingsDeviation returns the offset applied in daylight savings time in minutes.
+func (k *Clock) ReadDaylightSavingsDeviation(c dlms.Client) (minutes int8, err error) {
+	err = k.Get(c, ClockAttributeDaylightDeviation, &minutes)
+	return
+}
+
+func (k *Clock) WriteDaylightSavingsDeviation(c dlms.Client, minutes int8) error {
+	return k.Set(c, ClockAttributeDaylightDeviation, minutes)
+}
+
+func (k *Clock) ReadDaylightSavingsEnabled(c dlms.Client) (enabled bool, err error) {
+	err = k.Get(c, ClockAttributeDaylightEnabled, &enabled)
+	return
+}
+
+func (k *Clock) WriteDaylightSavingsEnabled(c dlms.Client, enabled bool) error {
+	return k.Set(c, ClockAttributeDaylightEnabled, enabled)
+}
+
+func (k *Clock) ReadClockBase(c dlms.Client) (base ClockBase, err error) {
+	err = k.Get(c, ClockAttributeClockBase, &base)
+	return
+}
+
+// AdjustToQuarter sets the clock to the nearest quarter of an hour.
+func (k *Clock) AdjustToQuarter(c dlms.Client) error {
+	return k.Invoke(c, ClockMethodAdjustToQuarter, integerZero())
+}
+
+func (k *Clock) AdjustToMeasuringPeriod(c dlms.Client) error {
+	return k.Invoke(c, ClockMethodAdjustToMeasuringPeriod, integerZero())
+}
+
+// AdjustToMinute sets the clock to the nearest minute.
+func (k *Clock) AdjustToMinute(c dlms.Client) error {
+	return k.Invoke(c, ClockMethodAdjustToMinute, integerZero())
+}
+
+// AdjustToPresetTime sets the clock to the time given by PresetAdjustingTime.
+func (k *Clock) AdjustToPresetTime(c dlms.Client) error {
+	return k.Invoke(c, ClockMethodAdjustToPresetTime, integerZero())
+}
+
+// PresetAdjustingTime presets the time to be set by AdjustToPresetTime, to be
+// accepted only between validityStart and validityEnd.
+func (k *Clock) PresetAdjustingTime(c dlms.Client, preset time.Time, validityStart time.Time, validityEnd time.Time) error {
+	data := struct {
+		Preset        time.Time
+		ValidityStart time.Time
+		ValidityEnd   time.Time
+	}{preset, validityStart, validityEnd}
+
+	return k.Invoke(c, ClockMethodPresetAdjustingTime, data)
+}
+
+// ShiftTime moves the clock forward or backward, between -900 and 900 seconds.
+func (k *Clock) ShiftTime(c dlms.Client, seconds int16) error {
+	return k.Invoke(c, ClockMethodShiftTime, axdr.CreateAxdrLong(seconds))
+}
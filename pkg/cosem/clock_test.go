@@ -0,0 +1,58 @@
+package cosem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+)
+
+func TestClock(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 8, 0.0.1.0.0.255, 2 }"] = "090C07E40C1F04173B3B0000000000"
+	c.attributes["{ 8, 0.0.1.0.0.255, 3 }"] = "10FFC4"
+	c.attributes["{ 8, 0.0.1.0.0.255, 4 }"] = "1182"
+	c.attributes["{ 8, 0.0.1.0.0.255, 5 }"] = "090CFFFF03FE0702000000800000"
+	c.attributes["{ 8, 0.0.1.0.0.255, 9 }"] = "1601"
+
+	clock := cosem.NewClock("")
+
+	now, err := clock.Read(c)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2020, time.December, 31, 23, 59, 59, 0, time.UTC).Unix(), now.Unix())
+
+	dt, err := clock.ReadDateTime(c)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CosemDateTime{Year: 2020, Month: 12, Day: 31, DayOfWeek: 4, Hour: 23, Minute: 59, Second: 59}, dt)
+
+	status, err := clock.ReadStatus(c)
+	assert.NoError(t, err)
+	assert.True(t, status.IsDoubtful())
+	assert.True(t, status.IsDaylightSavingActive())
+
+	begin, err := clock.ReadDaylightSavingsBegin(c)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.DayLastOfMonth, begin.Day)
+	assert.True(t, begin.HasWildcards())
+
+	end := begin
+	end.Month = 10
+	end.Hour = 3
+	err = clock.WriteDaylightSavingsEnd(c, end)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrOctetString("ffff0afe0703000000800000"), c.written["{ 8, 0.0.1.0.0.255, 6 }"])
+
+	tz, err := clock.ReadTimeZone(c)
+	assert.NoError(t, err)
+	assert.Equal(t, int16(-60), tz)
+
+	base, err := clock.ReadClockBase(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.ClockBaseInternalCrystal, base)
+
+	err = clock.ShiftTime(c, -30)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrLong(-30), c.invoked["{ 8, 0.0.1.0.0.255, 6 }"])
+}
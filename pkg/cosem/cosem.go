@@ -0,0 +1,97 @@
+// Package cosem provides typed models of the most common COSEM interface classes,
+// so objects can be accessed without dealing with class, attribute and method IDs.
+package cosem
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Class IDs of the interface classes
+const (
+	ClassIDData              uint16 = 1
+	ClassIDRegister          uint16 = 3
+	ClassIDExtendedRegister  uint16 = 4
+	ClassIDDemandRegister    uint16 = 5
+	ClassIDProfileGeneric    uint16 = 7
+	ClassIDClock             uint16 = 8
+	ClassIDScriptTable       uint16 = 9
+	ClassIDSpecialDaysTable  uint16 = 11
+	ClassIDAssociationLN     uint16 = 15
+	ClassIDSAPAssignment     uint16 = 17
+	ClassIDImageTransfer     uint16 = 18
+	ClassIDActivityCalendar  uint16 = 20
+	ClassIDDisconnectControl uint16 = 70
+	ClassIDLimiter           uint16 = 71
+)
+
+// AttributeLogicalName is the first attribute of every interface class
+const AttributeLogicalName int8 = 1
+
+// Object is an instance of an interface class, identified by its logical name (OBIS code).
+type Object struct {
+	ClassID     uint16
+	LogicalName string
+}
+
+func (o Object) AttributeDescriptor(attribute int8) *dlms.AttributeDescriptor {
+	return dlms.CreateAttributeDescriptor(o.ClassID, o.LogicalName, attribute)
+}
+
+func (o Object) MethodDescriptor(method int8) *dlms.MethodDescriptor {
+	return dlms.CreateMethodDescriptor(o.ClassID, o.LogicalName, method)
+}
+
+// Get reads an attribute of the object into data, as dlms.Client GetRequest does.
+func (o Object) Get(c dlms.Client, attribute int8, data interface{}) error {
+	return c.GetRequest(o.AttributeDescriptor(attribute), data)
+}
+
+// Set writes an attribute of the object, as dlms.Client SetRequest does.
+func (o Object) Set(c dlms.Client, attribute int8, data interface{}) error {
+	return c.SetRequest(o.AttributeDescriptor(attribute), data)
+}
+
+// Invoke executes a method of the object, as dlms.Client ActionRequest does.
+func (o Object) Invoke(c dlms.Client, method int8, data interface{}) error {
+	return c.ActionRequest(o.MethodDescriptor(method), data)
+}
+
+// GetContext is Get with a context, as dlms.ContextClient GetRequestContext does.
+func (o Object) GetContext(ctx context.Context, c dlms.Client, attribute int8, data interface{}) error {
+	return dlms.AsContextClient(c).GetRequestContext(ctx, o.AttributeDescriptor(attribute), data)
+}
+
+// InvokeContext is Invoke with a context, as dlms.ContextClient ActionRequestContext does.
+func (o Object) InvokeContext(ctx context.Context, c dlms.Client, method int8, data interface{}) error {
+	return dlms.AsContextClient(c).ActionRequestContext(ctx, o.MethodDescriptor(method), data)
+}
+
+// LogicalNameFromHex converts a logical name received as octet-string into the
+// notation used by dlms.CreateObis (e.g. "0100010800ff" to "1.0.1.8.0.255").
+func LogicalNameFromHex(value string) (string, error) {
+	src, err := hex.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid logical name %s: %w", value, err)
+	}
+
+	if len(src) != 6 {
+		return "", fmt.Errorf("invalid logical name %s: expected 6 bytes, got %d", value, len(src))
+	}
+
+	obis, err := dlms.DecodeObis(&src)
+	if err != nil {
+		return "", err
+	}
+
+	return obis.String(), nil
+}
+
+// integerZero returns the parameter of the methods that do not need any (integer 0).
+func integerZero() *axdr.DlmsData {
+	return axdr.CreateAxdrInteger(0)
+}
@@ -0,0 +1,174 @@
+package cosem_test
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// fakeClient answers get requests with encoded data and records the data of the
+// set and action requests, all of them indexed by descriptor.
+type fakeClient struct {
+	dlms.ContextClient
+	attributes map[string]string
+	written    map[string]*axdr.DlmsData
+	invoked    map[string]*axdr.DlmsData
+	ranges     []string
+	buffer     []string
+	failures   int
+	entries    []dlms.EntryDescriptor
+	settings   dlms.Settings
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		attributes: make(map[string]string),
+		written:    make(map[string]*axdr.DlmsData),
+		invoked:    make(map[string]*axdr.DlmsData),
+	}
+}
+
+func (c *fakeClient) GetSettings() dlms.Settings {
+	return c.settings
+}
+
+func (c *fakeClient) GetRequest(att *dlms.AttributeDescriptor, data interface{}) error {
+	value, ok := c.attributes[att.String()]
+	if !ok {
+		return dlms.NewError(dlms.ErrorGetRejected, fmt.Sprintf("unexpected get %s", att.String()))
+	}
+
+	src := decodeHexString(value)
+	dec := axdr.NewDataDecoder(&src)
+	dt, err := dec.Decode(&src)
+	if err != nil {
+		return err
+	}
+
+	return axdr.UnmarshalData(dt, data)
+}
+
+func (c *fakeClient) GetRequestWithList(atts []*dlms.AttributeDescriptor, data []interface{}) error {
+	for i, att := range atts {
+		if err := c.GetRequest(att, data[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetRequestWithRange answers with the queued ranges, in order.
+func (c *fakeClient) GetRequestWithRange(att *dlms.AttributeDescriptor, rd dlms.RangeDescriptor, data interface{}) error {
+	if len(c.ranges) == 0 {
+		return dlms.NewError(dlms.ErrorGetRejected, fmt.Sprintf("unexpected get %s with range", att.String()))
+	}
+
+	src := decodeHexString(c.ranges[0])
+	c.ranges = c.ranges[1:]
+
+	dec := axdr.NewDataDecoder(&src)
+	dt, err := dec.Decode(&src)
+	if err != nil {
+		return err
+	}
+
+	return axdr.UnmarshalData(dt, data)
+}
+
+// GetRequestWithEntries answers with the rows of the buffer requested, after
+// failing the number of times set in failures.
+func (c *fakeClient) GetRequestWithEntries(att *dlms.AttributeDescriptor, ed dlms.EntryDescriptor, data interface{}) error {
+	c.entries = append(c.entries, ed)
+
+	if c.failures > 0 {
+		c.failures--
+		return dlms.NewError(dlms.ErrorCommunicationFailed, "connection lost")
+	}
+
+	if ed.FromEntry < 1 || int(ed.ToEntry) > len(c.buffer) {
+		return dlms.NewError(dlms.ErrorGetRejected, fmt.Sprintf("unexpected get %s with entries %v", att.String(), ed))
+	}
+
+	rows := c.buffer[ed.FromEntry-1 : ed.ToEntry]
+	src := decodeHexString(fmt.Sprintf("01%02X%s", len(rows), strings.Join(rows, "")))
+
+	dec := axdr.NewDataDecoder(&src)
+	dt, err := dec.Decode(&src)
+	if err != nil {
+		return err
+	}
+
+	return axdr.UnmarshalData(dt, data)
+}
+
+// GetRequestRowsContext yields the elements of the array of an attribute.
+func (c *fakeClient) GetRequestRowsContext(_ context.Context, att *dlms.AttributeDescriptor, _ *dlms.SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error] {
+	return func(yield func(axdr.DlmsData, error) bool) {
+		var rows []axdr.DlmsData
+		if err := c.GetRequest(att, &rows); err != nil {
+			yield(axdr.DlmsData{}, err)
+			return
+		}
+
+		for _, row := range rows {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *fakeClient) SetRequest(att *dlms.AttributeDescriptor, data interface{}) error {
+	dt, err := marshal(data)
+	c.written[att.String()] = dt
+
+	return err
+}
+
+func (c *fakeClient) ActionRequest(mth *dlms.MethodDescriptor, data interface{}) error {
+	dt, err := marshal(data)
+	c.invoked[mth.String()] = dt
+
+	return err
+}
+
+func marshal(data interface{}) (*axdr.DlmsData, error) {
+	if dt, ok := data.(*axdr.DlmsData); ok {
+		return dt, nil
+	}
+
+	return axdr.MarshalData(data)
+}
+
+func decodeHexString(s string) []byte {
+	b, _ := hex.DecodeString(s)
+	return b
+}
+
+func TestObject(t *testing.T) {
+	o := cosem.Object{ClassID: cosem.ClassIDRegister, LogicalName: "1-0:1.8.0.255"}
+
+	assert.Equal(t, "{ 3, 1.0.1.8.0.255, 2 }", o.AttributeDescriptor(2).String())
+	assert.Equal(t, "{ 3, 1.0.1.8.0.255, 1 }", o.MethodDescriptor(1).String())
+}
+
+func TestLogicalNameFromHex(t *testing.T) {
+	ln, err := cosem.LogicalNameFromHex("0100010800ff")
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.1.8.0.255", ln)
+
+	_, err = cosem.LogicalNameFromHex("01000108")
+	assert.Error(t, err)
+
+	_, err = cosem.LogicalNameFromHex("zz")
+	assert.Error(t, err)
+}
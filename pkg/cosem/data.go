@@ -0,0 +1,28 @@
+package cosem
+
+import (
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Data (class ID 1, version 0) holds a value of any type.
+const (
+	DataAttributeValue int8 = 2
+)
+
+type Data struct {
+	Object
+}
+
+func NewData(logicalName string) *Data {
+	return &Data{Object{ClassID: ClassIDData, LogicalName: logicalName}}
+}
+
+// Read reads the value attribute into v.
+func (d *Data) Read(c dlms.Client, v interface{}) error {
+	return d.Get(c, DataAttributeValue, v)
+}
+
+// Write writes the value attribute.
+func (d *Data) Write(c dlms.Client, v interface{}) error {
+	return d.Set(c, DataAttributeValue, v)
+}
@@ -0,0 +1,78 @@
+package cosem
+
+import (
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Demand Register (class ID 5, version 0) stores a demand value calculated over
+// sliding periods.
+const (
+	DemandRegisterAttributeCurrentAverageValue int8 = 2
+	DemandRegisterAttributeLastAverageValue    int8 = 3
+	DemandRegisterAttributeScalerUnit          int8 = 4
+	DemandRegisterAttributeStatus              int8 = 5
+	DemandRegisterAttributeCaptureTime         int8 = 6
+	DemandRegisterAttributeStartTimeCurrent    int8 = 7
+	DemandRegisterAttributePeriod              int8 = 8
+	DemandRegisterAttributeNumberOfPeriods     int8 = 9
+
+	DemandRegisterMethodReset      int8 = 1
+	DemandRegisterMethodNextPeriod int8 = 2
+)
+
+type DemandRegister struct {
+	Object
+}
+
+func NewDemandRegister(logicalName string) *DemandRegister {
+	return &DemandRegister{Object{ClassID: ClassIDDemandRegister, LogicalName: logicalName}}
+}
+
+// ReadCurrentAverage reads the demand of the running period into v. The value is not scaled.
+func (r *DemandRegister) ReadCurrentAverage(c dlms.Client, v interface{}) error {
+	return r.Get(c, DemandRegisterAttributeCurrentAverageValue, v)
+}
+
+// ReadLastAverage reads the demand of the last completed period into v. The value is not scaled.
+func (r *DemandRegister) ReadLastAverage(c dlms.Client, v interface{}) error {
+	return r.Get(c, DemandRegisterAttributeLastAverageValue, v)
+}
+
+func (r *DemandRegister) ReadScalerUnit(c dlms.Client) (su dlms.ScalerUnit, err error) {
+	err = r.Get(c, DemandRegisterAttributeScalerUnit, &su)
+	return
+}
+
+func (r *DemandRegister) ReadCaptureTime(c dlms.Client) (t time.Time, err error) {
+	err = r.Get(c, DemandRegisterAttributeCaptureTime, &t)
+	return
+}
+
+func (r *DemandRegister) ReadStartTimeCurrent(c dlms.Client) (t time.Time, err error) {
+	err = r.Get(c, DemandRegisterAttributeStartTimeCurrent, &t)
+	return
+}
+
+// ReadPeriod returns the length of each period.
+func (r *DemandRegister) ReadPeriod(c dlms.Client) (time.Duration, error) {
+	var seconds uint32
+	err := r.Get(c, DemandRegisterAttributePeriod, &seconds)
+
+	return time.Duration(seconds) * time.Second, err
+}
+
+func (r *DemandRegister) ReadNumberOfPeriods(c dlms.Client) (n uint16, err error) {
+	err = r.Get(c, DemandRegisterAttributeNumberOfPeriods, &n)
+	return
+}
+
+func (r *DemandRegister) Reset(c dlms.Client) error {
+	return r.Invoke(c, DemandRegisterMethodReset, integerZero())
+}
+
+// NextPeriod closes the running period and starts a new one.
+func (r *DemandRegister) NextPeriod(c dlms.Client) error {
+	return r.Invoke(c, DemandRegisterMethodNextPeriod, integerZero())
+}
@@ -0,0 +1,171 @@
+package cosem
+
+import (
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Disconnect Control (class ID 70, version 0) manages the supply disconnector of the meter.
+const (
+	DisconnectControlAttributeOutputState  int8 = 2
+	DisconnectControlAttributeControlState int8 = 3
+	DisconnectControlAttributeControlMode  int8 = 4
+
+	DisconnectControlMethodRemoteDisconnect int8 = 1
+	DisconnectControlMethodRemoteReconnect  int8 = 2
+)
+
+// ControlState is the internal state of the disconnector
+type ControlState uint8
+
+const (
+	ControlStateDisconnected ControlState = iota
+	ControlStateConnected
+	ControlStateReadyForReconnection
+)
+
+func (s ControlState) String() string {
+	switch s {
+	case ControlStateDisconnected:
+		return "disconnected"
+	case ControlStateConnected:
+		return "connected"
+	case ControlStateReadyForReconnection:
+		return "ready for reconnection"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(s))
+	}
+}
+
+// ControlMode configures which transitions between states are allowed (0 to 6).
+// Remote disconnection is allowed in every mode but ControlModeNone, and remote
+// reconnection leaves the disconnector ready for (manual) reconnection in every
+// mode but 2 and 4, where it connects the supply directly.
+type ControlMode uint8
+
+const (
+	ControlModeNone ControlMode = iota // The disconnector is always connected
+	ControlMode1
+	ControlMode2
+	ControlMode3
+	ControlMode4
+	ControlMode5
+	ControlMode6
+)
+
+// RemoteTransition returns the state reached from the given one by the remote
+// disconnect or reconnect method, or dlms.ErrorInvalidState if the mode does not
+// allow it.
+func (m ControlMode) RemoteTransition(state ControlState, method int8) (ControlState, error) {
+	if m == ControlModeNone || m > ControlMode6 {
+		return state, dlms.NewError(dlms.ErrorInvalidState, fmt.Sprintf("remote control not allowed in control mode %d", m))
+	}
+
+	switch method {
+	case DisconnectControlMethodRemoteDisconnect:
+		if state != ControlStateConnected && state != ControlStateReadyForReconnection {
+			return state, dlms.NewError(dlms.ErrorInvalidState, fmt.Sprintf("remote disconnect not allowed when %s", state))
+		}
+
+		return ControlStateDisconnected, nil
+	case DisconnectControlMethodRemoteReconnect:
+		if state != ControlStateDisconnected {
+			return state, dlms.NewError(dlms.ErrorInvalidState, fmt.Sprintf("remote reconnect not allowed when %s", state))
+		}
+
+		if m == ControlMode2 || m == ControlMode4 {
+			return ControlStateConnected, nil
+		}
+
+		return ControlStateReadyForReconnection, nil
+	default:
+		return state, dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("unknown method %d", method))
+	}
+}
+
+// DisconnectControlStatus is the state of a disconnector
+type DisconnectControlStatus struct {
+	OutputState  bool // true if the supply is connected
+	ControlState ControlState
+	ControlMode  ControlMode
+}
+
+type DisconnectControl struct {
+	Object
+}
+
+func NewDisconnectControl(logicalName string) *DisconnectControl {
+	return &DisconnectControl{Object{ClassID: ClassIDDisconnectControl, LogicalName: logicalName}}
+}
+
+// ReadOutputState returns true if the supply is connected.
+func (d *DisconnectControl) ReadOutputState(c dlms.Client) (connected bool, err error) {
+	err = d.Get(c, DisconnectControlAttributeOutputState, &connected)
+	return
+}
+
+func (d *DisconnectControl) ReadControlState(c dlms.Client) (state ControlState, err error) {
+	err = d.Get(c, DisconnectControlAttributeControlState, &state)
+	return
+}
+
+func (d *DisconnectControl) ReadControlMode(c dlms.Client) (mode ControlMode, err error) {
+	err = d.Get(c, DisconnectControlAttributeControlMode, &mode)
+	return
+}
+
+func (d *DisconnectControl) WriteControlMode(c dlms.Client, mode ControlMode) error {
+	if mode > ControlMode6 {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid control mode %d", mode))
+	}
+
+	return d.Set(c, DisconnectControlAttributeControlMode, axdr.CreateAxdrEnum(uint8(mode)))
+}
+
+// ReadStatus returns the output state, control state and control mode, in a
+// single request if the meter allows it.
+func (d *DisconnectControl) ReadStatus(c dlms.Client) (status DisconnectControlStatus, err error) {
+	atts := []*dlms.AttributeDescriptor{
+		d.AttributeDescriptor(DisconnectControlAttributeOutputState),
+		d.AttributeDescriptor(DisconnectControlAttributeControlState),
+		d.AttributeDescriptor(DisconnectControlAttributeControlMode),
+	}
+
+	err = c.GetRequestWithList(atts, []interface{}{&status.OutputState, &status.ControlState, &status.ControlMode})
+	return
+}
+
+// RemoteDisconnect disconnects the supply. The control state and mode are read
+// first, and the method is not invoked if they do not allow it. It returns the
+// control state expected after the disconnection.
+func (d *DisconnectControl) RemoteDisconnect(c dlms.Client) (ControlState, error) {
+	return d.remoteControl(c, DisconnectControlMethodRemoteDisconnect)
+}
+
+// RemoteReconnect reconnects the supply, or allows the customer to do it,
+// depending on the control mode. The control state and mode are read first, and
+// the method is not invoked if they do not allow it. It returns the control state
+// expected after the reconnection.
+func (d *DisconnectControl) RemoteReconnect(c dlms.Client) (ControlState, error) {
+	return d.remoteControl(c, DisconnectControlMethodRemoteReconnect)
+}
+
+func (d *DisconnectControl) remoteControl(c dlms.Client, method int8) (ControlState, error) {
+	status, err := d.ReadStatus(c)
+	if err != nil {
+		return 0, err
+	}
+
+	state, err := status.ControlMode.RemoteTransition(status.ControlState, method)
+	if err != nil {
+		return status.ControlState, err
+	}
+
+	if err := d.Invoke(c, method, integerZero()); err != nil {
+		return status.ControlState, err
+	}
+
+	return state, nil
+}
@@ -0,0 +1,98 @@
+package cosem_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func TestDisconnectControl(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 70, 0.0.96.3.10.255, 2 }"] = "0301"
+	c.attributes["{ 70, 0.0.96.3.10.255, 3 }"] = "1601"
+	c.attributes["{ 70, 0.0.96.3.10.255, 4 }"] = "1601"
+
+	d := cosem.NewDisconnectControl("0-0:96.3.10.255")
+
+	connected, err := d.ReadOutputState(c)
+	assert.NoError(t, err)
+	assert.True(t, connected)
+
+	state, err := d.ReadControlState(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.ControlStateConnected, state)
+
+	status, err := d.ReadStatus(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.DisconnectControlStatus{OutputState: true, ControlState: cosem.ControlStateConnected, ControlMode: cosem.ControlMode1}, status)
+
+	state, err = d.RemoteDisconnect(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.ControlStateDisconnected, state)
+	assert.Equal(t, axdr.CreateAxdrInteger(0), c.invoked["{ 70, 0.0.96.3.10.255, 1 }"])
+
+	// Already connected: reconnection is not sent
+	_, err = d.RemoteReconnect(c)
+	assertInvalidState(t, err)
+	assert.NotContains(t, c.invoked, "{ 70, 0.0.96.3.10.255, 2 }")
+
+	c.attributes["{ 70, 0.0.96.3.10.255, 3 }"] = "1600"
+
+	state, err = d.RemoteReconnect(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.ControlStateReadyForReconnection, state)
+	assert.Contains(t, c.invoked, "{ 70, 0.0.96.3.10.255, 2 }")
+
+	err = d.WriteControlMode(c, cosem.ControlMode4)
+	assert.NoError(t, err)
+	require.Contains(t, c.written, "{ 70, 0.0.96.3.10.255, 4 }")
+	out, err := c.written["{ 70, 0.0.96.3.10.255, 4 }"].Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{byte(axdr.TagEnum), 0x04}, out)
+
+	err = d.WriteControlMode(c, 7)
+	assert.Error(t, err)
+}
+
+func TestControlMode_RemoteTransition(t *testing.T) {
+	tests := []struct {
+		mode   cosem.ControlMode
+		state  cosem.ControlState
+		method int8
+		want   cosem.ControlState
+		ok     bool
+	}{
+		{cosem.ControlModeNone, cosem.ControlStateConnected, cosem.DisconnectControlMethodRemoteDisconnect, cosem.ControlStateConnected, false},
+		{cosem.ControlMode1, cosem.ControlStateConnected, cosem.DisconnectControlMethodRemoteDisconnect, cosem.ControlStateDisconnected, true},
+		{cosem.ControlMode3, cosem.ControlStateReadyForReconnection, cosem.DisconnectControlMethodRemoteDisconnect, cosem.ControlStateDisconnected, true},
+		{cosem.ControlMode1, cosem.ControlStateDisconnected, cosem.DisconnectControlMethodRemoteDisconnect, cosem.ControlStateDisconnected, false},
+		{cosem.ControlMode1, cosem.ControlStateDisconnected, cosem.DisconnectControlMethodRemoteReconnect, cosem.ControlStateReadyForReconnection, true},
+		{cosem.ControlMode2, cosem.ControlStateDisconnected, cosem.DisconnectControlMethodRemoteReconnect, cosem.ControlStateConnected, true},
+		{cosem.ControlMode4, cosem.ControlStateDisconnected, cosem.DisconnectControlMethodRemoteReconnect, cosem.ControlStateConnected, true},
+		{cosem.ControlMode6, cosem.ControlStateReadyForReconnection, cosem.DisconnectControlMethodRemoteReconnect, cosem.ControlStateReadyForReconnection, false},
+		{7, cosem.ControlStateConnected, cosem.DisconnectControlMethodRemoteDisconnect, cosem.ControlStateConnected, false},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.mode.RemoteTransition(tt.state, tt.method)
+		if tt.ok {
+			assert.NoError(t, err)
+		} else {
+			assertInvalidState(t, err)
+		}
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func assertInvalidState(t *testing.T, err error) {
+	t.Helper()
+
+	var dlmsError *dlms.Error
+	require.True(t, errors.As(err, &dlmsError))
+	assert.Equal(t, dlms.ErrorInvalidState, dlmsError.Code())
+}
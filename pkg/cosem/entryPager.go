@@ -0,0 +1,92 @@
+package cosem
+
+import (
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// EntryPager reads the buffer of a Profile Generic in pages of entries, so large
+// profiles can be read incrementally. A page that fails can be read again calling
+// Next once more, and a pager created with NewEntryPagerFrom continues a reading
+// interrupted before, e.g. after a disconnection.
+//
+// The number of entries is read when the first page is requested. Entries
+// captured later are not read, and if the buffer is full each new capture shifts
+// the entries by one, so pages should be read in a short time.
+type EntryPager struct {
+	profile    *ProfileGeneric
+	client     dlms.Client
+	pageSize   uint32
+	next       uint32
+	total      uint32
+	started    bool
+	fromColumn uint16
+	toColumn   uint16
+}
+
+// NewEntryPager returns a pager that reads the buffer from its first entry.
+func (p *ProfileGeneric) NewEntryPager(c dlms.Client, pageSize uint32) *EntryPager {
+	return p.NewEntryPagerFrom(c, pageSize, 1)
+}
+
+// NewEntryPagerFrom returns a pager that reads the buffer starting with the entry
+// given (numbered from 1), usually the Position of a previous pager.
+func (p *ProfileGeneric) NewEntryPagerFrom(c dlms.Client, pageSize uint32, entry uint32) *EntryPager {
+	if pageSize < 1 {
+		pageSize = 1
+	}
+
+	if entry < 1 {
+		entry = 1
+	}
+
+	return &EntryPager{profile: p, client: c, pageSize: pageSize, next: entry}
+}
+
+// SetColumns restricts the columns read (numbered from 1, a 0 in to means up to
+// the last one).
+func (e *EntryPager) SetColumns(from uint16, to uint16) {
+	e.fromColumn = from
+	e.toColumn = to
+}
+
+// Position returns the next entry to be read.
+func (e *EntryPager) Position() uint32 {
+	return e.next
+}
+
+// Total returns the number of entries in use, once the first page is requested.
+func (e *EntryPager) Total() uint32 {
+	return e.total
+}
+
+// Next reads the next page of entries into v, as dlms.Client GetRequest does, and
+// returns false when there are no more entries to read.
+func (e *EntryPager) Next(v interface{}) (bool, error) {
+	if !e.started {
+		total, err := e.profile.ReadEntriesInUse(e.client)
+		if err != nil {
+			return false, err
+		}
+
+		e.total = total
+		e.started = true
+	}
+
+	if e.next > e.total {
+		return false, nil
+	}
+
+	last := e.next + e.pageSize - 1
+	if last > e.total || last < e.next {
+		last = e.total
+	}
+
+	ed := dlms.EntryDescriptor{FromEntry: e.next, ToEntry: last, FromColumn: e.fromColumn, ToColumn: e.toColumn}
+	if err := e.client.GetRequestWithEntries(e.profile.AttributeDescriptor(ProfileGenericAttributeBuffer), ed, v); err != nil {
+		return false, err
+	}
+
+	e.next = last + 1
+
+	return true, nil
+}
@@ -0,0 +1,86 @@
+package cosem_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func newPagedProfileClient(entries int) *fakeClient {
+	c := newFakeClient()
+	c.attributes["{ 7, 1.0.99.1.0.255, 7 }"] = fmt.Sprintf("06%08X", entries)
+
+	for i := 1; i <= entries; i++ {
+		c.buffer = append(c.buffer, fmt.Sprintf("020106%08X", i))
+	}
+
+	return c
+}
+
+func TestEntryPager(t *testing.T) {
+	c := newPagedProfileClient(5)
+
+	pager := cosem.NewProfileGeneric("1-0:99.1.0.255").NewEntryPager(c, 2)
+	pager.SetColumns(1, 1)
+
+	var values []uint32
+	for {
+		var page []struct{ Value uint32 }
+
+		more, err := pager.Next(&page)
+		require.NoError(t, err)
+
+		if !more {
+			break
+		}
+
+		for _, p := range page {
+			values = append(values, p.Value)
+		}
+	}
+
+	assert.Equal(t, []uint32{1, 2, 3, 4, 5}, values)
+	assert.Equal(t, uint32(5), pager.Total())
+	assert.Equal(t, uint32(6), pager.Position())
+	assert.Equal(t, []dlms.EntryDescriptor{
+		{FromEntry: 1, ToEntry: 2, FromColumn: 1, ToColumn: 1},
+		{FromEntry: 3, ToEntry: 4, FromColumn: 1, ToColumn: 1},
+		{FromEntry: 5, ToEntry: 5, FromColumn: 1, ToColumn: 1},
+	}, c.entries)
+}
+
+func TestEntryPager_Resume(t *testing.T) {
+	c := newPagedProfileClient(5)
+
+	p := cosem.NewProfileGeneric("1-0:99.1.0.255")
+	pager := p.NewEntryPager(c, 3)
+
+	var page []struct{ Value uint32 }
+
+	more, err := pager.Next(&page)
+	require.NoError(t, err)
+	assert.True(t, more)
+
+	// A failed page does not move the pager, so it can be retried
+	c.failures = 1
+	_, err = pager.Next(&page)
+	assert.Error(t, err)
+	assert.Equal(t, uint32(4), pager.Position())
+
+	// Or continued by another pager, e.g. after connecting again
+	pager = p.NewEntryPagerFrom(c, 3, pager.Position())
+
+	more, err = pager.Next(&page)
+	require.NoError(t, err)
+	assert.True(t, more)
+	assert.Len(t, page, 2)
+	assert.Equal(t, uint32(4), page[0].Value)
+
+	more, err = pager.Next(&page)
+	require.NoError(t, err)
+	assert.False(t, more)
+}
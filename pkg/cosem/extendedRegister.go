@@ -0,0 +1,36 @@
+package cosem
+
+import (
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Extended Register (class ID 4, version 0) is a register that also stores the
+// status and the time when the value was captured.
+const (
+	ExtendedRegisterAttributeValue       int8 = 2
+	ExtendedRegisterAttributeScalerUnit  int8 = 3
+	ExtendedRegisterAttributeStatus      int8 = 4
+	ExtendedRegisterAttributeCaptureTime int8 = 5
+
+	ExtendedRegisterMethodReset int8 = 1
+)
+
+type ExtendedRegister struct {
+	Register
+}
+
+func NewExtendedRegister(logicalName string) *ExtendedRegister {
+	return &ExtendedRegister{Register{Object{ClassID: ClassIDExtendedRegister, LogicalName: logicalName}}}
+}
+
+// ReadStatus reads the status attribute into v. Its type is manufacturer specific.
+func (r *ExtendedRegister) ReadStatus(c dlms.Client, v interface{}) error {
+	return r.Get(c, ExtendedRegisterAttributeStatus, v)
+}
+
+func (r *ExtendedRegister) ReadCaptureTime(c dlms.Client) (t time.Time, err error) {
+	err = r.Get(c, ExtendedRegisterAttributeCaptureTime, &t)
+	return
+}
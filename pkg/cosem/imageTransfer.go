@@ -0,0 +1,423 @@
+package cosem
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Image Transfer (class ID 18, version 0) upgrades the firmware of the meter: the
+// image is sent in blocks, verified and then activated.
+const (
+	ImageTransferAttributeBlockSize                  int8 = 2
+	ImageTransferAttributeTransferredBlocksStatus    int8 = 3
+	ImageTransferAttributeFirstNotTransferredBlock   int8 = 4
+	ImageTransferAttributeTransferEnabled            int8 = 5
+	ImageTransferAttributeTransferStatus             int8 = 6
+	ImageTransferAttributeImageToActivateInformation int8 = 7
+
+	ImageTransferMethodInitiate      int8 = 1
+	ImageTransferMethodBlockTransfer int8 = 2
+	ImageTransferMethodVerify        int8 = 3
+	ImageTransferMethodActivate      int8 = 4
+)
+
+// ImageTransferLogicalName is the logical name of the image transfer of the meter
+const ImageTransferLogicalName = "0-0:44.0.0.255"
+
+// ImageTransferStatus is the state of the image transfer process
+type ImageTransferStatus uint8
+
+const (
+	ImageTransferStatusNotInitiated ImageTransferStatus = iota
+	ImageTransferStatusInitiated
+	ImageTransferStatusVerificationInitiated
+	ImageTransferStatusVerificationSuccessful
+	ImageTransferStatusVerificationFailed
+	ImageTransferStatusActivationInitiated
+	ImageTransferStatusActivationSuccessful
+	ImageTransferStatusActivationFailed
+)
+
+func (s ImageTransferStatus) String() string {
+	switch s {
+	case ImageTransferStatusNotInitiated:
+		return "not initiated"
+	case ImageTransferStatusInitiated:
+		return "initiated"
+	case ImageTransferStatusVerificationInitiated:
+		return "verification initiated"
+	case ImageTransferStatusVerificationSuccessful:
+		return "verification successful"
+	case ImageTransferStatusVerificationFailed:
+		return "verification failed"
+	case ImageTransferStatusActivationInitiated:
+		return "activation initiated"
+	case ImageTransferStatusActivationSuccessful:
+		return "activation successful"
+	case ImageTransferStatusActivationFailed:
+		return "activation failed"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint8(s))
+	}
+}
+
+// ImageToActivate is the information of an image verified and ready to be activated
+type ImageToActivate struct {
+	Size           uint32
+	Identification []byte
+	Signature      []byte
+}
+
+// ImageTransferProgress is reported while the image is upgraded
+type ImageTransferProgress struct {
+	Status            ImageTransferStatus
+	BlocksTransferred uint32
+	BlocksTotal       uint32
+}
+
+// ImageTransferOptions configures Upgrade. The zero value is valid.
+type ImageTransferOptions struct {
+	// Progress is called after each block sent and each change of status.
+	Progress func(ImageTransferProgress)
+	// PollInterval is the time between reads of the status while the image is
+	// verified or activated (5 seconds if zero). Polling ends with the context.
+	PollInterval time.Duration
+	// MaxWait limits the time polling the status of the verification or the
+	// activation (10 minutes if zero), even if the context has no deadline.
+	MaxWait time.Duration
+	// MaxRetries is the number of times the missing blocks are sent again (3 if zero).
+	MaxRetries int
+	// SkipActivation leaves the image verified, to be activated later with Activate.
+	SkipActivation bool
+}
+
+func (o ImageTransferOptions) pollInterval() time.Duration {
+	if o.PollInterval <= 0 {
+		return 5 * time.Second
+	}
+
+	return o.PollInterval
+}
+
+func (o ImageTransferOptions) maxWait() time.Duration {
+	if o.MaxWait <= 0 {
+		return 10 * time.Minute
+	}
+
+	return o.MaxWait
+}
+
+func (o ImageTransferOptions) maxRetries() int {
+	if o.MaxRetries <= 0 {
+		return 3
+	}
+
+	return o.MaxRetries
+}
+
+func (o ImageTransferOptions) report(p ImageTransferProgress) {
+	if o.Progress != nil {
+		o.Progress(p)
+	}
+}
+
+type ImageTransfer struct {
+	Object
+}
+
+// NewImageTransfer returns the image transfer with the given logical name, or the
+// image transfer of the meter (ImageTransferLogicalName) if it is empty.
+func NewImageTransfer(logicalName string) *ImageTransfer {
+	if logicalName == "" {
+		logicalName = ImageTransferLogicalName
+	}
+
+	return &ImageTransfer{Object{ClassID: ClassIDImageTransfer, LogicalName: logicalName}}
+}
+
+func (it *ImageTransfer) ReadBlockSize(c dlms.Client) (size uint32, err error) {
+	err = it.Get(c, ImageTransferAttributeBlockSize, &size)
+	return
+}
+
+// ReadTransferredBlocksStatus returns which blocks have been transferred, up to
+// the last one transferred.
+func (it *ImageTransfer) ReadTransferredBlocksStatus(c dlms.Client) ([]bool, error) {
+	return it.readTransferredBlocksStatus(context.Background(), c)
+}
+
+func (it *ImageTransfer) readTransferredBlocksStatus(ctx context.Context, c dlms.Client) ([]bool, error) {
+	var bits string
+	if err := it.GetContext(ctx, c, ImageTransferAttributeTransferredBlocksStatus, &bits); err != nil {
+		return nil, err
+	}
+
+	status := make([]bool, len(bits))
+	for i, b := range bits {
+		status[i] = b == '1'
+	}
+
+	return status, nil
+}
+
+func (it *ImageTransfer) ReadFirstNotTransferredBlock(c dlms.Client) (block uint32, err error) {
+	err = it.Get(c, ImageTransferAttributeFirstNotTransferredBlock, &block)
+	return
+}
+
+func (it *ImageTransfer) ReadTransferEnabled(c dlms.Client) (enabled bool, err error) {
+	err = it.Get(c, ImageTransferAttributeTransferEnabled, &enabled)
+	return
+}
+
+func (it *ImageTransfer) ReadTransferStatus(c dlms.Client) (status ImageTransferStatus, err error) {
+	err = it.Get(c, ImageTransferAttributeTransferStatus, &status)
+	return
+}
+
+func (it *ImageTransfer) ReadImageToActivate(c dlms.Client) ([]ImageToActivate, error) {
+	var raw []struct {
+		Size           uint32
+		Identification string
+		Signature      string
+	}
+
+	if err := it.Get(c, ImageTransferAttributeImageToActivateInformation, &raw); err != nil {
+		return nil, err
+	}
+
+	images := make([]ImageToActivate, len(raw))
+	for i, r := range raw {
+		identification, err := hex.DecodeString(r.Identification)
+		if err != nil {
+			return nil, dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("invalid identification of image %d: %v", i, err))
+		}
+
+		signature, err := hex.DecodeString(r.Signature)
+		if err != nil {
+			return nil, dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("invalid signature of image %d: %v", i, err))
+		}
+
+		images[i] = ImageToActivate{Size: r.Size, Identification: identification, Signature: signature}
+	}
+
+	return images, nil
+}
+
+// Initiate starts (or resumes, if the identifier is the one being transferred)
+// the transfer of an image.
+func (it *ImageTransfer) Initiate(c dlms.Client, identifier []byte, size uint32) error {
+	return it.initiate(context.Background(), c, identifier, size)
+}
+
+func (it *ImageTransfer) initiate(ctx context.Context, c dlms.Client, identifier []byte, size uint32) error {
+	data := axdr.CreateAxdrStructure([]*axdr.DlmsData{
+		axdr.CreateAxdrOctetString(hex.EncodeToString(identifier)),
+		axdr.CreateAxdrDoubleLongUnsigned(size),
+	})
+
+	return it.InvokeContext(ctx, c, ImageTransferMethodInitiate, data)
+}
+
+// TransferBlock sends a block of the image, numbered from 0.
+func (it *ImageTransfer) TransferBlock(c dlms.Client, number uint32, block []byte) error {
+	return it.transferBlock(context.Background(), c, number, block)
+}
+
+func (it *ImageTransfer) transferBlock(ctx context.Context, c dlms.Client, number uint32, block []byte) error {
+	data := axdr.CreateAxdrStructure([]*axdr.DlmsData{
+		axdr.CreateAxdrDoubleLongUnsigned(number),
+		axdr.CreateAxdrOctetString(hex.EncodeToString(block)),
+	})
+
+	return it.InvokeContext(ctx, c, ImageTransferMethodBlockTransfer, data)
+}
+
+// Upgrade transfers the image, sending again the blocks the meter has not
+// received, and then verifies and activates it. A transfer interrupted before can
+// be resumed calling Upgrade again with the same identifier: only the blocks
+// missing in the meter are sent.
+func (it *ImageTransfer) Upgrade(ctx context.Context, c dlms.Client, identifier []byte, image []byte, opts ImageTransferOptions) error {
+	var blockSize uint32
+	if err := it.GetContext(ctx, c, ImageTransferAttributeBlockSize, &blockSize); err != nil {
+		return err
+	}
+
+	if blockSize == 0 {
+		return dlms.NewError(dlms.ErrorInvalidResponse, "image block size is 0")
+	}
+
+	var enabled bool
+	if err := it.GetContext(ctx, c, ImageTransferAttributeTransferEnabled, &enabled); err != nil {
+		return err
+	}
+
+	if !enabled {
+		return dlms.NewError(dlms.ErrorInvalidState, "image transfer is not enabled")
+	}
+
+	if err := it.initiate(ctx, c, identifier, uint32(len(image))); err != nil {
+		return err
+	}
+
+	if err := it.transferBlocks(ctx, c, image, blockSize, opts); err != nil {
+		return err
+	}
+
+	if err := it.verify(ctx, c, opts); err != nil {
+		return err
+	}
+
+	if opts.SkipActivation {
+		return nil
+	}
+
+	return it.activate(ctx, c, opts)
+}
+
+func (it *ImageTransfer) transferBlocks(ctx context.Context, c dlms.Client, image []byte, blockSize uint32, opts ImageTransferOptions) error {
+	total := uint32((uint64(len(image)) + uint64(blockSize) - 1) / uint64(blockSize))
+
+	for retry := 0; ; retry++ {
+		transferred, err := it.readTransferredBlocksStatus(ctx, c)
+		if err != nil {
+			return err
+		}
+
+		var missing []uint32
+		for i := uint32(0); i < total; i++ {
+			if i >= uint32(len(transferred)) || !transferred[i] {
+				missing = append(missing, i)
+			}
+		}
+
+		if len(missing) == 0 {
+			return nil
+		}
+
+		if retry > opts.maxRetries() {
+			return dlms.NewError(dlms.ErrorActionRejected, fmt.Sprintf("%d blocks of the image not transferred", len(missing)))
+		}
+
+		done := total - uint32(len(missing))
+		opts.report(ImageTransferProgress{Status: ImageTransferStatusInitiated, BlocksTransferred: done, BlocksTotal: total})
+
+		for _, n := range missing {
+			start := uint64(n) * uint64(blockSize)
+			end := start + uint64(blockSize)
+			if end > uint64(len(image)) {
+				end = uint64(len(image))
+			}
+
+			if err := it.transferBlock(ctx, c, n, image[start:end]); err != nil {
+				// A block rejected is sent again in the next round
+				var dlmsError *dlms.Error
+				if !errors.As(err, &dlmsError) || dlmsError.Code() != dlms.ErrorActionRejected {
+					return err
+				}
+
+				continue
+			}
+
+			done++
+			opts.report(ImageTransferProgress{Status: ImageTransferStatusInitiated, BlocksTransferred: done, BlocksTotal: total})
+		}
+	}
+}
+
+// Verify checks the integrity of the image transferred and waits until the meter
+// has verified it, the context ends or opts.MaxWait passes.
+func (it *ImageTransfer) Verify(ctx context.Context, c dlms.Client, opts ImageTransferOptions) error {
+	return it.verify(ctx, c, opts)
+}
+
+func (it *ImageTransfer) verify(ctx context.Context, c dlms.Client, opts ImageTransferOptions) error {
+	return it.invokeAndWait(ctx, c, ImageTransferMethodVerify, opts, ImageTransferStatusInitiated,
+		ImageTransferStatusVerificationInitiated, ImageTransferStatusVerificationSuccessful, ImageTransferStatusVerificationFailed)
+}
+
+// Activate activates the image verified and waits until the meter has activated
+// it, the context ends or opts.MaxWait passes. Errors while polling are ignored, as
+// the meter may restart to activate the image.
+func (it *ImageTransfer) Activate(ctx context.Context, c dlms.Client, opts ImageTransferOptions) error {
+	return it.activate(ctx, c, opts)
+}
+
+func (it *ImageTransfer) activate(ctx context.Context, c dlms.Client, opts ImageTransferOptions) error {
+	return it.invokeAndWait(ctx, c, ImageTransferMethodActivate, opts, ImageTransferStatusVerificationSuccessful,
+		ImageTransferStatusActivationInitiated, ImageTransferStatusActivationSuccessful, ImageTransferStatusActivationFailed)
+}
+
+// invokeAndWait invokes the verify or activate method and polls the status while
+// it is in progress, or still the previous one (pending) as the meter may start the
+// operation later. The method may be rejected (temporary failure) when the
+// operation takes long, so the status decides the result.
+func (it *ImageTransfer) invokeAndWait(ctx context.Context, c dlms.Client, method int8, opts ImageTransferOptions,
+	pending ImageTransferStatus, inProgress ImageTransferStatus, successful ImageTransferStatus, failed ImageTransferStatus,
+) error {
+	errInvoke := it.InvokeContext(ctx, c, method, integerZero())
+	if errInvoke != nil {
+		var dlmsError *dlms.Error
+		if !errors.As(errInvoke, &dlmsError) || dlmsError.Code() != dlms.ErrorActionRejected {
+			return errInvoke
+		}
+	}
+
+	maxWait := time.NewTimer(opts.maxWait())
+	defer maxWait.Stop()
+
+	reported := false
+	status := pending
+
+	for {
+		err := it.GetContext(ctx, c, ImageTransferAttributeTransferStatus, &status)
+
+		if err == nil {
+			if !reported || status != inProgress {
+				opts.report(ImageTransferProgress{Status: status})
+				reported = true
+			}
+
+			switch status {
+			case successful:
+				return nil
+			case failed:
+				return dlms.NewError(dlms.ErrorActionRejected, fmt.Sprintf("image transfer %s", status))
+			case pending, inProgress:
+			default:
+				if errInvoke != nil {
+					return errInvoke
+				}
+
+				return dlms.NewError(dlms.ErrorInvalidState, fmt.Sprintf("unexpected image transfer status: %s", status))
+			}
+		} else if method != ImageTransferMethodActivate {
+			return err
+		}
+
+		timer := time.NewTimer(opts.pollInterval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err != nil {
+				return dlms.NewErrorWithCause(dlms.ErrorCanceled, fmt.Sprintf("image transfer status unknown: %v", err), ctx.Err())
+			}
+
+			return dlms.NewErrorWithCause(dlms.ErrorCanceled, fmt.Sprintf("image transfer still %s", status), ctx.Err())
+		case <-maxWait.C:
+			timer.Stop()
+			if err != nil {
+				return dlms.NewError(dlms.ErrorActionRejected, fmt.Sprintf("image transfer status unknown after %v: %v", opts.maxWait(), err))
+			}
+
+			return dlms.NewError(dlms.ErrorActionRejected, fmt.Sprintf("image transfer still %s after %v", status, opts.maxWait()))
+		case <-timer.C:
+		}
+	}
+}
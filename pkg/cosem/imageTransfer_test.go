@@ -0,0 +1,326 @@
+package cosem_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// simulatedMeter implements the image transfer of a meter
+type simulatedMeter struct {
+	dlms.ContextClient
+	blockSize  uint32
+	enabled    bool
+	identifier []byte
+	size       uint32
+	blocks     map[uint32][]byte
+	status     cosem.ImageTransferStatus
+	// lost are the blocks accepted but not stored the first time they are sent
+	lost map[uint32]bool
+	// sent are the numbers of the blocks received, in order
+	sent []uint32
+	// pending is the number of polls before the verification or activation ends
+	pending    int
+	activated  []byte
+	unreadable int
+	// lateVerify keeps the status initiated when the verification is invoked
+	lateVerify bool
+	// failActivation fails the activation of the image
+	failActivation bool
+}
+
+func newSimulatedMeter(blockSize uint32) *simulatedMeter {
+	return &simulatedMeter{blockSize: blockSize, enabled: true, blocks: make(map[uint32][]byte), lost: make(map[uint32]bool)}
+}
+
+func (m *simulatedMeter) GetRequest(att *dlms.AttributeDescriptor, data interface{}) error {
+	return m.GetRequestContext(context.Background(), att, data)
+}
+
+func (m *simulatedMeter) GetRequestContext(_ context.Context, att *dlms.AttributeDescriptor, data interface{}) error {
+	var value *axdr.DlmsData
+
+	switch att.AttributeID {
+	case cosem.ImageTransferAttributeBlockSize:
+		value = axdr.CreateAxdrDoubleLongUnsigned(m.blockSize)
+	case cosem.ImageTransferAttributeTransferredBlocksStatus:
+		var sb strings.Builder
+		for i := uint32(0); i < m.blockCount(); i++ {
+			if _, ok := m.blocks[i]; ok {
+				sb.WriteString("1")
+			} else {
+				sb.WriteString("0")
+			}
+		}
+		value = axdr.CreateAxdrBitString(sb.String())
+	case cosem.ImageTransferAttributeTransferEnabled:
+		value = axdr.CreateAxdrBoolean(m.enabled)
+	case cosem.ImageTransferAttributeTransferStatus:
+		if m.unreadable > 0 {
+			m.unreadable--
+			return dlms.NewError(dlms.ErrorCommunicationFailed, "meter restarting")
+		}
+
+		if m.pending > 0 {
+			m.pending--
+		} else {
+			m.finish()
+		}
+		value = axdr.CreateAxdrEnum(uint8(m.status))
+	default:
+		return fmt.Errorf("unexpected get %s", att.String())
+	}
+
+	return axdr.UnmarshalData(*value, data)
+}
+
+func (m *simulatedMeter) ActionRequestContext(_ context.Context, mth *dlms.MethodDescriptor, data interface{}) error {
+	dt := data.(*axdr.DlmsData)
+
+	switch mth.MethodID {
+	case cosem.ImageTransferMethodInitiate:
+		var params struct {
+			Identifier string
+			Size       uint32
+		}
+		if err := axdr.UnmarshalData(*dt, &params); err != nil {
+			return err
+		}
+
+		identifier, _ := hex.DecodeString(params.Identifier)
+		if !bytes.Equal(identifier, m.identifier) || params.Size != m.size {
+			m.blocks = make(map[uint32][]byte)
+		}
+		m.identifier = identifier
+		m.size = params.Size
+		m.status = cosem.ImageTransferStatusInitiated
+	case cosem.ImageTransferMethodBlockTransfer:
+		var params struct {
+			Number uint32
+			Value  string
+		}
+		if err := axdr.UnmarshalData(*dt, &params); err != nil {
+			return err
+		}
+
+		m.sent = append(m.sent, params.Number)
+		if m.lost[params.Number] {
+			delete(m.lost, params.Number)
+			return nil
+		}
+
+		m.blocks[params.Number], _ = hex.DecodeString(params.Value)
+	case cosem.ImageTransferMethodVerify:
+		if m.lateVerify {
+			return nil
+		}
+
+		m.status = cosem.ImageTransferStatusVerificationInitiated
+		// The verification takes long: temporary failure
+		return dlms.NewError(dlms.ErrorActionRejected, "action rejected: temporary failure")
+	case cosem.ImageTransferMethodActivate:
+		if m.status != cosem.ImageTransferStatusVerificationSuccessful {
+			return dlms.NewError(dlms.ErrorActionRejected, "action rejected: other reason")
+		}
+		m.status = cosem.ImageTransferStatusActivationInitiated
+	default:
+		return fmt.Errorf("unexpected action %s", mth.String())
+	}
+
+	return nil
+}
+
+func (m *simulatedMeter) blockCount() uint32 {
+	return (m.size + m.blockSize - 1) / m.blockSize
+}
+
+func (m *simulatedMeter) image() []byte {
+	var image []byte
+	for i := uint32(0); i < m.blockCount(); i++ {
+		image = append(image, m.blocks[i]...)
+	}
+
+	return image
+}
+
+func (m *simulatedMeter) finish() {
+	switch m.status {
+	case cosem.ImageTransferStatusInitiated, cosem.ImageTransferStatusVerificationInitiated:
+		if uint32(len(m.image())) == m.size {
+			m.status = cosem.ImageTransferStatusVerificationSuccessful
+		} else {
+			m.status = cosem.ImageTransferStatusVerificationFailed
+		}
+	case cosem.ImageTransferStatusActivationInitiated:
+		if m.failActivation {
+			m.status = cosem.ImageTransferStatusActivationFailed
+			return
+		}
+
+		m.activated = m.image()
+		m.status = cosem.ImageTransferStatusActivationSuccessful
+	}
+}
+
+func testImage(size int) []byte {
+	image := make([]byte, size)
+	for i := range image {
+		image[i] = byte(i)
+	}
+
+	return image
+}
+
+func TestImageTransfer_Upgrade(t *testing.T) {
+	m := newSimulatedMeter(64)
+	m.lost[2] = true
+	m.pending = 2
+
+	image := testImage(300)
+
+	var progress []cosem.ImageTransferProgress
+	opts := cosem.ImageTransferOptions{
+		Progress:     func(p cosem.ImageTransferProgress) { progress = append(progress, p) },
+		PollInterval: time.Millisecond,
+	}
+
+	err := cosem.NewImageTransfer("").Upgrade(context.Background(), m, []byte("FW-2.0"), image, opts)
+	require.NoError(t, err)
+	assert.Equal(t, image, m.activated)
+	assert.Equal(t, []uint32{0, 1, 2, 3, 4, 2}, m.sent)
+	assert.Equal(t, cosem.ImageTransferStatusActivationSuccessful, m.status)
+
+	assert.Equal(t, cosem.ImageTransferProgress{Status: cosem.ImageTransferStatusInitiated, BlocksTransferred: 0, BlocksTotal: 5}, progress[0])
+	assert.Equal(t, cosem.ImageTransferProgress{Status: cosem.ImageTransferStatusInitiated, BlocksTransferred: 5, BlocksTotal: 5}, progress[5])
+	// The lost block is sent again
+	assert.Equal(t, cosem.ImageTransferProgress{Status: cosem.ImageTransferStatusInitiated, BlocksTransferred: 4, BlocksTotal: 5}, progress[6])
+	assert.Equal(t, []cosem.ImageTransferProgress{
+		{Status: cosem.ImageTransferStatusVerificationInitiated},
+		{Status: cosem.ImageTransferStatusVerificationSuccessful},
+		{Status: cosem.ImageTransferStatusActivationSuccessful},
+	}, progress[8:])
+}
+
+func TestImageTransfer_UpgradeResumed(t *testing.T) {
+	m := newSimulatedMeter(100)
+	image := testImage(1000)
+
+	// A previous transfer of the same image was interrupted after 6 blocks
+	identifier := []byte("FW-2.0")
+	require.NoError(t, cosem.NewImageTransfer("").Initiate(m, identifier, uint32(len(image))))
+	for i := uint32(0); i < 6; i++ {
+		require.NoError(t, cosem.NewImageTransfer("").TransferBlock(m, i, image[i*100:(i+1)*100]))
+	}
+	m.sent = nil
+
+	blocks, err := cosem.NewImageTransfer("").ReadTransferredBlocksStatus(m)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, true, true, true, false, false, false, false}, blocks)
+
+	opts := cosem.ImageTransferOptions{PollInterval: time.Millisecond, SkipActivation: true}
+
+	err = cosem.NewImageTransfer("").Upgrade(context.Background(), m, identifier, image, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{6, 7, 8, 9}, m.sent)
+	assert.Equal(t, cosem.ImageTransferStatusVerificationSuccessful, m.status)
+	assert.Nil(t, m.activated)
+
+	// The meter restarts while the image is activated
+	m.unreadable = 2
+	err = cosem.NewImageTransfer("").Activate(context.Background(), m, opts)
+	require.NoError(t, err)
+	assert.Equal(t, image, m.activated)
+}
+
+func TestImageTransfer_UpgradeLateVerification(t *testing.T) {
+	m := newSimulatedMeter(64)
+	m.lateVerify = true
+	m.pending = 2
+
+	image := testImage(100)
+	opts := cosem.ImageTransferOptions{PollInterval: time.Millisecond}
+
+	// The status is still initiated until the meter verifies the image
+	err := cosem.NewImageTransfer("").Upgrade(context.Background(), m, []byte("FW-2.0"), image, opts)
+	require.NoError(t, err)
+	assert.Equal(t, image, m.activated)
+}
+
+func TestImageTransfer_UpgradeFail(t *testing.T) {
+	opts := cosem.ImageTransferOptions{PollInterval: time.Millisecond, MaxRetries: 1}
+
+	// Transfer not enabled
+	m := newSimulatedMeter(64)
+	m.enabled = false
+
+	err := cosem.NewImageTransfer("").Upgrade(context.Background(), m, []byte("FW"), testImage(100), opts)
+	assertInvalidState(t, err)
+	assert.Empty(t, m.sent)
+
+	// Block lost every time
+	m = newSimulatedMeter(64)
+	lost := &lostBlockMeter{simulatedMeter: m, block: 1}
+
+	err = cosem.NewImageTransfer("").Upgrade(context.Background(), lost, []byte("FW"), testImage(100), opts)
+	assert.ErrorContains(t, err, "1 blocks of the image not transferred")
+	assert.Equal(t, []uint32{0, 1, 1}, m.sent)
+
+	// Verification never ends
+	m = newSimulatedMeter(64)
+	m.pending = 1000
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err = cosem.NewImageTransfer("").Upgrade(ctx, m, []byte("FW"), testImage(100), opts)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, cosem.ImageTransferStatusVerificationInitiated, m.status)
+
+	// Activation never ends, without deadline in the context
+	m = newSimulatedMeter(64)
+	m.status = cosem.ImageTransferStatusVerificationSuccessful
+	m.pending = 1000
+
+	err = cosem.NewImageTransfer("").Activate(context.Background(), m, cosem.ImageTransferOptions{PollInterval: time.Millisecond, MaxWait: 20 * time.Millisecond})
+	assertActionRejected(t, err)
+	assert.ErrorContains(t, err, "still activation initiated")
+
+	// Activation failed
+	m = newSimulatedMeter(64)
+	m.failActivation = true
+
+	err = cosem.NewImageTransfer("").Upgrade(context.Background(), m, []byte("FW"), testImage(100), opts)
+	assertActionRejected(t, err)
+	assert.ErrorContains(t, err, "activation failed")
+	assert.Nil(t, m.activated)
+}
+
+// lostBlockMeter never stores a block
+type lostBlockMeter struct {
+	*simulatedMeter
+	block uint32
+}
+
+func (m *lostBlockMeter) ActionRequestContext(ctx context.Context, mth *dlms.MethodDescriptor, data interface{}) error {
+	m.lost[m.block] = true
+	return m.simulatedMeter.ActionRequestContext(ctx, mth, data)
+}
+
+func assertActionRejected(t *testing.T, err error) {
+	t.Helper()
+
+	var dlmsError *dlms.Error
+	require.True(t, errors.As(err, &dlmsError))
+	assert.Equal(t, dlms.ErrorActionRejected, dlmsError.Code())
+}
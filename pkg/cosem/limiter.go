@@ -0,0 +1,191 @@
+package cosem
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Limiter (class ID 71, version 0) monitors a value and runs scripts when it
+// crosses a threshold for long enough, e.g. to disconnect the supply.
+const (
+	LimiterAttributeMonitoredValue              int8 = 2
+	LimiterAttributeThresholdActive             int8 = 3
+	LimiterAttributeThresholdNormal             int8 = 4
+	LimiterAttributeThresholdEmergency          int8 = 5
+	LimiterAttributeMinOverThresholdDuration    int8 = 6
+	LimiterAttributeMinUnderThresholdDuration   int8 = 7
+	LimiterAttributeEmergencyProfile            int8 = 8
+	LimiterAttributeEmergencyProfileGroupIDList int8 = 9
+	LimiterAttributeEmergencyProfileActive      int8 = 10
+	LimiterAttributeActions                     int8 = 11
+)
+
+// ValueDefinition is the attribute monitored by a limiter
+type ValueDefinition struct {
+	ClassID        uint16
+	LogicalName    string
+	AttributeIndex int8
+}
+
+// EmergencyProfile is the threshold_emergency activation: it is active during
+// Duration from ActivationTime, if its ID is in the emergency profile group list.
+type EmergencyProfile struct {
+	ID             uint16
+	ActivationTime time.Time
+	Duration       uint32 // seconds
+}
+
+// LimiterAction is the script executed when the threshold is crossed
+type LimiterAction struct {
+	ScriptLogicalName string
+	ScriptSelector    uint16
+}
+
+type LimiterActions struct {
+	OverThreshold  LimiterAction
+	UnderThreshold LimiterAction
+}
+
+type Limiter struct {
+	Object
+}
+
+func NewLimiter(logicalName string) *Limiter {
+	return &Limiter{Object{ClassID: ClassIDLimiter, LogicalName: logicalName}}
+}
+
+func (l *Limiter) ReadMonitoredValue(c dlms.Client) (ValueDefinition, error) {
+	var value ValueDefinition
+	if err := l.Get(c, LimiterAttributeMonitoredValue, &value); err != nil {
+		return value, err
+	}
+
+	logicalName, err := LogicalNameFromHex(value.LogicalName)
+	if err != nil {
+		return value, dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("invalid monitored value: %v", err))
+	}
+	value.LogicalName = logicalName
+
+	return value, nil
+}
+
+func (l *Limiter) WriteMonitoredValue(c dlms.Client, value ValueDefinition) error {
+	return l.Set(c, LimiterAttributeMonitoredValue, value)
+}
+
+// ReadThresholdActive returns the threshold in use, of the type of the monitored value.
+func (l *Limiter) ReadThresholdActive(c dlms.Client) (threshold axdr.DlmsData, err error) {
+	err = l.Get(c, LimiterAttributeThresholdActive, &threshold)
+	return
+}
+
+func (l *Limiter) ReadThresholdNormal(c dlms.Client) (threshold axdr.DlmsData, err error) {
+	err = l.Get(c, LimiterAttributeThresholdNormal, &threshold)
+	return
+}
+
+// WriteThresholdNormal sets the threshold used when no emergency profile is
+// active. It must be of the type of the monitored value (e.g. uint32 for a
+// double-long-unsigned register).
+func (l *Limiter) WriteThresholdNormal(c dlms.Client, threshold interface{}) error {
+	return l.Set(c, LimiterAttributeThresholdNormal, threshold)
+}
+
+func (l *Limiter) ReadThresholdEmergency(c dlms.Client) (threshold axdr.DlmsData, err error) {
+	err = l.Get(c, LimiterAttributeThresholdEmergency, &threshold)
+	return
+}
+
+// WriteThresholdEmergency sets the threshold used while an emergency profile is
+// active. It must be of the type of the monitored value.
+func (l *Limiter) WriteThresholdEmergency(c dlms.Client, threshold interface{}) error {
+	return l.Set(c, LimiterAttributeThresholdEmergency, threshold)
+}
+
+// ReadMinOverThresholdDuration returns how long the value must be over the
+// threshold to run the over threshold action.
+func (l *Limiter) ReadMinOverThresholdDuration(c dlms.Client) (time.Duration, error) {
+	return l.readDuration(c, LimiterAttributeMinOverThresholdDuration)
+}
+
+func (l *Limiter) WriteMinOverThresholdDuration(c dlms.Client, d time.Duration) error {
+	return l.writeDuration(c, LimiterAttributeMinOverThresholdDuration, d)
+}
+
+// ReadMinUnderThresholdDuration returns how long the value must be under the
+// threshold to run the under threshold action.
+func (l *Limiter) ReadMinUnderThresholdDuration(c dlms.Client) (time.Duration, error) {
+	return l.readDuration(c, LimiterAttributeMinUnderThresholdDuration)
+}
+
+func (l *Limiter) WriteMinUnderThresholdDuration(c dlms.Client, d time.Duration) error {
+	return l.writeDuration(c, LimiterAttributeMinUnderThresholdDuration, d)
+}
+
+func (l *Limiter) readDuration(c dlms.Client, attribute int8) (time.Duration, error) {
+	var seconds uint32
+	if err := l.Get(c, attribute, &seconds); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func (l *Limiter) writeDuration(c dlms.Client, attribute int8, d time.Duration) error {
+	if d < 0 || d.Seconds() > float64(^uint32(0)) {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid duration %s", d))
+	}
+
+	return l.Set(c, attribute, uint32(d/time.Second))
+}
+
+func (l *Limiter) ReadEmergencyProfile(c dlms.Client) (profile EmergencyProfile, err error) {
+	err = l.Get(c, LimiterAttributeEmergencyProfile, &profile)
+	return
+}
+
+func (l *Limiter) WriteEmergencyProfile(c dlms.Client, profile EmergencyProfile) error {
+	return l.Set(c, LimiterAttributeEmergencyProfile, profile)
+}
+
+func (l *Limiter) ReadEmergencyProfileGroupIDList(c dlms.Client) (ids []uint16, err error) {
+	err = l.Get(c, LimiterAttributeEmergencyProfileGroupIDList, &ids)
+	return
+}
+
+func (l *Limiter) WriteEmergencyProfileGroupIDList(c dlms.Client, ids []uint16) error {
+	if ids == nil {
+		ids = []uint16{}
+	}
+
+	return l.Set(c, LimiterAttributeEmergencyProfileGroupIDList, ids)
+}
+
+func (l *Limiter) ReadEmergencyProfileActive(c dlms.Client) (active bool, err error) {
+	err = l.Get(c, LimiterAttributeEmergencyProfileActive, &active)
+	return
+}
+
+func (l *Limiter) ReadActions(c dlms.Client) (LimiterActions, error) {
+	var actions LimiterActions
+	if err := l.Get(c, LimiterAttributeActions, &actions); err != nil {
+		return actions, err
+	}
+
+	for _, action := range []*LimiterAction{&actions.OverThreshold, &actions.UnderThreshold} {
+		logicalName, err := LogicalNameFromHex(action.ScriptLogicalName)
+		if err != nil {
+			return actions, dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("invalid action: %v", err))
+		}
+		action.ScriptLogicalName = logicalName
+	}
+
+	return actions, nil
+}
+
+func (l *Limiter) WriteActions(c dlms.Client, actions LimiterActions) error {
+	return l.Set(c, LimiterAttributeActions, actions)
+}
@@ -0,0 +1,73 @@
+package cosem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+)
+
+func TestLimiter(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 71, 0.0.17.0.0.255, 2 }"] = "020312000309060100010700FF0F02"
+	c.attributes["{ 71, 0.0.17.0.0.255, 3 }"] = "0600001770"
+	c.attributes["{ 71, 0.0.17.0.0.255, 6 }"] = "060000003C"
+	c.attributes["{ 71, 0.0.17.0.0.255, 8 }"] = "0203120001090C07E7010F07000000000000000600000E10"
+	c.attributes["{ 71, 0.0.17.0.0.255, 9 }"] = "0102120001120002"
+	c.attributes["{ 71, 0.0.17.0.0.255, 11 }"] = "02020202090600000A0064FF1200010202090600000A0064FF120002"
+
+	l := cosem.NewLimiter("0-0:17.0.0.255")
+
+	value, err := l.ReadMonitoredValue(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.ValueDefinition{ClassID: 3, LogicalName: "1.0.1.7.0.255", AttributeIndex: 2}, value)
+
+	threshold, err := l.ReadThresholdActive(c)
+	assert.NoError(t, err)
+	assert.Equal(t, *axdr.CreateAxdrDoubleLongUnsigned(6000), threshold)
+
+	duration, err := l.ReadMinOverThresholdDuration(c)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, duration)
+
+	profile, err := l.ReadEmergencyProfile(c)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(1), profile.ID)
+	assert.Equal(t, time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC).Unix(), profile.ActivationTime.Unix())
+	assert.Equal(t, uint32(3600), profile.Duration)
+
+	ids, err := l.ReadEmergencyProfileGroupIDList(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{1, 2}, ids)
+
+	actions, err := l.ReadActions(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.LimiterActions{
+		OverThreshold:  cosem.LimiterAction{ScriptLogicalName: "0.0.10.0.100.255", ScriptSelector: 1},
+		UnderThreshold: cosem.LimiterAction{ScriptLogicalName: "0.0.10.0.100.255", ScriptSelector: 2},
+	}, actions)
+
+	err = l.WriteThresholdNormal(c, uint32(5000))
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrDoubleLongUnsigned(5000), c.written["{ 71, 0.0.17.0.0.255, 4 }"])
+
+	err = l.WriteMinUnderThresholdDuration(c, 90*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrDoubleLongUnsigned(90), c.written["{ 71, 0.0.17.0.0.255, 7 }"])
+
+	err = l.WriteMinUnderThresholdDuration(c, -time.Second)
+	assert.Error(t, err)
+
+	err = l.WriteEmergencyProfileGroupIDList(c, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrArray([]*axdr.DlmsData{}), c.written["{ 71, 0.0.17.0.0.255, 9 }"])
+
+	err = l.WriteActions(c, actions)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrStructure([]*axdr.DlmsData{
+		axdr.CreateAxdrStructure([]*axdr.DlmsData{axdr.CreateAxdrOctetString("0.0.10.0.100.255"), axdr.CreateAxdrLongUnsigned(1)}),
+		axdr.CreateAxdrStructure([]*axdr.DlmsData{axdr.CreateAxdrOctetString("0.0.10.0.100.255"), axdr.CreateAxdrLongUnsigned(2)}),
+	}), c.written["{ 71, 0.0.17.0.0.255, 11 }"])
+}
@@ -0,0 +1,387 @@
+package cosem
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// ProfileColumn is a capture object of a profile together with its scaler_unit,
+// nil when the captured attribute is not scaled.
+type ProfileColumn struct {
+	CaptureObject
+	ScalerUnit *dlms.ScalerUnit
+}
+
+// Key identifies the column in a ProfileRow: the logical name and the attribute
+// index (e.g. "1.0.1.8.0.255:2"), followed by the data index when it is not 0.
+func (c ProfileColumn) Key() string {
+	key := c.LogicalName + ":" + strconv.Itoa(int(c.AttributeIndex))
+	if c.DataIndex != 0 {
+		key += ":" + strconv.Itoa(int(c.DataIndex))
+	}
+
+	return key
+}
+
+func (c ProfileColumn) isClock() bool {
+	return c.ClassID == ClassIDClock && c.AttributeIndex == ClockAttributeTime
+}
+
+// Profile is the layout of the buffer of a Profile Generic object, needed to
+// decode its entries.
+type Profile struct {
+	Columns       []ProfileColumn
+	CapturePeriod time.Duration
+	// TimeZone is the convention of the meter for the deviation of date-times, as
+	// in dlms.Settings. If nil, axdr.TimeZoneDeviation is used.
+	TimeZone *axdr.TimeZone
+}
+
+// timeZone returns the convention used to decode the date-times of the buffer.
+func (pr *Profile) timeZone() axdr.TimeZone {
+	if pr.TimeZone != nil {
+		return *pr.TimeZone
+	}
+
+	return axdr.TimeZoneDeviation //nolint:staticcheck // default when the profile has no time zone
+}
+
+// ProfileRow is an entry of the buffer keyed by ProfileColumn.Key. Scaled columns
+// hold a dlms.PhysicalValue, the clock a time.Time and the rest the value as
+// decoded by the axdr package (nil for null values).
+type ProfileRow map[string]interface{}
+
+// scalerUnitIndex returns the index of the scaler_unit attribute that applies to
+// a captured attribute, or 0 if the attribute is not scaled.
+func scalerUnitIndex(o CaptureObject) int8 {
+	switch {
+	case (o.ClassID == ClassIDRegister || o.ClassID == ClassIDExtendedRegister) && o.AttributeIndex == RegisterAttributeValue:
+		return RegisterAttributeScalerUnit
+	case o.ClassID == ClassIDDemandRegister && (o.AttributeIndex == DemandRegisterAttributeCurrentAverageValue || o.AttributeIndex == DemandRegisterAttributeLastAverageValue):
+		return DemandRegisterAttributeScalerUnit
+	default:
+		return 0
+	}
+}
+
+// ReadProfile reads the capture objects, the capture period and the scaler_unit
+// of the scaled columns. Columns whose scaler_unit cannot be read are left unscaled.
+// The time zone is taken from the settings of the client.
+func (p *ProfileGeneric) ReadProfile(c dlms.Client) (*Profile, error) {
+	objects, err := p.ReadCaptureObjects(c)
+	if err != nil {
+		return nil, err
+	}
+
+	period, err := p.ReadCapturePeriod(c)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &Profile{Columns: make([]ProfileColumn, len(objects)), CapturePeriod: period, TimeZone: c.GetSettings().TimeZone}
+
+	var atts []*dlms.AttributeDescriptor
+	var scaled []int
+	for i, o := range objects {
+		profile.Columns[i].CaptureObject = o
+
+		if index := scalerUnitIndex(o); index != 0 {
+			atts = append(atts, dlms.CreateAttributeDescriptor(o.ClassID, o.LogicalName, index))
+			scaled = append(scaled, i)
+		}
+	}
+
+	if len(atts) == 0 {
+		return profile, nil
+	}
+
+	scalers := make([]dlms.ScalerUnit, len(atts))
+	data := make([]interface{}, len(atts))
+	for i := range scalers {
+		data[i] = &scalers[i]
+	}
+
+	err = c.GetRequestWithList(atts, data)
+	if err == nil {
+		for i, column := range scaled {
+			profile.Columns[column].ScalerUnit = &scalers[i]
+		}
+
+		return profile, nil
+	}
+
+	var dlmsError *dlms.Error
+	if !errors.As(err, &dlmsError) || dlmsError.Code() != dlms.ErrorGetRejected {
+		return nil, err
+	}
+
+	// Some of them were rejected, so read them one by one to know which
+	for i, column := range scaled {
+		var su dlms.ScalerUnit
+
+		err = c.GetRequest(atts[i], &su)
+		if err == nil {
+			profile.Columns[column].ScalerUnit = &su
+		} else if !errors.As(err, &dlmsError) || dlmsError.Code() != dlms.ErrorGetRejected {
+			return nil, err
+		}
+	}
+
+	return profile, nil
+}
+
+// ReadRows reads the whole buffer and decodes its entries.
+func (p *ProfileGeneric) ReadRows(c dlms.Client, profile *Profile) ([]ProfileRow, error) {
+	var buffer []axdr.DlmsData
+	if err := p.ReadBuffer(c, &buffer); err != nil {
+		return nil, err
+	}
+
+	return profile.Rows(buffer)
+}
+
+// ReadRowsByDate reads the entries captured between start and end and decodes them.
+func (p *ProfileGeneric) ReadRowsByDate(c dlms.Client, profile *Profile, start time.Time, end time.Time) ([]ProfileRow, error) {
+	var buffer []axdr.DlmsData
+	if err := p.ReadBufferByDate(c, start, end, &buffer); err != nil {
+		return nil, err
+	}
+
+	return profile.Rows(buffer)
+}
+
+// ReadRowsByRange reads the entries selected by a range descriptor and decodes them,
+// with only the columns selected in it if any.
+func (p *ProfileGeneric) ReadRowsByRange(c dlms.Client, profile *Profile, rd dlms.RangeDescriptor) ([]ProfileRow, error) {
+	if len(rd.Columns) != 0 {
+		var err error
+		if profile, err = profile.Select(rd.Columns); err != nil {
+			return nil, dlms.NewError(dlms.ErrorInvalidParameter, err.Error())
+		}
+	}
+
+	var buffer []axdr.DlmsData
+	if err := p.ReadBufferByRange(c, rd, &buffer); err != nil {
+		return nil, err
+	}
+
+	return profile.Rows(buffer)
+}
+
+// Select returns the layout of the buffer when only some columns are selected.
+func (pr *Profile) Select(values []dlms.SelectedValue) (*Profile, error) {
+	selected := &Profile{Columns: make([]ProfileColumn, 0, len(values)), CapturePeriod: pr.CapturePeriod, TimeZone: pr.TimeZone}
+
+	for _, v := range values {
+		found := false
+
+		for _, column := range pr.Columns {
+			if column.ClassID == v.Attribute.ClassID && column.LogicalName == v.Attribute.InstanceID.String() &&
+				column.AttributeIndex == v.Attribute.AttributeID && column.DataIndex == v.DataIndex {
+				selected.Columns = append(selected.Columns, column)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("column %s is not captured in the profile", v.Attribute.String())
+		}
+	}
+
+	return selected, nil
+}
+
+// Expand returns the entries of the buffer with the null-compressed values filled:
+// a null clock is the clock of the previous entry plus the capture period and any
+// other null value is the same as in the previous entry. Clock values are decoded
+// into time.Time with the time zone of the profile.
+func (pr *Profile) Expand(buffer []axdr.DlmsData) ([][]axdr.DlmsData, error) {
+	entries := make([][]axdr.DlmsData, len(buffer))
+
+	var previous []axdr.DlmsData
+	var previousTime time.Time
+
+	for i, row := range buffer {
+		values, ok := row.Value.([]*axdr.DlmsData)
+		if !ok {
+			return nil, fmt.Errorf("entry %d is not a structure", i)
+		}
+
+		if len(values) != len(pr.Columns) {
+			return nil, fmt.Errorf("entry %d has %d values, expected %d", i, len(values), len(pr.Columns))
+		}
+
+		entry := make([]axdr.DlmsData, len(values))
+		for j, value := range values {
+			entry[j] = *value
+
+			if value.Tag == axdr.TagNull && previous != nil {
+				if pr.Columns[j].isClock() {
+					if pr.CapturePeriod == 0 {
+						return nil, fmt.Errorf("entry %d has no clock and the profile is not periodic", i)
+					}
+
+					entry[j] = *axdr.CreateAxdrOctetString(previousTime.Add(pr.CapturePeriod))
+				} else {
+					entry[j] = previous[j]
+				}
+			}
+
+			if pr.Columns[j].isClock() && entry[j].Tag != axdr.TagNull {
+				if err := axdr.UnmarshalDataWithTimeZone(entry[j], &previousTime, pr.timeZone()); err != nil {
+					return nil, fmt.Errorf("invalid clock in entry %d: %w", i, err)
+				}
+				entry[j] = *axdr.CreateAxdrOctetString(previousTime)
+			}
+		}
+
+		entries[i] = entry
+		previous = entry
+	}
+
+	return entries, nil
+}
+
+// Rows expands the buffer and decodes its entries into rows keyed by column.
+func (pr *Profile) Rows(buffer []axdr.DlmsData) ([]ProfileRow, error) {
+	entries, err := pr.Expand(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]ProfileRow, len(entries))
+	for i, entry := range entries {
+		row := make(ProfileRow, len(entry))
+
+		for j, value := range entry {
+			column := pr.Columns[j]
+
+			switch {
+			case value.Tag == axdr.TagNull:
+				row[column.Key()] = nil
+			case column.isClock():
+				t, err := value.AsTime()
+				if err != nil {
+					return nil, fmt.Errorf("invalid clock in entry %d: %w", i, err)
+				}
+				row[column.Key()] = t
+			case column.ScalerUnit != nil:
+				pv, err := column.ScalerUnit.Apply(value.Value)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s in entry %d: %w", column.Key(), i, err)
+				}
+				row[column.Key()] = pv
+			default:
+				row[column.Key()] = value.Value
+			}
+		}
+
+		rows[i] = row
+	}
+
+	return rows, nil
+}
+
+// Unmarshal expands the buffer and decodes its entries into v, a pointer to a
+// slice of structs. The fields are matched to the columns with the same tag used
+// by dlms.Client GetRequestWithStructOfElements: `obis:"class,obis,attribute"`.
+// Scaled columns can be stored in floats or dlms.PhysicalValue, and the fields
+// of the columns not in the profile are left untouched.
+func (pr *Profile) Unmarshal(buffer []axdr.DlmsData, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice || rv.Elem().Type().Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("v must be a pointer to a slice of structs")
+	}
+
+	rt := rv.Elem().Type().Elem()
+
+	fields := make([]int, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		column, err := pr.columnOfField(rt.Field(i))
+		if err != nil {
+			return err
+		}
+		fields[i] = column
+	}
+
+	entries, err := pr.Expand(buffer)
+	if err != nil {
+		return err
+	}
+
+	slice := reflect.MakeSlice(rv.Elem().Type(), len(entries), len(entries))
+	for i, entry := range entries {
+		for j, column := range fields {
+			if column < 0 || entry[column].Tag == axdr.TagNull {
+				continue
+			}
+
+			field := slice.Index(i).Field(j)
+			if err := pr.setField(field, pr.Columns[column], entry[column]); err != nil {
+				return fmt.Errorf("invalid %s in entry %d: %w", pr.Columns[column].Key(), i, err)
+			}
+		}
+	}
+
+	rv.Elem().Set(slice)
+
+	return nil
+}
+
+// columnOfField returns the column of the profile tagged in a field, or -1. The
+// scaled option is accepted, as float and dlms.PhysicalValue fields are always
+// scaled when the scaler_unit of the column is known.
+func (pr *Profile) columnOfField(field reflect.StructField) (int, error) {
+	att, _, err := dlms.ParseObisTag(field.Tag.Get("obis"))
+	if err != nil || att == nil {
+		return -1, err
+	}
+
+	logicalName := att.InstanceID.String()
+
+	for i, column := range pr.Columns {
+		if column.ClassID == att.ClassID && column.LogicalName == logicalName && column.AttributeIndex == att.AttributeID {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+func (pr *Profile) setField(field reflect.Value, column ProfileColumn, value axdr.DlmsData) error {
+	if field.Kind() == reflect.Ptr {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+
+	if column.ScalerUnit != nil {
+		switch field.Interface().(type) {
+		case dlms.PhysicalValue:
+			pv, err := column.ScalerUnit.Apply(value.Value)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(pv))
+
+			return nil
+		case float32, float64:
+			pv, err := column.ScalerUnit.Apply(value.Value)
+			if err != nil {
+				return err
+			}
+			field.SetFloat(pv.Value)
+
+			return nil
+		}
+	}
+
+	return axdr.UnmarshalDataWithTimeZone(value, field.Addr().Interface(), pr.timeZone())
+}
@@ -0,0 +1,178 @@
+package cosem
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Profile Generic (class ID 7, version 1) captures the values of other objects
+// into a buffer, usually periodically (load profiles, event logs...).
+const (
+	ProfileGenericAttributeBuffer         int8 = 2
+	ProfileGenericAttributeCaptureObjects int8 = 3
+	ProfileGenericAttributeCapturePeriod  int8 = 4
+	ProfileGenericAttributeSortMethod     int8 = 5
+	ProfileGenericAttributeSortObject     int8 = 6
+	ProfileGenericAttributeEntriesInUse   int8 = 7
+	ProfileGenericAttributeProfileEntries int8 = 8
+
+	ProfileGenericMethodReset   int8 = 1
+	ProfileGenericMethodCapture int8 = 2
+)
+
+// SortMethod defines how the entries of the buffer are sorted
+type SortMethod uint8
+
+const (
+	SortMethodFIFO SortMethod = iota + 1
+	SortMethodLIFO
+	SortMethodLargest
+	SortMethodSmallest
+	SortMethodNearestToZero
+	SortMethodFarthestFromZero
+)
+
+// CaptureObject is a column of the buffer: an attribute of another object and,
+// for complex attributes, the index of the element captured (0 for all of it).
+type CaptureObject struct {
+	ClassID        uint16
+	LogicalName    string
+	AttributeIndex int8
+	DataIndex      uint16
+}
+
+// MarshalAXDR encodes the capture object as the structure of its four elements
+func (o CaptureObject) MarshalAXDR() (*axdr.DlmsData, error) {
+	return axdr.CreateAxdrStructure([]*axdr.DlmsData{
+		axdr.CreateAxdrLongUnsigned(o.ClassID),
+		axdr.CreateAxdrOctetString(o.LogicalName),
+		axdr.CreateAxdrInteger(o.AttributeIndex),
+		axdr.CreateAxdrLongUnsigned(o.DataIndex),
+	}), nil
+}
+
+// UnmarshalAXDR decodes the capture object, with the logical name in dotted notation
+func (o *CaptureObject) UnmarshalAXDR(data axdr.DlmsData) error {
+	// raw has the same fields but no methods, so it is unmarshaled as a plain struct
+	type raw CaptureObject
+
+	var r raw
+	if err := axdr.UnmarshalData(data, &r); err != nil {
+		return err
+	}
+
+	logicalName, err := LogicalNameFromHex(r.LogicalName)
+	if err != nil {
+		return err
+	}
+	r.LogicalName = logicalName
+	*o = CaptureObject(r)
+
+	return nil
+}
+
+func (o CaptureObject) AttributeDescriptor() *dlms.AttributeDescriptor {
+	return dlms.CreateAttributeDescriptor(o.ClassID, o.LogicalName, o.AttributeIndex)
+}
+
+type ProfileGeneric struct {
+	Object
+}
+
+func NewProfileGeneric(logicalName string) *ProfileGeneric {
+	return &ProfileGeneric{Object{ClassID: ClassIDProfileGeneric, LogicalName: logicalName}}
+}
+
+// ReadBuffer reads the whole buffer into v, usually a slice of structs with one
+// field per capture object.
+func (p *ProfileGeneric) ReadBuffer(c dlms.Client, v interface{}) error {
+	return p.Get(c, ProfileGenericAttributeBuffer, v)
+}
+
+// ReadBufferByDate reads the entries of the buffer captured between start and end.
+func (p *ProfileGeneric) ReadBufferByDate(c dlms.Client, start time.Time, end time.Time, v interface{}) error {
+	return c.GetRequestWithSelectiveAccessByDate(p.AttributeDescriptor(ProfileGenericAttributeBuffer), start, end, v)
+}
+
+// ReadBufferByRange reads the entries of the buffer selected by a range descriptor,
+// which may be restricted by any capture object (e.g. a sequence number).
+func (p *ProfileGeneric) ReadBufferByRange(c dlms.Client, rd dlms.RangeDescriptor, v interface{}) error {
+	return c.GetRequestWithRange(p.AttributeDescriptor(ProfileGenericAttributeBuffer), rd, v)
+}
+
+// ReadBufferRows yields the entries of the buffer selected by acc (all of them if it
+// is nil) while they are still being received, to be unmarshaled one by one.
+func (p *ProfileGeneric) ReadBufferRows(ctx context.Context, c dlms.Client, acc *dlms.SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error] {
+	return dlms.AsContextClient(c).GetRequestRowsContext(ctx, p.AttributeDescriptor(ProfileGenericAttributeBuffer), acc)
+}
+
+// ReadCaptureObjects reads the definition of the columns of the buffer.
+func (p *ProfileGeneric) ReadCaptureObjects(c dlms.Client) ([]CaptureObject, error) {
+	var data axdr.DlmsData
+	if err := p.Get(c, ProfileGenericAttributeCaptureObjects, &data); err != nil {
+		return nil, err
+	}
+
+	return decodeCaptureObjects(data)
+}
+
+func decodeCaptureObjects(data axdr.DlmsData) ([]CaptureObject, error) {
+	var objects []CaptureObject
+	if err := axdr.UnmarshalData(data, &objects); err != nil {
+		return nil, fmt.Errorf("invalid capture objects: %w", err)
+	}
+
+	return objects, nil
+}
+
+// ReadCapturePeriod returns the capture period, 0 when capturing is not periodic.
+func (p *ProfileGeneric) ReadCapturePeriod(c dlms.Client) (time.Duration, error) {
+	var seconds uint32
+	err := p.Get(c, ProfileGenericAttributeCapturePeriod, &seconds)
+
+	return time.Duration(seconds) * time.Second, err
+}
+
+func (p *ProfileGeneric) ReadSortMethod(c dlms.Client) (m SortMethod, err error) {
+	err = p.Get(c, ProfileGenericAttributeSortMethod, &m)
+	return
+}
+
+func (p *ProfileGeneric) ReadSortObject(c dlms.Client) (CaptureObject, error) {
+	var data axdr.DlmsData
+	if err := p.Get(c, ProfileGenericAttributeSortObject, &data); err != nil {
+		return CaptureObject{}, err
+	}
+
+	objects, err := decodeCaptureObjects(*axdr.CreateAxdrArray([]*axdr.DlmsData{&data}))
+	if err != nil {
+		return CaptureObject{}, err
+	}
+
+	return objects[0], nil
+}
+
+func (p *ProfileGeneric) ReadEntriesInUse(c dlms.Client) (n uint32, err error) {
+	err = p.Get(c, ProfileGenericAttributeEntriesInUse, &n)
+	return
+}
+
+func (p *ProfileGeneric) ReadProfileEntries(c dlms.Client) (n uint32, err error) {
+	err = p.Get(c, ProfileGenericAttributeProfileEntries, &n)
+	return
+}
+
+// Reset clears the buffer.
+func (p *ProfileGeneric) Reset(c dlms.Client) error {
+	return p.Invoke(c, ProfileGenericMethodReset, integerZero())
+}
+
+// Capture adds a new entry to the buffer with the current values of the capture objects.
+func (p *ProfileGeneric) Capture(c dlms.Client) error {
+	return p.Invoke(c, ProfileGenericMethodCapture, integerZero())
+}
@@ -0,0 +1,66 @@
+package cosem_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+)
+
+func TestProfileGeneric(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 7, 1.0.99.1.0.255, 3 }"] = "0102" +
+		"020412000809060000010000FF0F02120000" +
+		"020412000309060100010800FF0F02120000"
+	c.attributes["{ 7, 1.0.99.1.0.255, 4 }"] = "0600000384"
+	c.attributes["{ 7, 1.0.99.1.0.255, 5 }"] = "1601"
+
+	p := cosem.NewProfileGeneric("1-0:99.1.0.255")
+
+	objects, err := p.ReadCaptureObjects(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []cosem.CaptureObject{
+		{ClassID: 8, LogicalName: "0.0.1.0.0.255", AttributeIndex: 2, DataIndex: 0},
+		{ClassID: 3, LogicalName: "1.0.1.8.0.255", AttributeIndex: 2, DataIndex: 0},
+	}, objects)
+	assert.Equal(t, "{ 3, 1.0.1.8.0.255, 2 }", objects[1].AttributeDescriptor().String())
+
+	period, err := p.ReadCapturePeriod(c)
+	assert.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, period)
+
+	method, err := p.ReadSortMethod(c)
+	assert.NoError(t, err)
+	assert.Equal(t, cosem.SortMethodFIFO, method)
+}
+
+func TestProfileGeneric_ReadBufferRows(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 7, 1.0.99.1.0.255, 2 }"] = "0103" +
+		"0202090C07E8030A0700000000FFFFFF0600000001" +
+		"0202090C07E8030A07000F0000FFFFFF0600000002" +
+		"0202090C07E8030A07001E0000FFFFFF0600000003"
+
+	type row struct {
+		Time  axdr.CosemDateTime
+		Value uint32
+	}
+
+	var values []uint32
+	for data, err := range cosem.NewProfileGeneric("1-0:99.1.0.255").ReadBufferRows(context.Background(), c, nil) {
+		require.NoError(t, err)
+
+		var r row
+		require.NoError(t, axdr.UnmarshalData(data, &r))
+		values = append(values, r.Value)
+
+		if len(values) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []uint32{1, 2}, values)
+}
@@ -0,0 +1,200 @@
+package cosem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func newLoadProfileClient() *fakeClient {
+	c := newFakeClient()
+	c.attributes["{ 7, 1.0.99.1.0.255, 3 }"] = "0103" +
+		"020412000809060000010000FF0F02120000" +
+		"020412000109060000600A01FF0F02120000" +
+		"020412000309060100010800FF0F02120000"
+	c.attributes["{ 7, 1.0.99.1.0.255, 4 }"] = "0600000384"
+	c.attributes["{ 3, 1.0.1.8.0.255, 3 }"] = "02020FFE161E"
+
+	// The second entry has no clock nor status, and the third no energy
+	c.attributes["{ 7, 1.0.99.1.0.255, 2 }"] = "0103" +
+		"0203" + "090C07E40101030C000000000000" + "1100" + "0600003039" +
+		"0203" + "00" + "00" + "0600003040" +
+		"0203" + "00" + "1108" + "00"
+
+	return c
+}
+
+func TestProfileGeneric_ReadProfile(t *testing.T) {
+	c := newLoadProfileClient()
+
+	profile, err := cosem.NewProfileGeneric("1-0:99.1.0.255").ReadProfile(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, 15*time.Minute, profile.CapturePeriod)
+	require.Len(t, profile.Columns, 3)
+	assert.Nil(t, profile.Columns[0].ScalerUnit)
+	assert.Nil(t, profile.Columns[1].ScalerUnit)
+	assert.Equal(t, &dlms.ScalerUnit{Scaler: -2, Unit: dlms.UnitWattHour}, profile.Columns[2].ScalerUnit)
+	assert.Equal(t, "1.0.1.8.0.255:2", profile.Columns[2].Key())
+
+	// A scaler_unit rejected leaves the column unscaled
+	delete(c.attributes, "{ 3, 1.0.1.8.0.255, 3 }")
+
+	profile, err = cosem.NewProfileGeneric("1-0:99.1.0.255").ReadProfile(c)
+	require.NoError(t, err)
+	assert.Nil(t, profile.Columns[2].ScalerUnit)
+}
+
+func TestProfileGeneric_ReadRows(t *testing.T) {
+	c := newLoadProfileClient()
+
+	p := cosem.NewProfileGeneric("1-0:99.1.0.255")
+
+	profile, err := p.ReadProfile(c)
+	require.NoError(t, err)
+
+	rows, err := p.ReadRows(c, profile)
+	require.NoError(t, err)
+
+	start := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, []cosem.ProfileRow{
+		{
+			"0.0.1.0.0.255:2":   start,
+			"0.0.96.10.1.255:2": uint8(0),
+			"1.0.1.8.0.255:2":   dlms.PhysicalValue{Value: 123.45, Unit: dlms.UnitWattHour},
+		},
+		{
+			"0.0.1.0.0.255:2":   start.Add(15 * time.Minute),
+			"0.0.96.10.1.255:2": uint8(0),
+			"1.0.1.8.0.255:2":   dlms.PhysicalValue{Value: 123.52, Unit: dlms.UnitWattHour},
+		},
+		{
+			"0.0.1.0.0.255:2":   start.Add(30 * time.Minute),
+			"0.0.96.10.1.255:2": uint8(8),
+			"1.0.1.8.0.255:2":   dlms.PhysicalValue{Value: 123.52, Unit: dlms.UnitWattHour},
+		},
+	}, rows)
+}
+
+func TestProfile_Unmarshal(t *testing.T) {
+	c := newLoadProfileClient()
+
+	p := cosem.NewProfileGeneric("1-0:99.1.0.255")
+
+	profile, err := p.ReadProfile(c)
+	require.NoError(t, err)
+
+	var buffer []axdr.DlmsData
+	err = p.ReadBuffer(c, &buffer)
+	require.NoError(t, err)
+
+	var entries []struct {
+		Time     time.Time `obis:"8,0-0:1.0.0.255,2"`
+		Status   uint8     `obis:"1,0-0:96.10.1.255,2"`
+		Energy   float64   `obis:"3,1-0:1.8.0.255,2"`
+		Power    *float64  `obis:"3,1-0:1.7.0.255,2"`
+		Comments string
+	}
+
+	err = profile.Unmarshal(buffer, &entries)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	start := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, start.Add(30*time.Minute), entries[2].Time)
+	assert.Equal(t, uint8(8), entries[2].Status)
+	assert.Equal(t, 123.52, entries[2].Energy)
+	assert.Nil(t, entries[2].Power)
+
+	// The tags of the requests with struct of elements are accepted too
+	var scaled []struct {
+		Energy dlms.PhysicalValue `obis:"3,1-0:1.8.0.255,2,scaled"`
+	}
+
+	err = profile.Unmarshal(buffer, &scaled)
+	require.NoError(t, err)
+	require.Len(t, scaled, 3)
+	assert.Equal(t, dlms.PhysicalValue{Value: 123.52, Unit: dlms.UnitWattHour}, scaled[2].Energy)
+
+	err = profile.Unmarshal(buffer, entries)
+	assert.Error(t, err)
+}
+
+func TestProfile_RowsWithTimeZone(t *testing.T) {
+	c := newLoadProfileClient()
+	c.attributes["{ 7, 1.0.99.1.0.255, 2 }"] = "0102" +
+		"0203" + "090C07E40101030C0000FF003C00" + "1100" + "0600003039" +
+		"0203" + "00" + "00" + "0600003040"
+
+	tz := axdr.TimeZoneReversed
+	c.settings.TimeZone = &tz
+
+	p := cosem.NewProfileGeneric("1-0:99.1.0.255")
+
+	profile, err := p.ReadProfile(c)
+	require.NoError(t, err)
+
+	rows, err := p.ReadRows(c, profile)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	// The deviation is local time minus UTC, so 12:00 is 11:00 UTC
+	start := time.Date(2020, time.January, 1, 11, 0, 0, 0, time.UTC)
+	assert.True(t, start.Equal(rows[0]["0.0.1.0.0.255:2"].(time.Time)))
+	assert.True(t, start.Add(15*time.Minute).Equal(rows[1]["0.0.1.0.0.255:2"].(time.Time)))
+}
+
+func TestProfile_ExpandWithoutCapturePeriod(t *testing.T) {
+	profile := cosem.Profile{
+		Columns: []cosem.ProfileColumn{
+			{CaptureObject: cosem.CaptureObject{ClassID: 8, LogicalName: "0.0.1.0.0.255", AttributeIndex: 2}},
+		},
+	}
+
+	buffer := []axdr.DlmsData{
+		*axdr.CreateAxdrStructure([]*axdr.DlmsData{axdr.CreateAxdrOctetString("07E40101030C000000000000")}),
+		*axdr.CreateAxdrStructure([]*axdr.DlmsData{axdr.CreateAxdrNull()}),
+	}
+
+	_, err := profile.Expand(buffer)
+	assert.Error(t, err)
+}
+
+func TestProfileGeneric_ReadRowsByRange(t *testing.T) {
+	c := newLoadProfileClient()
+
+	p := cosem.NewProfileGeneric("1-0:99.1.0.255")
+
+	profile, err := p.ReadProfile(c)
+	require.NoError(t, err)
+
+	c.ranges = append(c.ranges, "0101"+"0202"+"090C07E40101030C000000000000"+"0600003039")
+
+	rd := dlms.RangeDescriptor{
+		RestrictingObject: dlms.ClockRestrictingObject(),
+		From:              time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC),
+		To:                time.Date(2020, time.January, 1, 12, 5, 0, 0, time.UTC),
+		Columns: []dlms.SelectedValue{
+			{Attribute: *dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2)},
+			{Attribute: *dlms.CreateAttributeDescriptor(3, "1-0:1.8.0.255", 2)},
+		},
+	}
+
+	rows, err := p.ReadRowsByRange(c, profile, rd)
+	require.NoError(t, err)
+	assert.Equal(t, []cosem.ProfileRow{
+		{
+			"0.0.1.0.0.255:2": time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC),
+			"1.0.1.8.0.255:2": dlms.PhysicalValue{Value: 123.45, Unit: dlms.UnitWattHour},
+		},
+	}, rows)
+
+	rd.Columns = append(rd.Columns, dlms.SelectedValue{Attribute: *dlms.CreateAttributeDescriptor(3, "1-0:2.8.0.255", 2)})
+	_, err = p.ReadRowsByRange(c, profile, rd)
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package cosem
+
+import (
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Register (class ID 3, version 0) holds a process or status value with its scaler and unit.
+const (
+	RegisterAttributeValue      int8 = 2
+	RegisterAttributeScalerUnit int8 = 3
+
+	RegisterMethodReset int8 = 1
+)
+
+type Register struct {
+	Object
+}
+
+func NewRegister(logicalName string) *Register {
+	return &Register{Object{ClassID: ClassIDRegister, LogicalName: logicalName}}
+}
+
+// Read reads the value attribute into v. The value is not scaled.
+func (r *Register) Read(c dlms.Client, v interface{}) error {
+	return r.Get(c, RegisterAttributeValue, v)
+}
+
+func (r *Register) ReadScalerUnit(c dlms.Client) (su dlms.ScalerUnit, err error) {
+	err = r.Get(c, RegisterAttributeScalerUnit, &su)
+	return
+}
+
+// ReadPhysicalValue reads the value and the scaler_unit together and returns the
+// scaled value with its unit.
+func (r *Register) ReadPhysicalValue(c dlms.Client) (dlms.PhysicalValue, error) {
+	var value axdr.DlmsData
+	var su dlms.ScalerUnit
+
+	atts := []*dlms.AttributeDescriptor{
+		r.AttributeDescriptor(RegisterAttributeValue),
+		r.AttributeDescriptor(RegisterAttributeScalerUnit),
+	}
+
+	if err := c.GetRequestWithList(atts, []interface{}{&value, &su}); err != nil {
+		return dlms.PhysicalValue{}, err
+	}
+
+	pv, err := su.Apply(value.Value)
+	if err != nil {
+		return dlms.PhysicalValue{}, dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error scaling %s: %v", r.LogicalName, err))
+	}
+
+	return pv, nil
+}
+
+// Reset sets the value to its default.
+func (r *Register) Reset(c dlms.Client) error {
+	return r.Invoke(c, RegisterMethodReset, integerZero())
+}
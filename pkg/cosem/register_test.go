@@ -0,0 +1,56 @@
+package cosem_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func TestRegister(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 3, 1.0.1.8.0.255, 2 }"] = "0600003039"
+	c.attributes["{ 3, 1.0.1.8.0.255, 3 }"] = "02020FFE161E"
+
+	r := cosem.NewRegister("1-0:1.8.0.255")
+
+	var value uint32
+	err := r.Read(c, &value)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12345), value)
+
+	su, err := r.ReadScalerUnit(c)
+	assert.NoError(t, err)
+	assert.Equal(t, dlms.ScalerUnit{Scaler: -2, Unit: dlms.UnitWattHour}, su)
+
+	pv, err := r.ReadPhysicalValue(c)
+	assert.NoError(t, err)
+	assert.Equal(t, dlms.PhysicalValue{Value: 123.45, Unit: dlms.UnitWattHour}, pv)
+	assert.Equal(t, "123.45 Wh", pv.String())
+
+	err = r.Reset(c)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrInteger(0), c.invoked["{ 3, 1.0.1.8.0.255, 1 }"])
+}
+
+func TestDemandRegister(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 5, 1.0.1.4.0.255, 4 }"] = "02020F00161B"
+	c.attributes["{ 5, 1.0.1.4.0.255, 8 }"] = "0600000384"
+
+	r := cosem.NewDemandRegister("1-0:1.4.0.255")
+
+	su, err := r.ReadScalerUnit(c)
+	assert.NoError(t, err)
+	assert.Equal(t, dlms.ScalerUnit{Scaler: 0, Unit: dlms.UnitWatt}, su)
+
+	period, err := r.ReadPeriod(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "15m0s", period.String())
+
+	err = r.NextPeriod(c)
+	assert.NoError(t, err)
+	assert.Contains(t, c.invoked, "{ 5, 1.0.1.4.0.255, 2 }")
+}
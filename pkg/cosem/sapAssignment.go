@@ -0,0 +1,56 @@
+package cosem
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// SAP Assignment (class ID 17, version 0) lists the logical devices of a physical
+// device and their addresses.
+const (
+	SAPAssignmentAttributeList int8 = 2
+
+	SAPAssignmentMethodConnectLogicalDevice int8 = 1
+)
+
+// SAPAssignmentLogicalName is the logical name of the SAP Assignment object
+const SAPAssignmentLogicalName = "0-0:41.0.0.255"
+
+type LogicalDevice struct {
+	SAP  uint16
+	Name string
+}
+
+type SAPAssignment struct {
+	Object
+}
+
+// NewSAPAssignment returns the SAP Assignment object with the given logical name,
+// or SAPAssignmentLogicalName if it is empty.
+func NewSAPAssignment(logicalName string) *SAPAssignment {
+	if logicalName == "" {
+		logicalName = SAPAssignmentLogicalName
+	}
+
+	return &SAPAssignment{Object{ClassID: ClassIDSAPAssignment, LogicalName: logicalName}}
+}
+
+// ReadList returns the logical devices, with their names as text.
+func (s *SAPAssignment) ReadList(c dlms.Client) ([]LogicalDevice, error) {
+	var devices []LogicalDevice
+	if err := s.Get(c, SAPAssignmentAttributeList, &devices); err != nil {
+		return nil, err
+	}
+
+	for i := range devices {
+		name, err := hex.DecodeString(devices[i].Name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name of logical device %d: %w", devices[i].SAP, err)
+		}
+		devices[i].Name = string(name)
+	}
+
+	return devices, nil
+}
@@ -0,0 +1,17 @@
+package cosem_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+)
+
+func TestSAPAssignment(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 17, 0.0.41.0.0.255, 2 }"] = "0101" + "02021200010903434952"
+
+	devices, err := cosem.NewSAPAssignment("").ReadList(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []cosem.LogicalDevice{{SAP: 1, Name: "CIR"}}, devices)
+}
@@ -0,0 +1,69 @@
+package cosem
+
+import (
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Script Table (class ID 9, version 0) holds scripts, sequences of actions
+// executed on other objects.
+const (
+	ScriptTableAttributeScripts int8 = 2
+
+	ScriptTableMethodExecute int8 = 1
+)
+
+// ScriptService is the kind of action performed by a script
+type ScriptService uint8
+
+const (
+	ScriptServiceWriteAttribute ScriptService = iota + 1
+	ScriptServiceExecuteMethod
+)
+
+type ScriptAction struct {
+	ServiceID   ScriptService
+	ClassID     uint16
+	LogicalName string
+	Index       int8
+	Parameter   axdr.DlmsData
+}
+
+type Script struct {
+	ID      uint16
+	Actions []ScriptAction
+}
+
+type ScriptTable struct {
+	Object
+}
+
+func NewScriptTable(logicalName string) *ScriptTable {
+	return &ScriptTable{Object{ClassID: ClassIDScriptTable, LogicalName: logicalName}}
+}
+
+func (s *ScriptTable) ReadScripts(c dlms.Client) ([]Script, error) {
+	var scripts []Script
+	if err := s.Get(c, ScriptTableAttributeScripts, &scripts); err != nil {
+		return nil, err
+	}
+
+	for i := range scripts {
+		for j := range scripts[i].Actions {
+			logicalName, err := LogicalNameFromHex(scripts[i].Actions[j].LogicalName)
+			if err != nil {
+				return nil, fmt.Errorf("invalid action %d of script %d: %w", j, scripts[i].ID, err)
+			}
+			scripts[i].Actions[j].LogicalName = logicalName
+		}
+	}
+
+	return scripts, nil
+}
+
+// Execute runs the script with the given identifier.
+func (s *ScriptTable) Execute(c dlms.Client, scriptID uint16) error {
+	return s.Invoke(c, ScriptTableMethodExecute, axdr.CreateAxdrLongUnsigned(scriptID))
+}
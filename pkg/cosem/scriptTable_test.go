@@ -0,0 +1,39 @@
+package cosem_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+)
+
+func TestScriptTable(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 9, 0.0.10.0.106.255, 2 }"] = "0101" +
+		"0202120001" +
+		"0101" + "020516021200460906000060030" + "0FF0F010F00"
+
+	s := cosem.NewScriptTable("0-0:10.0.106.255")
+
+	scripts, err := s.ReadScripts(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []cosem.Script{
+		{
+			ID: 1,
+			Actions: []cosem.ScriptAction{
+				{
+					ServiceID:   cosem.ScriptServiceExecuteMethod,
+					ClassID:     70,
+					LogicalName: "0.0.96.3.0.255",
+					Index:       1,
+					Parameter:   *axdr.CreateAxdrInteger(0),
+				},
+			},
+		},
+	}, scripts)
+
+	err = s.Execute(c, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrLongUnsigned(1), c.invoked["{ 9, 0.0.10.0.106.255, 1 }"])
+}
@@ -0,0 +1,65 @@
+package cosem
+
+import (
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// Special Days Table (class ID 11, version 0) gives the day profile of the
+// activity calendar used on special days (e.g. holidays), instead of the one of
+// the week profile.
+const (
+	SpecialDaysTableAttributeEntries int8 = 2
+
+	SpecialDaysTableMethodInsert int8 = 1
+	SpecialDaysTableMethodDelete int8 = 2
+)
+
+// SpecialDaysTableLogicalName is the logical name of the special days table of the meter
+const SpecialDaysTableLogicalName = "0-0:11.0.0.255"
+
+// SpecialDay is an entry of the special days table
+type SpecialDay struct {
+	Index uint16
+	// Date is usually given with wildcards (e.g. the same day every year)
+	Date  axdr.CosemDate
+	DayID uint8
+}
+
+type SpecialDaysTable struct {
+	Object
+}
+
+// NewSpecialDaysTable returns the special days table with the given logical name,
+// or the special days table of the meter (SpecialDaysTableLogicalName) if it is empty.
+func NewSpecialDaysTable(logicalName string) *SpecialDaysTable {
+	if logicalName == "" {
+		logicalName = SpecialDaysTableLogicalName
+	}
+
+	return &SpecialDaysTable{Object{ClassID: ClassIDSpecialDaysTable, LogicalName: logicalName}}
+}
+
+func (s *SpecialDaysTable) ReadEntries(c dlms.Client) (entries []SpecialDay, err error) {
+	err = s.Get(c, SpecialDaysTableAttributeEntries, &entries)
+	return
+}
+
+// WriteEntries replaces all the entries of the table.
+func (s *SpecialDaysTable) WriteEntries(c dlms.Client, entries []SpecialDay) error {
+	if entries == nil {
+		entries = []SpecialDay{}
+	}
+
+	return s.Set(c, SpecialDaysTableAttributeEntries, entries)
+}
+
+// Insert adds an entry, or replaces the one with the same index.
+func (s *SpecialDaysTable) Insert(c dlms.Client, entry SpecialDay) error {
+	return s.Invoke(c, SpecialDaysTableMethodInsert, entry)
+}
+
+// Delete removes the entry with the given index.
+func (s *SpecialDaysTable) Delete(c dlms.Client, index uint16) error {
+	return s.Invoke(c, SpecialDaysTableMethodDelete, axdr.CreateAxdrLongUnsigned(index))
+}
@@ -0,0 +1,36 @@
+package cosem_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/cosem"
+)
+
+func TestSpecialDaysTable(t *testing.T) {
+	c := newFakeClient()
+	c.attributes["{ 11, 0.0.11.0.0.255, 2 }"] = "0102" +
+		"0203" + "120001" + "0905FFFF0C19FF" + "1102" +
+		"0203" + "120002" + "090507E8031DFF" + "1102"
+
+	s := cosem.NewSpecialDaysTable("")
+
+	entries, err := s.ReadEntries(c)
+	assert.NoError(t, err)
+	assert.Equal(t, []cosem.SpecialDay{
+		{Index: 1, Date: axdr.CosemDate{Year: axdr.YearNotSpecified, Month: 12, Day: 25, DayOfWeek: axdr.NotSpecified}, DayID: 2},
+		{Index: 2, Date: axdr.CosemDate{Year: 2024, Month: 3, Day: 29, DayOfWeek: axdr.NotSpecified}, DayID: 2},
+	}, entries)
+
+	err = s.Insert(c, entries[0])
+	assert.NoError(t, err)
+	out, err := c.invoked["{ 11, 0.0.11.0.0.255, 1 }"].Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, "02031200010905ffff0c19ff1102", hex.EncodeToString(out))
+
+	err = s.Delete(c, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrLongUnsigned(2), c.invoked["{ 11, 0.0.11.0.0.255, 2 }"])
+}
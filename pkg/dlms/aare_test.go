@@ -155,3 +155,29 @@ func TestDecodeAAREWithSecurity(t *testing.T) {
 	assert.Equal(t, uint8(6), aare.ConfirmedServiceError.Value)
 	assert.Nil(t, aare.ReceivedIC)
 }
+
+func FuzzDecodeAARE(f *testing.F) {
+	f.Add(decodeHexString("6129A109060760857405080101A203020100A305A103020100BE10040E0800065F1F040000101D00800007"))
+	f.Add(decodeHexString("611FA109060760857405080101A203020101A305A10302010DBE0604040E010600"))
+	f.Add(decodeHexString("6148A109060760857405080103A203020100A305A103020100A40A04084C475A2022604828BE230421281F300000003149963E23D6DA824A369644B66A9A17C60C3CA3F63E58608FA192"))
+
+	f.Fuzz(func(t *testing.T, src []byte) {
+		ciphered := src
+
+		_, _ = DecodeAARE(nil, &src)
+
+		ciphering, err := NewCiphering(
+			SecurityLevelDedicatedKey,
+			SecurityEncryption|SecurityAuthentication,
+			decodeHexString("4349520000000001"),
+			decodeHexString("00112233445566778899AABBCCDDEEFF"),
+			1,
+			decodeHexString("00112233445566778899AABBCCDDEEFF"),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = DecodeAARE(&Settings{Ciphering: ciphering}, &ciphered)
+	})
+}
@@ -107,10 +107,16 @@ func generateApplicationContextName(settings *Settings) (out []byte) {
 	// Application context name - 0xA1
 	buf.WriteByte(BERTypeContext | BERTypeConstructed | PduTypeApplicationContextName)
 	buf.Write([]byte{0x09, 0x06, 0x07, 0x60, 0x85, 0x74, 0x05, 0x08, 0x01})
-	if settings.Ciphering.Security == SecurityNone && len(settings.Ciphering.SystemTitle) == 0 {
-		buf.WriteByte(byte(ApplicationContextLNNoCiphering))
-	} else {
+	isCiphered := settings.Ciphering.Security != SecurityNone || len(settings.Ciphering.SystemTitle) != 0
+	switch {
+	case settings.Referencing == ReferencingShortName && isCiphered:
+		buf.WriteByte(byte(ApplicationContextSNCiphering))
+	case settings.Referencing == ReferencingShortName:
+		buf.WriteByte(byte(ApplicationContextSNNoCiphering))
+	case isCiphered:
 		buf.WriteByte(byte(ApplicationContextLNCiphering))
+	default:
+		buf.WriteByte(byte(ApplicationContextLNNoCiphering))
 	}
 
 	if len(settings.Ciphering.SystemTitle) > 0 {
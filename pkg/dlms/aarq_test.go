@@ -54,3 +54,14 @@ func TestEncodeAARQWithLowAuthenticationAndCipher(t *testing.T) {
 	_, err = EncodeAARQ(&settings)
 	assert.Error(t, err)
 }
+
+func TestEncodeAARQWithShortNameReferencing(t *testing.T) {
+	settings, _ := NewSettingsWithoutAuthentication()
+	settings.EnableShortNameReferencing()
+
+	out, err := EncodeAARQ(&settings)
+	assert.NoError(t, err)
+
+	expected := decodeHexString("601DA109060760857405080102BE10040E01000000065F1F04001C1B200100")
+	assert.Equal(t, expected, out)
+}
@@ -48,6 +48,11 @@ func (gr *ActionRequest) New(tag actionRequestTag) (out CosemPDU, err error) {
 }
 
 func (gr *ActionRequest) Decode(src *[]byte) (out CosemPDU, err error) {
+	if len(*src) < 2 {
+		err = ErrWrongLength(len(*src), 2)
+		return
+	}
+
 	if (*src)[0] != TagActionRequest.Value() {
 		err = ErrWrongTag(0, (*src)[0], byte(TagActionRequest))
 		return
@@ -88,6 +93,11 @@ func CreateActionRequestNormal(invokeID uint8, mth MethodDescriptor, dt *axdr.Dl
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionRequestNormal) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionRequestNormal) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionRequest))
@@ -118,6 +128,11 @@ func (ar ActionRequestNormal) Encode() (out []byte, err error) {
 func DecodeActionRequestNormal(ori *[]byte) (out ActionRequestNormal, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagActionRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionRequest))
 		return
@@ -133,6 +148,10 @@ func DecodeActionRequestNormal(ori *[]byte) (out ActionRequestNormal, err error)
 		return
 	}
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
 	haveMethodParam := src[0]
 	src = src[1:]
 	if haveMethodParam == 0 {
@@ -165,6 +184,11 @@ func CreateActionRequestNextPBlock(invokeID uint8, blockNum uint32) *ActionReque
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionRequestNextPBlock) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionRequestNextPBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionRequest))
@@ -180,6 +204,11 @@ func (ar ActionRequestNextPBlock) Encode() (out []byte, err error) {
 func DecodeActionRequestNextPBlock(ori *[]byte) (out ActionRequestNextPBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagActionRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionRequest))
 		return
@@ -227,6 +256,11 @@ func CreateActionRequestWithList(invokeID uint8, mthList []MethodDescriptor, val
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionRequestWithList) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionRequestWithList) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionRequest))
@@ -258,6 +292,11 @@ func (ar ActionRequestWithList) Encode() (out []byte, err error) {
 func DecodeActionRequestWithList(ori *[]byte) (out ActionRequestWithList, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagActionRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionRequest))
 		return
@@ -279,6 +318,10 @@ func DecodeActionRequestWithList(ori *[]byte) (out ActionRequestWithList, err er
 		out.MethodInfoList = append(out.MethodInfoList, v)
 	}
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
 	out.MethodParamCount = src[0]
 	src = src[1:]
 	for i := 0; i < int(out.MethodParamCount); i++ {
@@ -310,6 +353,11 @@ func CreateActionRequestWithFirstPBlock(invokeID uint8, mth MethodDescriptor, dt
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionRequestWithFirstPBlock) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionRequestWithFirstPBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionRequest))
@@ -335,6 +383,11 @@ func (ar ActionRequestWithFirstPBlock) Encode() (out []byte, err error) {
 func DecodeActionRequestWithFirstPBlock(ori *[]byte) (out ActionRequestWithFirstPBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagActionRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionRequest))
 		return
@@ -376,6 +429,11 @@ func CreateActionRequestWithListAndFirstPBlock(invokeID uint8, mthList []MethodD
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionRequestWithListAndFirstPBlock) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionRequestWithListAndFirstPBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionRequest))
@@ -404,6 +462,11 @@ func (ar ActionRequestWithListAndFirstPBlock) Encode() (out []byte, err error) {
 func DecodeActionRequestWithListAndFirstPBlock(ori *[]byte) (out ActionRequestWithListAndFirstPBlock, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagActionRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionRequest))
 		return
@@ -444,6 +507,11 @@ func CreateActionRequestWithPBlock(invokeID uint8, dt DataBlockSA) *ActionReques
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionRequestWithPBlock) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionRequestWithPBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionRequest))
@@ -463,6 +531,11 @@ func (ar ActionRequestWithPBlock) Encode() (out []byte, err error) {
 func DecodeActionRequestWithPBlock(ori *[]byte) (out ActionRequestWithPBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagActionRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionRequest))
 		return
@@ -42,6 +42,11 @@ func (gr *ActionResponse) New(tag actionResponseTag) (out CosemPDU, err error) {
 }
 
 func (gr *ActionResponse) Decode(src *[]byte) (out CosemPDU, err error) {
+	if len(*src) < 2 {
+		err = ErrWrongLength(len(*src), 2)
+		return
+	}
+
 	if (*src)[0] != TagActionResponse.Value() {
 		err = ErrWrongTag(0, (*src)[0], byte(TagActionResponse))
 		return
@@ -75,6 +80,11 @@ func CreateActionResponseNormal(invokeID uint8, res ActResponse) *ActionResponse
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionResponseNormal) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionResponseNormal) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionResponse))
@@ -94,6 +104,11 @@ func (ar ActionResponseNormal) Encode() (out []byte, err error) {
 func DecodeActionResponseNormal(ori *[]byte) (out ActionResponseNormal, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagActionResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionResponse))
 		return
@@ -123,6 +138,11 @@ func CreateActionResponseWithPBlock(invokeID uint8, dt DataBlockSA) *ActionRespo
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionResponseWithPBlock) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionResponseWithPBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionResponse))
@@ -142,6 +162,11 @@ func (ar ActionResponseWithPBlock) Encode() (out []byte, err error) {
 func DecodeActionResponseWithPBlock(ori *[]byte) (out ActionResponseWithPBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagActionResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionResponse))
 		return
@@ -176,6 +201,11 @@ func CreateActionResponseWithList(invokeID uint8, resList []ActResponse) *Action
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionResponseWithList) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionResponseWithList) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionResponse))
@@ -198,6 +228,11 @@ func (ar ActionResponseWithList) Encode() (out []byte, err error) {
 func DecodeActionResponseWithList(ori *[]byte) (out ActionResponseWithList, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagActionResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionResponse))
 		return
@@ -235,6 +270,11 @@ func CreateActionResponseNextPBlock(invokeID uint8, blockNum uint32) *ActionResp
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (ar ActionResponseNextPBlock) InvokeID() uint8 {
+	return ar.InvokePriority & InvokeIDMask
+}
+
 func (ar ActionResponseNextPBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagActionResponse))
@@ -250,6 +290,11 @@ func (ar ActionResponseNextPBlock) Encode() (out []byte, err error) {
 func DecodeActionResponseNextPBlock(ori *[]byte) (out ActionResponseNextPBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagActionResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagActionResponse))
 		return
@@ -3,6 +3,8 @@ package dlms
 import (
 	"encoding/binary"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"gitlab.com/circutor-library/gosem/pkg/axdr"
 )
@@ -19,6 +21,38 @@ func CreateAttributeDescriptor(c uint16, i string, a int8) *AttributeDescriptor
 	return &AttributeDescriptor{ClassID: c, InstanceID: ob, AttributeID: a}
 }
 
+// ParseObisTag parses the obis tag of a struct field, in the form "class,logical
+// name,attribute" optionally followed by ",scaled". It returns nil if the tag is empty.
+func ParseObisTag(tag string) (att *AttributeDescriptor, scaled bool, err error) {
+	if tag == "" {
+		return nil, false, nil
+	}
+
+	values := strings.Split(tag, ",")
+	if len(values) != 3 && len(values) != 4 {
+		return nil, false, fmt.Errorf("invalid obis tag: %s", tag)
+	}
+
+	class, err := strconv.ParseUint(values[0], 0, 16)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid class: %s", tag)
+	}
+
+	attribute, err := strconv.ParseUint(values[2], 0, 8)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid attribute: %s", tag)
+	}
+
+	if len(values) == 4 {
+		if values[3] != "scaled" {
+			return nil, false, fmt.Errorf("invalid option: %s", tag)
+		}
+		scaled = true
+	}
+
+	return CreateAttributeDescriptor(uint16(class), values[1], int8(attribute)), scaled, nil
+}
+
 func (ad AttributeDescriptor) Encode() (out []byte, err error) {
 	var output []byte
 	var c [2]byte
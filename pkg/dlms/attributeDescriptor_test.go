@@ -37,3 +37,26 @@ func TestAttributeDescriptor_Decode(t *testing.T) {
 		t.Errorf("t1 reminder failed. get: %v, should: [1, 2, 3]", src)
 	}
 }
+
+func TestParseObisTag(t *testing.T) {
+	att, scaled, err := ParseObisTag("3,1-0:1.8.0.255,2")
+	if err != nil || scaled || *att != *CreateAttributeDescriptor(3, "1-0:1.8.0.255", 2) {
+		t.Errorf("t1 failed. get: %v, %v, %v", att, scaled, err)
+	}
+
+	att, scaled, err = ParseObisTag("3,1-0:1.8.0.255,2,scaled")
+	if err != nil || !scaled || *att != *CreateAttributeDescriptor(3, "1-0:1.8.0.255", 2) {
+		t.Errorf("t2 failed. get: %v, %v, %v", att, scaled, err)
+	}
+
+	att, _, err = ParseObisTag("")
+	if err != nil || att != nil {
+		t.Errorf("t3 failed. get: %v, %v", att, err)
+	}
+
+	for _, tag := range []string{"3,1-0:1.8.0.255", "x,1-0:1.8.0.255,2", "3,1-0:1.8.0.255,x", "3,1-0:1.8.0.255,2,unscaled"} {
+		if _, _, err := ParseObisTag(tag); err == nil {
+			t.Errorf("%s should have failed", tag)
+		}
+	}
+}
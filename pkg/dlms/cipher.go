@@ -56,6 +56,10 @@ func CipherData(cfg Cipher, data []byte) ([]byte, error) {
 }
 
 func DecipherData(cfg *Cipher, data []byte) ([]byte, error) {
+	if len(data) < 1 {
+		return nil, ErrWrongLength(len(data), 1)
+	}
+
 	// Check COSEM tag
 	if data[0] != byte(cfg.Tag) {
 		return nil, ErrWrongTag(0, data[0], byte(cfg.Tag))
@@ -73,6 +77,11 @@ func DecipherData(cfg *Cipher, data []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	// Security control byte and frame counter
+	if len(data) < 5 {
+		return nil, ErrWrongLength(len(data), 5)
+	}
+
 	// Check security level
 	if data[0] != byte(cfg.Security) {
 		return nil, errors.New("wrong security level")
@@ -74,6 +74,15 @@ func TestDecipherData(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDecipherDataShort(t *testing.T) {
+	cfg := Cipher{Tag: TagGloInitiateRequest, Security: SecurityEncryption}
+
+	for _, data := range [][]byte{nil, {0x21}, {0x21, 0x00}, {0x21, 0x03, 0x30, 0x00, 0x00}} {
+		_, err := DecipherData(&cfg, data)
+		assert.Error(t, err)
+	}
+}
+
 func decodeHexString(s string) []byte {
 	b, _ := hex.DecodeString(s)
 	return b
@@ -1,6 +1,8 @@
 package dlms
 
 import (
+	"context"
+	"iter"
 	"log"
 	"time"
 
@@ -12,6 +14,16 @@ type Notification struct {
 	DataNotification DataNotification
 }
 
+// ReconnectPolicy defines how the client recovers from a lost connection or
+// association: the transport is reconnected, the association is established again
+// and idempotent requests (GET and READ) are retried with exponential backoff.
+type ReconnectPolicy struct {
+	MaxRetries     int           // Retries after the first failure. Zero disables the policy.
+	InitialBackoff time.Duration // Wait before the first retry.
+	MaxBackoff     time.Duration // Upper limit of the wait between retries. Zero means no limit.
+	Multiplier     float64       // Growth of the wait after each retry. Values lower than 1 mean 2.
+}
+
 //go:generate mockery --name Client --structname ClientMock --filename clientMock.go
 
 // Client specifies the client layer.
@@ -23,17 +35,238 @@ type Client interface {
 	SetSettings(settings Settings)
 	SetAddress(client int, server int)
 	SetLogger(logger *log.Logger)
+	SetReconnectPolicy(policy ReconnectPolicy)
 	Associate() error
 	CloseAssociation() error
 	IsAssociated() bool
 	SetNotificationChannel(id string, nc chan Notification)
 	GetRequest(att *AttributeDescriptor, data interface{}) (err error)
+	// Deprecated: use GetRequestWithRange, which builds the range descriptor.
 	GetRequestWithSelectiveAccess(att *AttributeDescriptor, selectiveAccess axdr.DlmsData, data interface{}) (err error)
+	GetRequestWithRange(att *AttributeDescriptor, rd RangeDescriptor, data interface{}) (err error)
+	GetRequestWithEntries(att *AttributeDescriptor, ed EntryDescriptor, data interface{}) (err error)
 	GetRequestWithSelectiveAccessByDate(att *AttributeDescriptor, start time.Time, end time.Time, data interface{}) (err error)
 	GetRequestWithSelectiveAccessByDateAndValues(att *AttributeDescriptor, start time.Time, end time.Time, values []AttributeDescriptor, data interface{}) (err error)
+	GetRequestWithList(atts []*AttributeDescriptor, data []interface{}) (err error)
 	GetRequestWithStructOfElements(data interface{}) (err error)
+	// GetRequestRows yields the elements of an array attribute (e.g. the rows of a
+	// profile buffer) while its blocks are still arriving. The selective access may be nil.
+	GetRequestRows(att *AttributeDescriptor, acc *SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error]
 	SetRequest(att *AttributeDescriptor, data interface{}) (err error)
 	SetRequestWithStructOfElements(data interface{}, continueOnSetRejected bool) (err error)
 	ActionRequest(mth *MethodDescriptor, data interface{}) (err error)
 	CheckRequestWithStructOfElements(data interface{}) (err error)
+	ReadRequest(sn uint16, data interface{}) (err error)
+	ReadRequestWithParameters(sn uint16, selector uint8, parameter axdr.DlmsData, data interface{}) (err error)
+	WriteRequest(sn uint16, data interface{}) (err error)
+	UnconfirmedWriteRequest(sn uint16, data interface{}) (err error)
+}
+
+// ContextClient is a Client whose requests also accept a context. Canceling the
+// context aborts the request (including any pending block transfer) and its
+// deadline bounds the whole operation.
+type ContextClient interface {
+	Client
+	ConnectContext(ctx context.Context) error
+	AssociateContext(ctx context.Context) error
+	CloseAssociationContext(ctx context.Context) error
+	GetRequestContext(ctx context.Context, att *AttributeDescriptor, data interface{}) (err error)
+	GetRequestWithSelectiveAccessContext(ctx context.Context, att *AttributeDescriptor, selectiveAccess axdr.DlmsData, data interface{}) (err error)
+	GetRequestWithRangeContext(ctx context.Context, att *AttributeDescriptor, rd RangeDescriptor, data interface{}) (err error)
+	GetRequestWithEntriesContext(ctx context.Context, att *AttributeDescriptor, ed EntryDescriptor, data interface{}) (err error)
+	GetRequestWithSelectiveAccessByDateContext(ctx context.Context, att *AttributeDescriptor, start time.Time, end time.Time, data interface{}) (err error)
+	GetRequestWithSelectiveAccessByDateAndValuesContext(ctx context.Context, att *AttributeDescriptor, start time.Time, end time.Time, values []AttributeDescriptor, data interface{}) (err error)
+	GetRequestWithListContext(ctx context.Context, atts []*AttributeDescriptor, data []interface{}) (err error)
+	GetRequestWithStructOfElementsContext(ctx context.Context, data interface{}) (err error)
+	GetRequestRowsContext(ctx context.Context, att *AttributeDescriptor, acc *SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error]
+	SetRequestContext(ctx context.Context, att *AttributeDescriptor, data interface{}) (err error)
+	SetRequestWithStructOfElementsContext(ctx context.Context, data interface{}, continueOnSetRejected bool) (err error)
+	ActionRequestContext(ctx context.Context, mth *MethodDescriptor, data interface{}) (err error)
+	CheckRequestWithStructOfElementsContext(ctx context.Context, data interface{}) (err error)
+	ReadRequestContext(ctx context.Context, sn uint16, data interface{}) (err error)
+	ReadRequestWithParametersContext(ctx context.Context, sn uint16, selector uint8, parameter axdr.DlmsData, data interface{}) (err error)
+	WriteRequestContext(ctx context.Context, sn uint16, data interface{}) (err error)
+	UnconfirmedWriteRequestContext(ctx context.Context, sn uint16, data interface{}) (err error)
+}
+
+// AsContextClient returns c itself if it is already a ContextClient. Otherwise, it
+// returns a ContextClient whose requests check the context before calling the
+// methods of c, which cannot be interrupted once started.
+func AsContextClient(c Client) ContextClient {
+	if cc, ok := c.(ContextClient); ok {
+		return cc
+	}
+
+	return &contextClient{Client: c}
+}
+
+type contextClient struct {
+	Client
+}
+
+func (c *contextClient) ConnectContext(ctx context.Context) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.Connect()
+}
+
+func (c *contextClient) AssociateContext(ctx context.Context) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.Associate()
+}
+
+func (c *contextClient) CloseAssociationContext(ctx context.Context) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.CloseAssociation()
+}
+
+func (c *contextClient) GetRequestContext(ctx context.Context, att *AttributeDescriptor, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.GetRequest(att, data)
+}
+
+func (c *contextClient) GetRequestWithSelectiveAccessContext(ctx context.Context, att *AttributeDescriptor, selectiveAccess axdr.DlmsData, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.GetRequestWithSelectiveAccess(att, selectiveAccess, data)
+}
+
+func (c *contextClient) GetRequestWithRangeContext(ctx context.Context, att *AttributeDescriptor, rd RangeDescriptor, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.GetRequestWithRange(att, rd, data)
+}
+
+func (c *contextClient) GetRequestWithEntriesContext(ctx context.Context, att *AttributeDescriptor, ed EntryDescriptor, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.GetRequestWithEntries(att, ed, data)
+}
+
+func (c *contextClient) GetRequestWithSelectiveAccessByDateContext(ctx context.Context, att *AttributeDescriptor, start time.Time, end time.Time, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.GetRequestWithSelectiveAccessByDate(att, start, end, data)
+}
+
+func (c *contextClient) GetRequestWithSelectiveAccessByDateAndValuesContext(ctx context.Context, att *AttributeDescriptor, start time.Time, end time.Time, values []AttributeDescriptor, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.GetRequestWithSelectiveAccessByDateAndValues(att, start, end, values, data)
+}
+
+func (c *contextClient) GetRequestWithListContext(ctx context.Context, atts []*AttributeDescriptor, data []interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.GetRequestWithList(atts, data)
+}
+
+func (c *contextClient) GetRequestWithStructOfElementsContext(ctx context.Context, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.GetRequestWithStructOfElements(data)
+}
+
+func (c *contextClient) GetRequestRowsContext(ctx context.Context, att *AttributeDescriptor, acc *SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error] {
+	return func(yield func(axdr.DlmsData, error) bool) {
+		if err := ContextError(ctx.Err()); err != nil {
+			yield(axdr.DlmsData{}, err)
+			return
+		}
+
+		for row, err := range c.GetRequestRows(att, acc) {
+			if !yield(row, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *contextClient) SetRequestContext(ctx context.Context, att *AttributeDescriptor, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.SetRequest(att, data)
+}
+
+func (c *contextClient) SetRequestWithStructOfElementsContext(ctx context.Context, data interface{}, continueOnSetRejected bool) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.SetRequestWithStructOfElements(data, continueOnSetRejected)
+}
+
+func (c *contextClient) ActionRequestContext(ctx context.Context, mth *MethodDescriptor, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.ActionRequest(mth, data)
+}
+
+func (c *contextClient) CheckRequestWithStructOfElementsContext(ctx context.Context, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.CheckRequestWithStructOfElements(data)
+}
+
+func (c *contextClient) ReadRequestContext(ctx context.Context, sn uint16, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.ReadRequest(sn, data)
+}
+
+func (c *contextClient) ReadRequestWithParametersContext(ctx context.Context, sn uint16, selector uint8, parameter axdr.DlmsData, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.ReadRequestWithParameters(sn, selector, parameter, data)
+}
+
+func (c *contextClient) WriteRequestContext(ctx context.Context, sn uint16, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.WriteRequest(sn, data)
+}
+
+func (c *contextClient) UnconfirmedWriteRequestContext(ctx context.Context, sn uint16, data interface{}) error {
+	if err := ContextError(ctx.Err()); err != nil {
+		return err
+	}
+
+	return c.UnconfirmedWriteRequest(sn, data)
 }
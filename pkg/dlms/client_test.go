@@ -0,0 +1,38 @@
+package dlms_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// plainClient implements only the requests without context.
+type plainClient struct {
+	dlms.Client
+	gets int
+}
+
+func (c *plainClient) GetRequest(_ *dlms.AttributeDescriptor, _ interface{}) error {
+	c.gets++
+	return nil
+}
+
+func TestAsContextClient(t *testing.T) {
+	c := &plainClient{}
+	cc := dlms.AsContextClient(c)
+
+	att := dlms.CreateAttributeDescriptor(3, "1-0:1.8.0.255", 2)
+	assert.NoError(t, cc.GetRequestContext(context.Background(), att, nil))
+	assert.Equal(t, 1, c.gets)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := cc.GetRequestContext(ctx, att, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, c.gets)
+
+	assert.Same(t, cc, dlms.AsContextClient(cc))
+}
@@ -6,23 +6,28 @@ type CosemTag uint8
 
 const (
 	// ---- standardized DLMS APDUs
-	TagInitiateRequest          CosemTag = 1
-	TagReadRequest              CosemTag = 5
-	TagWriteRequest             CosemTag = 6
-	TagInitiateResponse         CosemTag = 8
-	TagReadResponse             CosemTag = 12
-	TagWriteResponse            CosemTag = 13
-	TagConfirmedServiceError    CosemTag = 14
-	TagDataNotification         CosemTag = 15
-	TagUnconfirmedWriteRequest  CosemTag = 22
-	TagInformationReportRequest CosemTag = 24
-	TagGloInitiateRequest       CosemTag = 33
-	TagGloInitiateResponse      CosemTag = 40
-	TagGloConfirmedServiceError CosemTag = 46
-	TagAARQ                     CosemTag = 96
-	TagAARE                     CosemTag = 97
-	TagRLRQ                     CosemTag = 98
-	TagRLRE                     CosemTag = 99
+	TagInitiateRequest            CosemTag = 1
+	TagReadRequest                CosemTag = 5
+	TagWriteRequest               CosemTag = 6
+	TagInitiateResponse           CosemTag = 8
+	TagReadResponse               CosemTag = 12
+	TagWriteResponse              CosemTag = 13
+	TagConfirmedServiceError      CosemTag = 14
+	TagDataNotification           CosemTag = 15
+	TagUnconfirmedWriteRequest    CosemTag = 22
+	TagInformationReportRequest   CosemTag = 24
+	TagGloInitiateRequest         CosemTag = 33
+	TagGloReadRequest             CosemTag = 37
+	TagGloWriteRequest            CosemTag = 38
+	TagGloInitiateResponse        CosemTag = 40
+	TagGloReadResponse            CosemTag = 44
+	TagGloWriteResponse           CosemTag = 45
+	TagGloConfirmedServiceError   CosemTag = 46
+	TagGloUnconfirmedWriteRequest CosemTag = 54
+	TagAARQ                       CosemTag = 96
+	TagAARE                       CosemTag = 97
+	TagRLRQ                       CosemTag = 98
+	TagRLRE                       CosemTag = 99
 	// --- APDUs used for data communication services
 	TagGetRequest               CosemTag = 192
 	TagSetRequest               CosemTag = 193
@@ -77,6 +82,22 @@ type CosemPDU interface {
 	Encode() ([]byte, error)
 }
 
+// CosemPDUWithInvokeID is implemented by the PDUs that carry an Invoke-Id-And-Priority field.
+type CosemPDUWithInvokeID interface {
+	InvokeID() uint8
+}
+
+// GetInvokeID returns the invoke-id (lower nibble of the Invoke-Id-And-Priority
+// field) of the PDU. The second value is false if the PDU does not carry one.
+func GetInvokeID(pdu CosemPDU) (uint8, bool) {
+	p, ok := pdu.(CosemPDUWithInvokeID)
+	if !ok {
+		return 0, false
+	}
+
+	return p.InvokeID(), true
+}
+
 // DecodeCosem is a global function to decode payload based on implemented DLMS/COSEM APDU en/decoder
 func DecodeCosem(src *[]byte) (out CosemPDU, err error) {
 	if len(*src) == 0 {
@@ -88,6 +109,16 @@ func DecodeCosem(src *[]byte) (out CosemPDU, err error) {
 		out, err = DecodeConfirmedServiceError(src)
 	case TagDataNotification.Value():
 		out, err = DecodeDataNotification(src)
+	case TagReadRequest.Value():
+		out, err = DecodeReadRequest(src)
+	case TagReadResponse.Value():
+		out, err = DecodeReadResponse(src)
+	case TagWriteRequest.Value():
+		out, err = DecodeWriteRequest(src)
+	case TagWriteResponse.Value():
+		out, err = DecodeWriteResponse(src)
+	case TagUnconfirmedWriteRequest.Value():
+		out, err = DecodeUnconfirmedWriteRequest(src)
 	case TagGetRequest.Value():
 		var decoder GetRequest
 		out, err = decoder.Decode(src)
@@ -314,6 +314,61 @@ func TestDecode_cosem(t *testing.T) {
 		t.Errorf("Decode supposed to return ExceptionResponse instead of %v", reflect.TypeOf(res).Name())
 	}
 
+	// ------------------  ReadRequest
+	srcReadRequest := []byte{5, 1, 2, 250, 0}
+	res, e = DecodeCosem(&srcReadRequest)
+	if e != nil {
+		t.Errorf("Decode for ReadRequest Failed. err:%v", e)
+	}
+	_, assertTrue = res.(ReadRequest)
+	if !assertTrue {
+		t.Errorf("Decode supposed to return ReadRequest instead of %v", reflect.TypeOf(res).Name())
+	}
+
+	// ------------------  ReadResponse
+	srcReadResponse := []byte{12, 1, 0, 18, 0, 60}
+	res, e = DecodeCosem(&srcReadResponse)
+	if e != nil {
+		t.Errorf("Decode for ReadResponse Failed. err:%v", e)
+	}
+	_, assertTrue = res.(ReadResponse)
+	if !assertTrue {
+		t.Errorf("Decode supposed to return ReadResponse instead of %v", reflect.TypeOf(res).Name())
+	}
+
+	// ------------------  WriteRequest
+	srcWriteRequest := []byte{6, 1, 2, 250, 8, 1, 18, 0, 5}
+	res, e = DecodeCosem(&srcWriteRequest)
+	if e != nil {
+		t.Errorf("Decode for WriteRequest Failed. err:%v", e)
+	}
+	_, assertTrue = res.(WriteRequest)
+	if !assertTrue {
+		t.Errorf("Decode supposed to return WriteRequest instead of %v", reflect.TypeOf(res).Name())
+	}
+
+	// ------------------  WriteResponse
+	srcWriteResponse := []byte{13, 1, 0}
+	res, e = DecodeCosem(&srcWriteResponse)
+	if e != nil {
+		t.Errorf("Decode for WriteResponse Failed. err:%v", e)
+	}
+	_, assertTrue = res.(WriteResponse)
+	if !assertTrue {
+		t.Errorf("Decode supposed to return WriteResponse instead of %v", reflect.TypeOf(res).Name())
+	}
+
+	// ------------------  UnconfirmedWriteRequest
+	srcUnconfirmedWriteRequest := []byte{22, 1, 2, 250, 8, 1, 18, 0, 5}
+	res, e = DecodeCosem(&srcUnconfirmedWriteRequest)
+	if e != nil {
+		t.Errorf("Decode for UnconfirmedWriteRequest Failed. err:%v", e)
+	}
+	_, assertTrue = res.(UnconfirmedWriteRequest)
+	if !assertTrue {
+		t.Errorf("Decode supposed to return UnconfirmedWriteRequest instead of %v", reflect.TypeOf(res).Name())
+	}
+
 	// ------------------  Error test
 	srcError := []byte{255, 255, 255}
 	_, wow := DecodeCosem(&srcError)
@@ -321,3 +376,69 @@ func TestDecode_cosem(t *testing.T) {
 		t.Errorf("Decode should've return error.")
 	}
 }
+
+func TestGetInvokeID(t *testing.T) {
+	if id, ok := GetInvokeID(*CreateGetRequestNext(0xC5, 1)); !ok || id != 5 {
+		t.Errorf("Expected invoke-id 5, got %d (%v)", id, ok)
+	}
+
+	if id, ok := GetInvokeID(CreateGetRequestNext(0x4F, 1)); !ok || id != 15 {
+		t.Errorf("Expected invoke-id 15, got %d (%v)", id, ok)
+	}
+
+	if id, ok := GetInvokeID(*CreateSetResponseNormal(0x83, TagAccSuccess)); !ok || id != 3 {
+		t.Errorf("Expected invoke-id 3, got %d (%v)", id, ok)
+	}
+
+	if _, ok := GetInvokeID(*CreateReadRequest(nil)); ok {
+		t.Errorf("Read request should not have invoke-id")
+	}
+}
+
+func TestDecodeCosemTruncated(t *testing.T) {
+	pdus := [][]byte{
+		{192, 1, 81, 0, 1, 1, 0, 0, 3, 0, 255, 2, 1, 2, 2, 4, 6, 0, 0, 0, 0, 6, 0, 0, 0, 5, 18, 0, 0, 18, 0, 0},
+		{193, 4, 69, 1, 0, 1, 1, 0, 0, 3, 0, 255, 2, 1, 2, 2, 4, 6, 0, 0, 0, 0, 6, 0, 0, 0, 5, 18, 0, 0, 18, 0, 0, 1, 9, 5, 1, 2, 3, 4, 5},
+		{195, 3, 81, 1, 0, 1, 1, 0, 0, 3, 0, 255, 2, 1, 9, 5, 1, 2, 3, 4, 5},
+		{196, 2, 69, 0, 0, 0, 0, 1, 0, 3, 1, 2, 3},
+		{197, 4, 81, 3, 0, 1, 250, 0, 0, 0, 1},
+		{199, 1, 81, 0, 1, 0},
+		decodeHexString("C2010C05DC0101010000000000000000010100000300FF020301"),
+	}
+
+	for _, pdu := range pdus {
+		for i := 0; i < len(pdu); i++ {
+			src := pdu[:i]
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("Decoding % X panics: %v", pdu[:i], r)
+					}
+				}()
+				_, _ = DecodeCosem(&src)
+			}()
+		}
+	}
+}
+
+func FuzzDecodeCosem(f *testing.F) {
+	f.Add([]byte{192, 1, 81, 0, 1, 1, 0, 0, 3, 0, 255, 2, 1, 2, 2, 4, 6, 0, 0, 0, 0, 6, 0, 0, 0, 5, 18, 0, 0, 18, 0, 0})
+	f.Add([]byte{196, 3, 69, 2, 1, 0, 0, 5, 0, 0, 0, 1})
+	f.Add([]byte{193, 4, 69, 1, 0, 1, 1, 0, 0, 3, 0, 255, 2, 1, 2, 2, 4, 6, 0, 0, 0, 0, 6, 0, 0, 0, 5, 18, 0, 0, 18, 0, 0, 1, 9, 5, 1, 2, 3, 4, 5})
+	f.Add([]byte{195, 3, 81, 1, 0, 1, 1, 0, 0, 3, 0, 255, 2, 1, 9, 5, 1, 2, 3, 4, 5})
+	f.Add([]byte{199, 3, 81, 1, 0, 1, 1, 0})
+	f.Add([]byte{197, 4, 81, 3, 0, 1, 250, 0, 0, 0, 1})
+	f.Add([]byte{5, 1, 2, 250, 0})
+	f.Add([]byte{12, 1, 0, 18, 0, 60})
+	f.Add([]byte{6, 1, 2, 250, 8, 1, 18, 0, 5})
+	f.Add([]byte{13, 1, 0})
+	f.Add([]byte{14, 1, 6, 1})
+	f.Add([]byte{216, 1, 2})
+	f.Add(decodeHexString("0F0063D76A0C07E7011F02122217000000000301"))
+	f.Add(decodeHexString("C2010C05DC0101010000000000000000010100000300FF020301"))
+	f.Add(decodeHexString("C401C10013020201FFFF01FFFF00110105"))
+
+	f.Fuzz(func(t *testing.T, src []byte) {
+		_, _ = DecodeCosem(&src)
+	})
+}
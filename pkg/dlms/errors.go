@@ -2,6 +2,12 @@
 
 package dlms
 
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
 type ErrorCode int
 
 const (
@@ -19,11 +25,17 @@ const (
 	ErrorActionRejected
 	ErrorSetPartial
 	ErrorCheckDoesNotMatch
+	ErrorReadRejected
+	ErrorWriteRejected
+	ErrorCanceled
+	ErrorReconnectFailed
+	ErrorAccessDenied
 )
 
 type Error struct {
 	code ErrorCode
 	msg  string
+	err  error
 }
 
 func NewError(code ErrorCode, msg string) *Error {
@@ -33,6 +45,32 @@ func NewError(code ErrorCode, msg string) *Error {
 	}
 }
 
+// NewErrorWithCause creates an error that wraps the given cause, so it can be
+// checked with errors.Is (e.g. against context.Canceled).
+func NewErrorWithCause(code ErrorCode, msg string, cause error) *Error {
+	return &Error{
+		code: code,
+		msg:  msg,
+		err:  cause,
+	}
+}
+
+// ContextError converts the error of a done context (ctx.Err()) into an error that
+// still matches context.Canceled or context.DeadlineExceeded with errors.Is. A
+// deadline exceeded is a communication failure, while a cancellation is
+// ErrorCanceled. It returns nil if err is nil.
+func ContextError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NewErrorWithCause(ErrorCommunicationFailed, fmt.Sprintf("deadline exceeded: %v", err), err)
+	}
+
+	return NewErrorWithCause(ErrorCanceled, fmt.Sprintf("request canceled: %v", err), err)
+}
+
 func (ce *Error) Error() string {
 	return ce.msg
 }
@@ -40,3 +78,7 @@ func (ce *Error) Error() string {
 func (ce *Error) Code() ErrorCode {
 	return ce.code
 }
+
+func (ce *Error) Unwrap() error {
+	return ce.err
+}
@@ -76,7 +76,16 @@ func (dt GetDataResult) ValueAsAccess() (out AccessResultTag, err error) {
 func DecodeGetDataResult(ori *[]byte) (out GetDataResult, err error) {
 	src := *ori
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
+
 	if src[0] == 0x1 {
+		if len(src) < 2 {
+			err = ErrWrongLength(len(src), 2)
+			return
+		}
 		out.IsData = false
 		out.Value, err = GetAccessTag(src[1])
 		if err == nil {
@@ -198,6 +207,11 @@ func (dt DataBlockG) ResultAsAccess() (out AccessResultTag, err error) {
 func DecodeDataBlockG(ori *[]byte) (out DataBlockG, err error) {
 	src := *ori
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
+
 	if src[0] == 0x0 {
 		out.LastBlock = false
 	} else {
@@ -206,6 +220,14 @@ func DecodeDataBlockG(ori *[]byte) (out DataBlockG, err error) {
 	src = src[1:]
 
 	_, out.BlockNumber, err = axdr.DecodeDoubleLongUnsigned(&src)
+	if err != nil {
+		return
+	}
+
+	if len(src) < 2 {
+		err = ErrWrongLength(len(src), 2)
+		return
+	}
 
 	if src[0] == 0x0 {
 		out.IsResult = false
@@ -218,7 +240,16 @@ func DecodeDataBlockG(ori *[]byte) (out DataBlockG, err error) {
 		out.Result, err = GetAccessTag(src[0])
 		src = src[1:]
 	} else {
-		_, val, _ := axdr.DecodeLength(&src)
+		_, val, e := axdr.DecodeLength(&src)
+		if e != nil {
+			err = e
+			return
+		}
+
+		if uint64(len(src)) < val {
+			err = ErrWrongLength(len(src), int(val))
+			return
+		}
 		out.Result = src[:val]
 		src = src[val:]
 	}
@@ -283,6 +314,11 @@ func (dt DataBlockSA) Encode() (out []byte, err error) {
 func DecodeDataBlockSA(ori *[]byte) (out DataBlockSA, err error) {
 	src := *ori
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
+
 	if src[0] == 0x0 {
 		out.LastBlock = false
 	} else {
@@ -291,9 +327,21 @@ func DecodeDataBlockSA(ori *[]byte) (out DataBlockSA, err error) {
 	src = src[1:]
 
 	_, out.BlockNumber, err = axdr.DecodeDoubleLongUnsigned(&src)
+	if err != nil {
+		return
+	}
 
 	// not sure if length is limited only 1 byte, or does it follow KLV as in axdr
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
+
 	val := src[0]
+	if len(src) < int(val)+1 {
+		err = ErrWrongLength(len(src), int(val)+1)
+		return
+	}
 	out.Raw = src[1 : val+1]
 	src = src[val+1:]
 
@@ -335,6 +383,11 @@ func (dt ActResponse) Encode() (out []byte, err error) {
 func DecodeActResponse(ori *[]byte) (out ActResponse, err error) {
 	src := *ori
 
+	if len(src) < 2 {
+		err = ErrWrongLength(len(src), 2)
+		return
+	}
+
 	out.Result, err = GetActionTag(src[0])
 	if err != nil {
 		return
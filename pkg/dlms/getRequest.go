@@ -40,6 +40,11 @@ func (gr *GetRequest) New(tag getRequestTag) (out CosemPDU, err error) {
 }
 
 func (gr *GetRequest) Decode(src *[]byte) (out CosemPDU, err error) {
+	if len(*src) < 2 {
+		err = ErrWrongLength(len(*src), 2)
+		return
+	}
+
 	if (*src)[0] != TagGetRequest.Value() {
 		err = ErrWrongTag(0, (*src)[0], byte(TagGetRequest))
 		return
@@ -74,6 +79,11 @@ func CreateGetRequestNormal(invokeID uint8, att AttributeDescriptor, acc *Select
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (gr GetRequestNormal) InvokeID() uint8 {
+	return gr.InvokePriority & InvokeIDMask
+}
+
 func (gr GetRequestNormal) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagGetRequest))
@@ -104,6 +114,11 @@ func (gr GetRequestNormal) Encode() (out []byte, err error) {
 func DecodeGetRequestNormal(ori *[]byte) (out GetRequestNormal, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagGetRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagGetRequest))
 		return
@@ -119,6 +134,10 @@ func DecodeGetRequestNormal(ori *[]byte) (out GetRequestNormal, err error) {
 		return
 	}
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
 	haveAccDesc := src[0]
 	src = src[1:]
 	// SelectiveAccessInfo
@@ -151,6 +170,11 @@ func CreateGetRequestNext(invokeID uint8, blockNum uint32) *GetRequestNext {
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (gr GetRequestNext) InvokeID() uint8 {
+	return gr.InvokePriority & InvokeIDMask
+}
+
 func (gr GetRequestNext) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagGetRequest))
@@ -166,6 +190,11 @@ func (gr GetRequestNext) Encode() (out []byte, err error) {
 func DecodeGetRequestNext(ori *[]byte) (out GetRequestNext, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagGetRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagGetRequest))
 		return
@@ -206,6 +235,11 @@ func CreateGetRequestWithList(invokeID uint8, attList []AttributeDescriptorWithS
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (gr GetRequestWithList) InvokeID() uint8 {
+	return gr.InvokePriority & InvokeIDMask
+}
+
 func (gr GetRequestWithList) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagGetRequest))
@@ -228,6 +262,11 @@ func (gr GetRequestWithList) Encode() (out []byte, err error) {
 func DecodeGetRequestWithList(ori *[]byte) (out GetRequestWithList, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagGetRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagGetRequest))
 		return
@@ -37,6 +37,11 @@ func (gr *GetResponse) New(tag getResponseTag) (out CosemPDU, err error) {
 }
 
 func (gr *GetResponse) Decode(src *[]byte) (out CosemPDU, err error) {
+	if len(*src) < 2 {
+		err = ErrWrongLength(len(*src), 2)
+		return
+	}
+
 	if (*src)[0] != TagGetResponse.Value() {
 		err = ErrWrongTag(0, (*src)[0], byte(TagGetResponse))
 		return
@@ -69,6 +74,11 @@ func CreateGetResponseNormal(invokeID uint8, res GetDataResult) *GetResponseNorm
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (gr GetResponseNormal) InvokeID() uint8 {
+	return gr.InvokePriority & InvokeIDMask
+}
+
 func (gr GetResponseNormal) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagGetResponse))
@@ -88,6 +98,11 @@ func (gr GetResponseNormal) Encode() (out []byte, err error) {
 func DecodeGetResponseNormal(ori *[]byte) (out GetResponseNormal, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagGetResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagGetResponse))
 		return
@@ -118,6 +133,11 @@ func CreateGetResponseWithDataBlock(invokeID uint8, res DataBlockG) *GetResponse
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (gr GetResponseWithDataBlock) InvokeID() uint8 {
+	return gr.InvokePriority & InvokeIDMask
+}
+
 func (gr GetResponseWithDataBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagGetResponse))
@@ -137,6 +157,11 @@ func (gr GetResponseWithDataBlock) Encode() (out []byte, err error) {
 func DecodeGetResponseWithDataBlock(ori *[]byte) (out GetResponseWithDataBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagGetResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagGetResponse))
 		return
@@ -172,6 +197,11 @@ func CreateGetResponseWithList(invokeID uint8, resList []GetDataResult) *GetResp
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (gr GetResponseWithList) InvokeID() uint8 {
+	return gr.InvokePriority & InvokeIDMask
+}
+
 func (gr GetResponseWithList) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagGetResponse))
@@ -194,6 +224,11 @@ func (gr GetResponseWithList) Encode() (out []byte, err error) {
 func DecodeGetResponseWithList(ori *[]byte) (out GetResponseWithList, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagGetResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagGetResponse))
 		return
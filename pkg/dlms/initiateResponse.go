@@ -82,6 +82,11 @@ func DecodeInitiateResponse(ori *[]byte) (out InitiateResponse, err error) {
 		src = src[2:]
 	}
 
+	if len(src) < 12 {
+		err = ErrWrongLength(len(src), 12)
+		return
+	}
+
 	if src[0] != DlmsVersion {
 		err = ErrWrongVersion
 		return
@@ -1,9 +1,12 @@
 package dlms
 
 import (
+	"encoding/hex"
 	"fmt"
 	"strconv"
 	"strings"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
 )
 
 // this object doesn't have header/length part, unlike axdr.DlmsData OctetString
@@ -49,6 +52,27 @@ func (o Obis) Bytes() []byte {
 	return o.byteValue[:]
 }
 
+// MarshalAXDR encodes the OBIS code as an octet-string of 6 bytes
+func (o Obis) MarshalAXDR() (*axdr.DlmsData, error) {
+	return axdr.CreateAxdrOctetString(hex.EncodeToString(o.Bytes())), nil
+}
+
+// UnmarshalAXDR decodes the OBIS code from an octet-string of 6 bytes
+func (o *Obis) UnmarshalAXDR(data axdr.DlmsData) error {
+	if data.Tag != axdr.TagOctetString {
+		return fmt.Errorf("obis code must be an octet-string")
+	}
+
+	src, err := data.AsBytes()
+	if err != nil || len(src) != 6 {
+		return fmt.Errorf("invalid obis code %v", data.Value)
+	}
+
+	*o, err = DecodeObis(&src)
+
+	return err
+}
+
 func DecodeObis(src *[]byte) (outVal Obis, err error) {
 	if len(*src) < 6 {
 		err = fmt.Errorf("byte slice length must be at least 6 bytes")
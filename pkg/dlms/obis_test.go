@@ -3,6 +3,9 @@ package dlms
 import (
 	"bytes"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
 )
 
 func TestObis_Encode(t *testing.T) {
@@ -46,3 +49,20 @@ func TestObis_Decode(t *testing.T) {
 		t.Errorf("t1 reminder failed. get: %v, should: [1, 2, 3]", src)
 	}
 }
+
+func TestObis_AXDR(t *testing.T) {
+	data, err := axdr.MarshalData(*CreateObis("1-0:1.8.0.255"))
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrOctetString("0100010800ff"), data)
+
+	var o Obis
+	err = axdr.UnmarshalData(*data, &o)
+	assert.NoError(t, err)
+	assert.Equal(t, *CreateObis("1.0.1.8.0.255"), o)
+
+	err = axdr.UnmarshalData(*axdr.CreateAxdrOctetString("01000108"), &o)
+	assert.Error(t, err)
+
+	err = axdr.UnmarshalData(*axdr.CreateAxdrUnsigned(1), &o)
+	assert.Error(t, err)
+}
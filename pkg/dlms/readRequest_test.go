@@ -0,0 +1,29 @@
+package dlms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRequest(t *testing.T) {
+	a := *CreateReadRequest([]VariableAccessSpecification{*CreateVariableNameAccess(0xFA00), *CreateVariableNameAccess(0xFA08)})
+	out, err := a.Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, "050202FA0002FA08", encodeHexString(out))
+
+	src := decodeHexString("050202FA0002FA08")
+	b, err := DecodeReadRequest(&src)
+	assert.NoError(t, err)
+	assert.Len(t, b.Variables, 2)
+	assert.Equal(t, uint16(0xFA08), b.Variables[1].VariableName)
+	assert.Empty(t, src)
+
+	src = decodeHexString("060102FA00")
+	_, err = DecodeReadRequest(&src)
+	assert.Error(t, err)
+
+	src = decodeHexString("050202FA00")
+	_, err = DecodeReadRequest(&src)
+	assert.Error(t, err)
+}
@@ -0,0 +1,197 @@
+package dlms
+
+import (
+	"bytes"
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+type readResultTag uint8
+
+const (
+	TagReadResultData            readResultTag = 0x0
+	TagReadResultDataAccessError readResultTag = 0x1
+	TagReadResultDataBlockResult readResultTag = 0x2
+	TagReadResultBlockNumber     readResultTag = 0x3
+)
+
+// Value will return primitive value of the target.
+// This is used for comparing with non custom typed object
+func (s readResultTag) Value() uint8 {
+	return uint8(s)
+}
+
+// DataBlockResult is the data block returned in the READ-response during block transfer
+type DataBlockResult struct {
+	LastBlock   bool
+	BlockNumber uint16
+	RawData     []byte
+}
+
+// ReadResult is each of the results of the READ-response. Only the field related to
+// the Tag is meaningful.
+type ReadResult struct {
+	Tag         readResultTag
+	Data        axdr.DlmsData
+	AccessError AccessResultTag
+	DataBlock   DataBlockResult
+	BlockNumber uint16
+}
+
+func CreateReadResultAsData(data axdr.DlmsData) *ReadResult {
+	return &ReadResult{Tag: TagReadResultData, Data: data}
+}
+
+func CreateReadResultAsAccessError(access AccessResultTag) *ReadResult {
+	return &ReadResult{Tag: TagReadResultDataAccessError, AccessError: access}
+}
+
+func CreateReadResultAsDataBlock(lastBlock bool, blockNumber uint16, rawData []byte) *ReadResult {
+	return &ReadResult{Tag: TagReadResultDataBlockResult, DataBlock: DataBlockResult{LastBlock: lastBlock, BlockNumber: blockNumber, RawData: rawData}}
+}
+
+func CreateReadResultAsBlockNumber(blockNumber uint16) *ReadResult {
+	return &ReadResult{Tag: TagReadResultBlockNumber, BlockNumber: blockNumber}
+}
+
+func (rr ReadResult) Encode() (out []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte(rr.Tag.Value())
+
+	switch rr.Tag {
+	case TagReadResultData:
+		val, e := rr.Data.Encode()
+		if e != nil {
+			err = e
+			return
+		}
+		buf.Write(val)
+	case TagReadResultDataAccessError:
+		buf.WriteByte(byte(rr.AccessError))
+	case TagReadResultDataBlockResult:
+		buf.WriteByte(encodeBool(rr.DataBlock.LastBlock))
+		buf.Write(encodeUint16(rr.DataBlock.BlockNumber))
+		raw, e := encodeOctetString(rr.DataBlock.RawData)
+		if e != nil {
+			err = e
+			return
+		}
+		buf.Write(raw)
+	case TagReadResultBlockNumber:
+		buf.Write(encodeUint16(rr.BlockNumber))
+	default:
+		err = fmt.Errorf("read result tag not recognized (%v)", rr.Tag)
+		return
+	}
+
+	out = buf.Bytes()
+	return
+}
+
+func DecodeReadResult(ori *[]byte) (out ReadResult, err error) {
+	src := *ori
+
+	if len(src) < 2 {
+		err = ErrWrongLength(len(src), 2)
+		return
+	}
+
+	out.Tag = readResultTag(src[0])
+	src = src[1:]
+
+	switch out.Tag {
+	case TagReadResultData:
+		decoder := axdr.NewDataDecoder(&src)
+		out.Data, err = decoder.Decode(&src)
+	case TagReadResultDataAccessError:
+		out.AccessError, err = GetAccessTag(src[0])
+		src = src[1:]
+	case TagReadResultDataBlockResult:
+		_, out.DataBlock.LastBlock, err = axdr.DecodeBoolean(&src)
+		if err != nil {
+			return
+		}
+		_, out.DataBlock.BlockNumber, err = axdr.DecodeLongUnsigned(&src)
+		if err != nil {
+			return
+		}
+		out.DataBlock.RawData, err = decodeOctetString(&src)
+	case TagReadResultBlockNumber:
+		_, out.BlockNumber, err = axdr.DecodeLongUnsigned(&src)
+	default:
+		err = fmt.Errorf("byte tag not recognized (%v)", out.Tag)
+	}
+
+	if err != nil {
+		return
+	}
+
+	(*ori) = (*ori)[len((*ori))-len(src):]
+	return
+}
+
+// ReadResponse implement CosemPDU. It contains a result for each variable of the ReadRequest
+type ReadResponse struct {
+	Results []ReadResult
+}
+
+func CreateReadResponse(results []ReadResult) *ReadResponse {
+	return &ReadResponse{Results: results}
+}
+
+func (rr ReadResponse) Encode() (out []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte(byte(TagReadResponse))
+
+	length, err := axdr.EncodeLength(len(rr.Results))
+	if err != nil {
+		return
+	}
+	buf.Write(length)
+
+	for _, res := range rr.Results {
+		val, e := res.Encode()
+		if e != nil {
+			err = e
+			return
+		}
+		buf.Write(val)
+	}
+
+	out = buf.Bytes()
+	return
+}
+
+func DecodeReadResponse(ori *[]byte) (out ReadResponse, err error) {
+	src := *ori
+
+	if len(src) < 2 {
+		err = ErrWrongLength(len(src), 2)
+		return
+	}
+
+	if src[0] != TagReadResponse.Value() {
+		err = ErrWrongTag(0, src[0], byte(TagReadResponse))
+		return
+	}
+	src = src[1:]
+
+	_, count, err := axdr.DecodeLength(&src)
+	if err != nil {
+		return
+	}
+
+	out.Results = make([]ReadResult, 0)
+	for i := uint64(0); i < count; i++ {
+		res, e := DecodeReadResult(&src)
+		if e != nil {
+			err = e
+			return
+		}
+		out.Results = append(out.Results, res)
+	}
+
+	(*ori) = (*ori)[len((*ori))-len(src):]
+	return
+}
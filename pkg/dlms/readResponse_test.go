@@ -0,0 +1,42 @@
+package dlms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+func TestReadResponse(t *testing.T) {
+	a := *CreateReadResponse([]ReadResult{
+		*CreateReadResultAsData(*axdr.CreateAxdrLongUnsigned(60)),
+		*CreateReadResultAsAccessError(TagAccReadWriteDenied),
+		*CreateReadResultAsDataBlock(false, 1, []byte{0x09, 0x06}),
+		*CreateReadResultAsBlockNumber(2),
+	})
+	out, err := a.Encode()
+	assert.NoError(t, err)
+	expected := "0C04" + "0012003C" + "0103" + "0200000102" + "0906" + "030002"
+	assert.Equal(t, expected, encodeHexString(out))
+
+	src := decodeHexString(expected)
+	b, err := DecodeReadResponse(&src)
+	assert.NoError(t, err)
+	assert.Empty(t, src)
+	assert.Len(t, b.Results, 4)
+	assert.Equal(t, TagReadResultData, b.Results[0].Tag)
+	assert.Equal(t, uint16(60), b.Results[0].Data.Value)
+	assert.Equal(t, TagAccReadWriteDenied, b.Results[1].AccessError)
+	assert.False(t, b.Results[2].DataBlock.LastBlock)
+	assert.Equal(t, uint16(1), b.Results[2].DataBlock.BlockNumber)
+	assert.Equal(t, []byte{0x09, 0x06}, b.Results[2].DataBlock.RawData)
+	assert.Equal(t, uint16(2), b.Results[3].BlockNumber)
+}
+
+func TestReadResponseInvalid(t *testing.T) {
+	for _, s := range []string{"0C", "0D0100", "0C0104", "0C01020100010509"} {
+		src := decodeHexString(s)
+		_, err := DecodeReadResponse(&src)
+		assert.Error(t, err, s)
+	}
+}
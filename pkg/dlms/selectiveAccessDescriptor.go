@@ -2,6 +2,8 @@ package dlms
 
 import (
 	"bytes"
+	"encoding/hex"
+	"fmt"
 	"time"
 
 	"gitlab.com/circutor-library/gosem/pkg/axdr"
@@ -25,42 +27,130 @@ type SelectiveAccessDescriptor struct {
 	AccessParameter axdr.DlmsData
 }
 
-func CreateSelectiveAccessByRangeDescriptor(from time.Time, to time.Time, values []AttributeDescriptor) *SelectiveAccessDescriptor {
-	restrictingObject := createAttributeDescriptorWithIndex(8, "0.0.1.0.0.255", 2, 0)
+// RangeDescriptor is the parameter of the range selective access of the buffer of
+// a Profile Generic: the entries whose restricting object value is between From
+// and To, reduced to the selected Columns (all of them if empty).
+type RangeDescriptor struct {
+	RestrictingObject    AttributeDescriptor
+	RestrictingDataIndex uint16
+	// From and To are a time.Time or an axdr.CosemDateTime (encoded as date-time
+	// octet-string), an uint32 or any axdr.DlmsData
+	From    interface{}
+	To      interface{}
+	Columns []SelectedValue
+}
+
+// SelectedValue is a column selected in a range selective access
+type SelectedValue struct {
+	Attribute AttributeDescriptor
+	DataIndex uint16
+}
 
-	fromValue := axdr.CreateAxdrOctetString(from)
-	toValue := axdr.CreateAxdrOctetString(to)
+// ClockRestrictingObject returns the attribute usually restricting profiles: the time of the clock
+func ClockRestrictingObject() AttributeDescriptor {
+	return *CreateAttributeDescriptor(8, "0.0.1.0.0.255", 2)
+}
 
-	selected := make([]*axdr.DlmsData, 0, len(values))
+func CreateSelectiveAccessByRangeDescriptor(from time.Time, to time.Time, values []AttributeDescriptor) *SelectiveAccessDescriptor {
+	rd := RangeDescriptor{RestrictingObject: ClockRestrictingObject(), From: from, To: to}
 	for _, v := range values {
-		selected = append(selected, createAttributeDescriptorWithIndex(v.ClassID, v.InstanceID.String(), v.AttributeID, 0))
+		rd.Columns = append(rd.Columns, SelectedValue{Attribute: v})
 	}
-	selectedValues := axdr.CreateAxdrArray(selected)
 
-	rangeDescriptor := *axdr.CreateAxdrStructure([]*axdr.DlmsData{restrictingObject, fromValue, toValue, selectedValues})
+	sad, _ := CreateSelectiveAccessByRange(rd)
 
-	return &SelectiveAccessDescriptor{AccessSelector: AccessSelectorRange, AccessParameter: rangeDescriptor}
+	return sad
 }
 
-func CreateSelectiveAccessByEntryDescriptor(from uint32, to uint32) *SelectiveAccessDescriptor {
-	fromEntry := *axdr.CreateAxdrDoubleLongUnsigned(from)
-	toEntry := *axdr.CreateAxdrDoubleLongUnsigned(to)
+func CreateSelectiveAccessByRange(rd RangeDescriptor) (*SelectiveAccessDescriptor, error) {
+	fromValue, err := rangeValue(rd.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from value: %w", err)
+	}
+
+	toValue, err := rangeValue(rd.To)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to value: %w", err)
+	}
+
+	ro := rd.RestrictingObject
+
+	b := axdr.NewBuilder().Structure()
+	addAttributeDescriptorWithIndex(b, ro.ClassID, ro.InstanceID.String(), ro.AttributeID, rd.RestrictingDataIndex)
+	b.Add(fromValue).Add(toValue).Array()
+	for _, v := range rd.Columns {
+		addAttributeDescriptorWithIndex(b, v.Attribute.ClassID, v.Attribute.InstanceID.String(), v.Attribute.AttributeID, v.DataIndex)
+	}
+
+	rangeDescriptor, err := b.End().End().Build()
+	if err != nil {
+		return nil, fmt.Errorf("invalid range descriptor: %w", err)
+	}
+
+	return &SelectiveAccessDescriptor{AccessSelector: AccessSelectorRange, AccessParameter: *rangeDescriptor}, nil
+}
 
-	fromSelectedValue := *axdr.CreateAxdrLongUnsigned(0)
-	toSelectedValue := *axdr.CreateAxdrLongUnsigned(0)
+func rangeValue(value interface{}) (*axdr.DlmsData, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return axdr.CreateAxdrOctetString(v), nil
+	case axdr.CosemDateTime:
+		return axdr.CreateAxdrOctetString(hex.EncodeToString(v.Encode())), nil
+	case uint32:
+		return axdr.CreateAxdrDoubleLongUnsigned(v), nil
+	case axdr.DlmsData:
+		return &v, nil
+	case *axdr.DlmsData:
+		if v == nil {
+			return nil, fmt.Errorf("nil data")
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+// EntryDescriptor is the parameter of the entry selective access of the buffer of
+// a Profile Generic. Entries and columns are numbered from 1, and a 0 in ToEntry or
+// ToColumn means up to the last one.
+type EntryDescriptor struct {
+	FromEntry  uint32
+	ToEntry    uint32
+	FromColumn uint16
+	ToColumn   uint16
+}
 
-	entryDescriptor := *axdr.CreateAxdrStructure([]*axdr.DlmsData{&fromEntry, &toEntry, &fromSelectedValue, &toSelectedValue})
+func CreateSelectiveAccessByEntryDescriptor(from uint32, to uint32) *SelectiveAccessDescriptor {
+	sad, _ := CreateSelectiveAccessByEntry(EntryDescriptor{FromEntry: from, ToEntry: to})
 
-	return &SelectiveAccessDescriptor{AccessSelector: AccessSelectorEntry, AccessParameter: entryDescriptor}
+	return sad
 }
 
-func createAttributeDescriptorWithIndex(class uint16, obis string, attribute int8, index uint16) *axdr.DlmsData {
-	classID := *axdr.CreateAxdrLongUnsigned(class)
-	obisCode := *axdr.CreateAxdrOctetString(obis)
-	attributeID := *axdr.CreateAxdrInteger(attribute)
-	dataIdx := *axdr.CreateAxdrLongUnsigned(index)
+func CreateSelectiveAccessByEntry(ed EntryDescriptor) (*SelectiveAccessDescriptor, error) {
+	entryDescriptor, err := axdr.NewBuilder().
+		Structure().
+		DoubleLongUnsigned(ed.FromEntry).
+		DoubleLongUnsigned(ed.ToEntry).
+		LongUnsigned(ed.FromColumn).
+		LongUnsigned(ed.ToColumn).
+		End().
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("invalid entry descriptor: %w", err)
+	}
+
+	return &SelectiveAccessDescriptor{AccessSelector: AccessSelectorEntry, AccessParameter: *entryDescriptor}, nil
+}
 
-	return axdr.CreateAxdrStructure([]*axdr.DlmsData{&classID, &obisCode, &attributeID, &dataIdx})
+// addAttributeDescriptorWithIndex adds to b the structure selecting an attribute
+// (or an element of it, if index is not 0) of an object.
+func addAttributeDescriptorWithIndex(b *axdr.Builder, class uint16, obis string, attribute int8, index uint16) {
+	b.Structure().
+		LongUnsigned(class).
+		OctetString(obis).
+		Integer(attribute).
+		LongUnsigned(index).
+		End()
 }
 
 func (s SelectiveAccessDescriptor) Encode() (out []byte, err error) {
@@ -80,6 +170,11 @@ func (s SelectiveAccessDescriptor) Encode() (out []byte, err error) {
 func DecodeSelectiveAccessDescriptor(ori *[]byte) (out SelectiveAccessDescriptor, err error) {
 	src := *ori
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
+
 	if src[0] == AccessSelectorRange.Value() {
 		out.AccessSelector = AccessSelectorRange
 	} else {
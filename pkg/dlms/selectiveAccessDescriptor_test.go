@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
 )
 
 func TestSelectiveAccessDescriptor_Encode(t *testing.T) {
@@ -36,6 +37,60 @@ func TestSelectiveAccessDescriptor_Encode(t *testing.T) {
 	assert.Equal(t, expected, out)
 }
 
+func TestCreateSelectiveAccessByEntry(t *testing.T) {
+	a, err := CreateSelectiveAccessByEntry(EntryDescriptor{FromEntry: 1, ToEntry: 100, FromColumn: 2, ToColumn: 3})
+	assert.NoError(t, err)
+
+	out, err := a.Encode()
+	assert.NoError(t, err)
+
+	expected := decodeHexString("02020406000000010600000064120002120003")
+	assert.Equal(t, expected, out)
+}
+
+func TestCreateSelectiveAccessByRange(t *testing.T) {
+	// Restricted by a sequence number register, selecting the second element of a column
+	rd := RangeDescriptor{
+		RestrictingObject: *CreateAttributeDescriptor(1, "0-0:96.15.0.255", 2),
+		From:              uint32(10),
+		To:                uint32(20),
+		Columns: []SelectedValue{
+			{Attribute: *CreateAttributeDescriptor(1, "0-0:96.15.0.255", 2)},
+			{Attribute: *CreateAttributeDescriptor(4, "1-0:1.6.0.255", 5), DataIndex: 2},
+		},
+	}
+
+	a, err := CreateSelectiveAccessByRange(rd)
+	assert.NoError(t, err)
+
+	out, err := a.Encode()
+	assert.NoError(t, err)
+
+	expected := decodeHexString("010204020412000109060000600F00FF0F02120000060000000A0600000014" +
+		"0102020412000109060000600F00FF0F02120000020412000409060100010600FF0F05120002")
+	assert.Equal(t, expected, out)
+
+	// Any data can be used as limits
+	rd = RangeDescriptor{RestrictingObject: ClockRestrictingObject(), RestrictingDataIndex: 1, From: *axdr.CreateAxdrLongUnsigned(1), To: axdr.CreateAxdrLongUnsigned(2)}
+
+	a, err = CreateSelectiveAccessByRange(rd)
+	assert.NoError(t, err)
+
+	out, err = a.Encode()
+	assert.NoError(t, err)
+
+	expected = decodeHexString("010204020412000809060000010000FF0F02120001120001120002" + "0100")
+	assert.Equal(t, expected, out)
+
+	rd.From = "yesterday"
+	_, err = CreateSelectiveAccessByRange(rd)
+	assert.Error(t, err)
+
+	rd.From = (*axdr.DlmsData)(nil)
+	_, err = CreateSelectiveAccessByRange(rd)
+	assert.Error(t, err)
+}
+
 func TestSelectiveAccessDescriptor_Decode(t *testing.T) {
 	// ------------------------ AccessSelectorEntry
 	src := decodeHexString("02020406000000000600000005120000120000")
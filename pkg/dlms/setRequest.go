@@ -45,6 +45,11 @@ func (gr *SetRequest) New(tag setRequestTag) (out CosemPDU, err error) {
 }
 
 func (gr *SetRequest) Decode(src *[]byte) (out CosemPDU, err error) {
+	if len(*src) < 2 {
+		err = ErrWrongLength(len(*src), 2)
+		return
+	}
+
 	if (*src)[0] != TagSetRequest.Value() {
 		err = ErrWrongTag(0, (*src)[0], byte(TagSetRequest))
 		return
@@ -85,6 +90,11 @@ func CreateSetRequestNormal(invokeID uint8, att AttributeDescriptor, acc *Select
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetRequestNormal) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetRequestNormal) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagSetRequest))
@@ -107,20 +117,23 @@ func (sr SetRequestNormal) Encode() (out []byte, err error) {
 		}
 		buf.Write(selInfo)
 	}
-	val, eVal := sr.Value.Encode()
-	if eVal != nil {
-		err = eVal
-		return
+
+	out, err = sr.Value.AppendEncode(buf.Bytes())
+	if err != nil {
+		return nil, err
 	}
-	buf.Write(val)
 
-	out = buf.Bytes()
 	return
 }
 
 func DecodeSetRequestNormal(ori *[]byte) (out SetRequestNormal, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagSetRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetRequest))
 		return
@@ -136,6 +149,10 @@ func DecodeSetRequestNormal(ori *[]byte) (out SetRequestNormal, err error) {
 		return
 	}
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
 	haveAccDesc := src[0]
 	src = src[1:]
 	// SelectiveAccessInfo
@@ -175,6 +192,11 @@ func CreateSetRequestWithFirstDataBlock(invokeID uint8, att AttributeDescriptor,
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetRequestWithFirstDataBlock) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetRequestWithFirstDataBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagSetRequest))
@@ -212,6 +234,11 @@ func (sr SetRequestWithFirstDataBlock) Encode() (out []byte, err error) {
 func DecodeSetRequestWithFirstDataBlock(ori *[]byte) (out SetRequestWithFirstDataBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagSetRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetRequest))
 		return
@@ -227,6 +254,10 @@ func DecodeSetRequestWithFirstDataBlock(ori *[]byte) (out SetRequestWithFirstDat
 		return
 	}
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
 	haveAccDesc := src[0]
 	src = src[1:]
 
@@ -261,6 +292,11 @@ func CreateSetRequestWithDataBlock(invokeID uint8, dt DataBlockSA) *SetRequestWi
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetRequestWithDataBlock) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetRequestWithDataBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagSetRequest))
@@ -280,6 +316,11 @@ func (sr SetRequestWithDataBlock) Encode() (out []byte, err error) {
 func DecodeSetRequestWithDataBlock(ori *[]byte) (out SetRequestWithDataBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagSetRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetRequest))
 		return
@@ -322,6 +363,11 @@ func CreateSetRequestWithList(invokeID uint8, attList []AttributeDescriptorWithS
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetRequestWithList) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetRequestWithList) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagSetRequest))
@@ -353,6 +399,11 @@ func (sr SetRequestWithList) Encode() (out []byte, err error) {
 func DecodeSetRequestWithList(ori *[]byte) (out SetRequestWithList, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagSetRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetRequest))
 		return
@@ -374,6 +425,10 @@ func DecodeSetRequestWithList(ori *[]byte) (out SetRequestWithList, err error) {
 		out.AttributeInfoList = append(out.AttributeInfoList, v)
 	}
 
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
 	out.ValueCount = src[0]
 	src = src[1:]
 	for i := 0; i < int(out.ValueCount); i++ {
@@ -410,6 +465,11 @@ func CreateSetRequestWithListAndFirstDataBlock(invokeID uint8, attList []Attribu
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetRequestWithListAndFirstDataBlock) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetRequestWithListAndFirstDataBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(byte(TagSetRequest))
@@ -438,6 +498,11 @@ func (sr SetRequestWithListAndFirstDataBlock) Encode() (out []byte, err error) {
 func DecodeSetRequestWithListAndFirstDataBlock(ori *[]byte) (out SetRequestWithListAndFirstDataBlock, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagSetRequest.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetRequest))
 		return
@@ -45,6 +45,11 @@ func (gr *SetResponse) New(tag setResponseTag) (out CosemPDU, err error) {
 }
 
 func (gr *SetResponse) Decode(src *[]byte) (out CosemPDU, err error) {
+	if len(*src) < 2 {
+		err = ErrWrongLength(len(*src), 2)
+		return
+	}
+
 	if (*src)[0] != TagSetResponse.Value() {
 		err = ErrWrongTag(0, (*src)[0], byte(TagSetResponse))
 		return
@@ -81,6 +86,11 @@ func CreateSetResponseNormal(invokeID uint8, result AccessResultTag) *SetRespons
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetResponseNormal) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetResponseNormal) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(TagSetResponse.Value())
@@ -95,6 +105,11 @@ func (sr SetResponseNormal) Encode() (out []byte, err error) {
 func DecodeSetResponseNormal(ori *[]byte) (out SetResponseNormal, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagSetResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetResponse))
 		return
@@ -127,6 +142,11 @@ func CreateSetResponseDataBlock(invokeID uint8, blockNum uint32) *SetResponseDat
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetResponseDataBlock) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetResponseDataBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(TagSetResponse.Value())
@@ -142,6 +162,11 @@ func (sr SetResponseDataBlock) Encode() (out []byte, err error) {
 func DecodeSetResponseDataBlock(ori *[]byte) (out SetResponseDataBlock, err error) {
 	src := *ori
 
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
 	if src[0] != TagSetResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetResponse))
 		return
@@ -174,6 +199,11 @@ func CreateSetResponseLastDataBlock(invokeID uint8, result AccessResultTag, bloc
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetResponseLastDataBlock) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetResponseLastDataBlock) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(TagSetResponse.Value())
@@ -190,6 +220,11 @@ func (sr SetResponseLastDataBlock) Encode() (out []byte, err error) {
 func DecodeSetResponseLastDataBlock(ori *[]byte) (out SetResponseLastDataBlock, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagSetResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetResponse))
 		return
@@ -231,6 +266,11 @@ func CreateSetResponseLastDataBlockWithList(invokeID uint8, resList []AccessResu
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetResponseLastDataBlockWithList) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetResponseLastDataBlockWithList) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(TagSetResponse.Value())
@@ -250,6 +290,11 @@ func (sr SetResponseLastDataBlockWithList) Encode() (out []byte, err error) {
 func DecodeSetResponseLastDataBlockWithList(ori *[]byte) (out SetResponseLastDataBlockWithList, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagSetResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetResponse))
 		return
@@ -263,6 +308,10 @@ func DecodeSetResponseLastDataBlockWithList(ori *[]byte) (out SetResponseLastDat
 	out.ResultCount = src[3]
 	src = src[4:]
 	for i := 0; i < int(out.ResultCount); i++ {
+		if len(src) < 1 {
+			err = ErrWrongLength(len(src), 1)
+			return
+		}
 		v, e := GetAccessTag(src[0])
 		if e != nil {
 			err = e
@@ -296,6 +345,11 @@ func CreateSetResponseWithList(invokeID uint8, resList []AccessResultTag) *SetRe
 	}
 }
 
+// InvokeID returns the invoke-id of the Invoke-Id-And-Priority field.
+func (sr SetResponseWithList) InvokeID() uint8 {
+	return sr.InvokePriority & InvokeIDMask
+}
+
 func (sr SetResponseWithList) Encode() (out []byte, err error) {
 	var buf bytes.Buffer
 	buf.WriteByte(TagSetResponse.Value())
@@ -313,6 +367,11 @@ func (sr SetResponseWithList) Encode() (out []byte, err error) {
 func DecodeSetResponseWithList(ori *[]byte) (out SetResponseWithList, err error) {
 	src := *ori
 
+	if len(src) < 4 {
+		err = ErrWrongLength(len(src), 4)
+		return
+	}
+
 	if src[0] != TagSetResponse.Value() {
 		err = ErrWrongTag(0, src[0], byte(TagSetResponse))
 		return
@@ -326,6 +385,10 @@ func DecodeSetResponseWithList(ori *[]byte) (out SetResponseWithList, err error)
 	out.ResultCount = src[3]
 	src = src[4:]
 	for i := 0; i < int(out.ResultCount); i++ {
+		if len(src) < 1 {
+			err = ErrWrongLength(len(src), 1)
+			return
+		}
 		v, e := GetAccessTag(src[0])
 		if e != nil {
 			err = e
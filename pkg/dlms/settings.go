@@ -3,6 +3,8 @@ package dlms
 import (
 	"crypto/rand"
 	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
 )
 
 type Authentication byte
@@ -36,6 +38,37 @@ const (
 	SecurityKeySetBroadcast Security = 0x40 // Key set broadcast security is used.
 )
 
+type Referencing byte
+
+const (
+	ReferencingLogicalName Referencing = iota // Objects are addressed by class ID, OBIS code and attribute (LN).
+	ReferencingShortName                      // Objects are addressed by their base name (SN).
+)
+
+// Priority is the priority bit of the Invoke-Id-And-Priority field. The zero
+// value is high priority, as used by default.
+type Priority byte
+
+const (
+	PriorityHigh   Priority = iota // Requests are served with high priority.
+	PriorityNormal                 // Requests are served in order of arrival.
+)
+
+// ServiceClass is the service-class bit of the Invoke-Id-And-Priority field. The
+// zero value is confirmed, as used by default.
+type ServiceClass byte
+
+const (
+	ServiceClassConfirmed   ServiceClass = iota // The server answers each request.
+	ServiceClassUnconfirmed                     // The server does not answer the requests.
+)
+
+const (
+	InvokeIDMask    uint8 = 0x0F
+	serviceClassBit uint8 = 0x40
+	priorityBit     uint8 = 0x80
+)
+
 type Ciphering struct {
 	Level               SecurityLevel
 	Security            Security
@@ -58,6 +91,15 @@ type Settings struct {
 	MaxPduSendSize   int
 	ConformanceBlock int
 	UseBroadcast     bool
+	Referencing      Referencing
+	Priority         Priority
+	ServiceClass     ServiceClass
+	// MaxPendingRequests is the number of confirmed LN requests that can be in flight
+	// at the same time over the association. Values lower than 2 keep requests serial.
+	MaxPendingRequests int
+	// TimeZone is the convention of the meter for the deviation of date-times. If
+	// nil, axdr.TimeZoneDeviation is used.
+	TimeZone *axdr.TimeZone
 }
 
 func NewSettingsWithoutAuthentication() (Settings, error) {
@@ -98,6 +140,31 @@ func NewSettingsWithLowAuthenticationAndCiphering(password []byte, cipher Cipher
 	return s, nil
 }
 
+// EnableShortNameReferencing switches the settings to a short name association,
+// replacing the conformance block with the services available in SN referencing.
+func (s *Settings) EnableShortNameReferencing() {
+	s.Referencing = ReferencingShortName
+	s.ConformanceBlock = ConformanceBlockBlockTransferWithGetOrRead | ConformanceBlockBlockTransferWithSetOrWrite |
+		ConformanceBlockRead | ConformanceBlockWrite | ConformanceBlockUnconfirmedWrite | ConformanceBlockMultipleReferences |
+		ConformanceBlockInformationReport | ConformanceBlockParametrizedAccess
+}
+
+// InvokeIDAndPriority builds the Invoke-Id-And-Priority field of a request with
+// the given invoke-id and the priority and service class of the settings.
+func (s *Settings) InvokeIDAndPriority(invokeID uint8) uint8 {
+	out := invokeID & InvokeIDMask
+
+	if s.Priority == PriorityHigh {
+		out |= priorityBit
+	}
+
+	if s.ServiceClass == ServiceClassConfirmed {
+		out |= serviceClassBit
+	}
+
+	return out
+}
+
 func NewCiphering(level SecurityLevel, security Security, systemTitle []byte, unicastKey []byte, unicastKeyIC uint32, authenticationKey []byte) (Ciphering, error) {
 	if len(systemTitle) != 8 {
 		return Ciphering{}, fmt.Errorf("system title must be 8 bytes long")
@@ -133,6 +200,21 @@ func NewCiphering(level SecurityLevel, security Security, systemTitle []byte, un
 	return c, nil
 }
 
+// RenewDedicatedKey generates a new dedicated key for a new association and
+// restarts its invocation counters.
+func (c *Ciphering) RenewDedicatedKey() error {
+	dk, err := generateKey()
+	if err != nil {
+		return fmt.Errorf("could not generate dedicated key: %w", err)
+	}
+
+	c.DedicatedKey = dk
+	c.DedicatedKeyIC = 1
+	c.DedicatedExpectedIC = 0
+
+	return nil
+}
+
 func generateKey() ([]byte, error) {
 	dk := make([]byte, 16)
 	_, err := rand.Read(dk)
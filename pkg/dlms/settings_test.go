@@ -0,0 +1,29 @@
+package dlms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettingsInvokeIDAndPriority(t *testing.T) {
+	settings, _ := NewSettingsWithoutAuthentication()
+	assert.Equal(t, uint8(0xC1), settings.InvokeIDAndPriority(1))
+	assert.Equal(t, uint8(0xCF), settings.InvokeIDAndPriority(0x1F))
+
+	settings.Priority = PriorityNormal
+	assert.Equal(t, uint8(0x42), settings.InvokeIDAndPriority(2))
+
+	settings.ServiceClass = ServiceClassUnconfirmed
+	assert.Equal(t, uint8(0x03), settings.InvokeIDAndPriority(3))
+}
+
+func TestCipheringRenewDedicatedKey(t *testing.T) {
+	ciphering := Ciphering{DedicatedKey: make([]byte, 16), DedicatedKeyIC: 20, DedicatedExpectedIC: 30}
+
+	assert.NoError(t, ciphering.RenewDedicatedKey())
+	assert.Len(t, ciphering.DedicatedKey, 16)
+	assert.NotEqual(t, make([]byte, 16), ciphering.DedicatedKey)
+	assert.Equal(t, uint32(1), ciphering.DedicatedKeyIC)
+	assert.Equal(t, uint32(0), ciphering.DedicatedExpectedIC)
+}
@@ -1,6 +1,9 @@
 package dlms
 
-import "log"
+import (
+	"context"
+	"log"
+)
 
 type DataChannel chan []byte
 
@@ -22,3 +25,9 @@ type Transport interface {
 type TransportWithBroadcast interface {
 	SendBroadcast(src []byte) error
 }
+
+// TransportWithContext are optional methods for transport layers whose connection
+// establishment can be canceled or bounded by a deadline.
+type TransportWithContext interface {
+	ConnectContext(ctx context.Context) error
+}
@@ -0,0 +1,249 @@
+package dlms
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+// Unit is the physical unit of a value (enumeration of the scaler_unit
+// attribute), as listed in the unit table of the Blue Book.
+type Unit uint8
+
+const (
+	UnitYear                           Unit = 1
+	UnitMonth                          Unit = 2
+	UnitWeek                           Unit = 3
+	UnitDay                            Unit = 4
+	UnitHour                           Unit = 5
+	UnitMinute                         Unit = 6
+	UnitSecond                         Unit = 7
+	UnitDegree                         Unit = 8
+	UnitDegreeCelsius                  Unit = 9
+	UnitCurrency                       Unit = 10
+	UnitMetre                          Unit = 11
+	UnitMetrePerSecond                 Unit = 12
+	UnitCubicMetre                     Unit = 13
+	UnitCorrectedCubicMetre            Unit = 14
+	UnitCubicMetrePerHour              Unit = 15
+	UnitCorrectedCubicMetrePerHour     Unit = 16
+	UnitCubicMetrePerDay               Unit = 17
+	UnitCorrectedCubicMetrePerDay      Unit = 18
+	UnitLitre                          Unit = 19
+	UnitKilogram                       Unit = 20
+	UnitNewton                         Unit = 21
+	UnitNewtonMetre                    Unit = 22
+	UnitPascal                         Unit = 23
+	UnitBar                            Unit = 24
+	UnitJoule                          Unit = 25
+	UnitJoulePerHour                   Unit = 26
+	UnitWatt                           Unit = 27
+	UnitVoltAmpere                     Unit = 28
+	UnitVar                            Unit = 29
+	UnitWattHour                       Unit = 30
+	UnitVoltAmpereHour                 Unit = 31
+	UnitVarHour                        Unit = 32
+	UnitAmpere                         Unit = 33
+	UnitCoulomb                        Unit = 34
+	UnitVolt                           Unit = 35
+	UnitVoltPerMetre                   Unit = 36
+	UnitFarad                          Unit = 37
+	UnitOhm                            Unit = 38
+	UnitResistivity                    Unit = 39
+	UnitWeber                          Unit = 40
+	UnitTesla                          Unit = 41
+	UnitAmperePerMetre                 Unit = 42
+	UnitHenry                          Unit = 43
+	UnitHertz                          Unit = 44
+	UnitActiveEnergyMeterConstant      Unit = 45
+	UnitReactiveEnergyMeterConstant    Unit = 46
+	UnitApparentEnergyMeterConstant    Unit = 47
+	UnitVoltSquaredHour                Unit = 48
+	UnitAmpereSquaredHour              Unit = 49
+	UnitKilogramPerSecond              Unit = 50
+	UnitSiemens                        Unit = 51
+	UnitKelvin                         Unit = 52
+	UnitVoltSquaredHourMeterConstant   Unit = 53
+	UnitAmpereSquaredHourMeterConstant Unit = 54
+	UnitVolumeMeterConstant            Unit = 55
+	UnitPercentage                     Unit = 56
+	UnitAmpereHour                     Unit = 57
+	UnitEnergyPerVolume                Unit = 60
+	UnitCalorificValue                 Unit = 61
+	UnitMolePercent                    Unit = 62
+	UnitMassDensity                    Unit = 63
+	UnitDynamicViscosity               Unit = 64
+	UnitSpecificEnergy                 Unit = 65
+	UnitGramPerSquareCentimetre        Unit = 66
+	UnitAtmosphere                     Unit = 67
+	UnitSignalStrengthDecibelMilliwatt Unit = 70
+	UnitSignalStrengthDecibelMicrovolt Unit = 71
+	UnitDecibel                        Unit = 72
+	UnitOther                          Unit = 254
+	UnitCount                          Unit = 255
+)
+
+type unitInfo struct {
+	name   string
+	symbol string
+}
+
+//nolint:gochecknoglobals
+var units = map[Unit]unitInfo{
+	UnitYear:                           {"year", "a"},
+	UnitMonth:                          {"month", "mo"},
+	UnitWeek:                           {"week", "wk"},
+	UnitDay:                            {"day", "d"},
+	UnitHour:                           {"hour", "h"},
+	UnitMinute:                         {"minute", "min"},
+	UnitSecond:                         {"second", "s"},
+	UnitDegree:                         {"phase angle", "°"},
+	UnitDegreeCelsius:                  {"temperature", "°C"},
+	UnitCurrency:                       {"local currency", "currency"},
+	UnitMetre:                          {"length", "m"},
+	UnitMetrePerSecond:                 {"speed", "m/s"},
+	UnitCubicMetre:                     {"volume", "m³"},
+	UnitCorrectedCubicMetre:            {"corrected volume", "m³"},
+	UnitCubicMetrePerHour:              {"volume flux", "m³/h"},
+	UnitCorrectedCubicMetrePerHour:     {"corrected volume flux", "m³/h"},
+	UnitCubicMetrePerDay:               {"volume flux", "m³/d"},
+	UnitCorrectedCubicMetrePerDay:      {"corrected volume flux", "m³/d"},
+	UnitLitre:                          {"volume", "l"},
+	UnitKilogram:                       {"mass", "kg"},
+	UnitNewton:                         {"force", "N"},
+	UnitNewtonMetre:                    {"energy", "Nm"},
+	UnitPascal:                         {"pressure", "Pa"},
+	UnitBar:                            {"pressure", "bar"},
+	UnitJoule:                          {"energy", "J"},
+	UnitJoulePerHour:                   {"thermal power", "J/h"},
+	UnitWatt:                           {"active power", "W"},
+	UnitVoltAmpere:                     {"apparent power", "VA"},
+	UnitVar:                            {"reactive power", "var"},
+	UnitWattHour:                       {"active energy", "Wh"},
+	UnitVoltAmpereHour:                 {"apparent energy", "VAh"},
+	UnitVarHour:                        {"reactive energy", "varh"},
+	UnitAmpere:                         {"current", "A"},
+	UnitCoulomb:                        {"electrical charge", "C"},
+	UnitVolt:                           {"voltage", "V"},
+	UnitVoltPerMetre:                   {"electric field strength", "V/m"},
+	UnitFarad:                          {"capacitance", "F"},
+	UnitOhm:                            {"resistance", "Ω"},
+	UnitResistivity:                    {"resistivity", "Ωm²/m"},
+	UnitWeber:                          {"magnetic flux", "Wb"},
+	UnitTesla:                          {"magnetic flux density", "T"},
+	UnitAmperePerMetre:                 {"magnetic field strength", "A/m"},
+	UnitHenry:                          {"inductance", "H"},
+	UnitHertz:                          {"frequency", "Hz"},
+	UnitActiveEnergyMeterConstant:      {"active energy meter constant", "1/(Wh)"},
+	UnitReactiveEnergyMeterConstant:    {"reactive energy meter constant", "1/(varh)"},
+	UnitApparentEnergyMeterConstant:    {"apparent energy meter constant", "1/(VAh)"},
+	UnitVoltSquaredHour:                {"volt-squared hour", "V²h"},
+	UnitAmpereSquaredHour:              {"ampere-squared hour", "A²h"},
+	UnitKilogramPerSecond:              {"mass flux", "kg/s"},
+	UnitSiemens:                        {"conductance", "S"},
+	UnitKelvin:                         {"temperature", "K"},
+	UnitVoltSquaredHourMeterConstant:   {"volt-squared hour meter constant", "1/(V²h)"},
+	UnitAmpereSquaredHourMeterConstant: {"ampere-squared hour meter constant", "1/(A²h)"},
+	UnitVolumeMeterConstant:            {"meter constant for volume", "1/m³"},
+	UnitPercentage:                     {"percentage", "%"},
+	UnitAmpereHour:                     {"ampere-hours", "Ah"},
+	UnitEnergyPerVolume:                {"energy per volume", "Wh/m³"},
+	UnitCalorificValue:                 {"calorific value, wobbe", "J/m³"},
+	UnitMolePercent:                    {"molar fraction of gas composition", "Mol %"},
+	UnitMassDensity:                    {"mass density, quantity of material", "g/m³"},
+	UnitDynamicViscosity:               {"dynamic viscosity", "Pa s"},
+	UnitSpecificEnergy:                 {"specific energy", "J/kg"},
+	UnitGramPerSquareCentimetre:        {"pressure, gram per square centimetre", "g/cm²"},
+	UnitAtmosphere:                     {"pressure, atmosphere", "atm"},
+	UnitSignalStrengthDecibelMilliwatt: {"signal strength, dB milliwatt", "dBm"},
+	UnitSignalStrengthDecibelMicrovolt: {"signal strength, dB microvolt", "dBμV"},
+	UnitDecibel:                        {"logarithmic unit", "dB"},
+	UnitOther:                          {"other unit", ""},
+	UnitCount:                          {"no unit, unitless, count", ""},
+}
+
+// Name returns the quantity measured with the unit, as named in the Blue Book.
+func (u Unit) Name() string {
+	if info, ok := units[u]; ok {
+		return info.name
+	}
+
+	return "reserved"
+}
+
+// Symbol returns the symbol of the unit, empty for the units without one.
+func (u Unit) Symbol() string {
+	return units[u].symbol
+}
+
+func (u Unit) String() string {
+	if info, ok := units[u]; ok && info.symbol != "" {
+		return info.symbol
+	}
+
+	return "unit(" + strconv.Itoa(int(u)) + ")"
+}
+
+// ScalerUnit is the scaler_unit attribute of the Register, Extended Register and
+// Demand Register classes. The physical value is the raw value multiplied by 10^Scaler.
+type ScalerUnit struct {
+	Scaler int8
+	Unit   Unit
+}
+
+// MarshalAXDR encodes the scaler_unit as a structure of the scaler and the unit
+// enumeration.
+func (su ScalerUnit) MarshalAXDR() (*axdr.DlmsData, error) {
+	return axdr.CreateAxdrStructure([]*axdr.DlmsData{
+		axdr.CreateAxdrInteger(su.Scaler),
+		axdr.CreateAxdrEnum(uint8(su.Unit)),
+	}), nil
+}
+
+// Apply returns the physical value of a raw numeric value.
+func (su ScalerUnit) Apply(value interface{}) (PhysicalValue, error) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	var raw float64
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		raw = float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		raw = float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		raw = rv.Float()
+	default:
+		return PhysicalValue{}, fmt.Errorf("cannot scale a value of type %T", value)
+	}
+
+	// Dividing by the power of ten for negative scalers keeps results such as 123.45 exact
+	if su.Scaler < 0 {
+		raw /= math.Pow10(-int(su.Scaler))
+	} else {
+		raw *= math.Pow10(int(su.Scaler))
+	}
+
+	return PhysicalValue{Value: raw, Unit: su.Unit}, nil
+}
+
+// PhysicalValue is a value already scaled together with its unit.
+type PhysicalValue struct {
+	Value float64
+	Unit  Unit
+}
+
+func (pv PhysicalValue) String() string {
+	symbol := pv.Unit.Symbol()
+	if symbol == "" {
+		return strconv.FormatFloat(pv.Value, 'f', -1, 64)
+	}
+
+	return strconv.FormatFloat(pv.Value, 'f', -1, 64) + " " + symbol
+}
@@ -0,0 +1,63 @@
+package dlms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+func TestUnit(t *testing.T) {
+	assert.Equal(t, "active energy", UnitWattHour.Name())
+	assert.Equal(t, "Wh", UnitWattHour.Symbol())
+	assert.Equal(t, "Wh", UnitWattHour.String())
+	assert.Equal(t, "°C", UnitDegreeCelsius.String())
+
+	assert.Equal(t, "no unit, unitless, count", UnitCount.Name())
+	assert.Equal(t, "", UnitCount.Symbol())
+	assert.Equal(t, "unit(255)", UnitCount.String())
+
+	assert.Equal(t, "reserved", Unit(100).Name())
+	assert.Equal(t, "unit(100)", Unit(100).String())
+}
+
+func TestScalerUnitApply(t *testing.T) {
+	tests := []struct {
+		su       ScalerUnit
+		value    interface{}
+		expected PhysicalValue
+	}{
+		{ScalerUnit{Scaler: -2, Unit: UnitWattHour}, uint32(12345), PhysicalValue{123.45, UnitWattHour}},
+		{ScalerUnit{Scaler: 3, Unit: UnitWatt}, int16(-12), PhysicalValue{-12000, UnitWatt}},
+		{ScalerUnit{Scaler: -1, Unit: UnitVolt}, float32(2305), PhysicalValue{230.5, UnitVolt}},
+		{ScalerUnit{Scaler: 0, Unit: UnitCount}, uint8(7), PhysicalValue{7, UnitCount}},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.su.Apply(tt.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, got)
+	}
+
+	_, err := ScalerUnit{}.Apply("1234")
+	assert.Error(t, err)
+}
+
+func TestScalerUnitMarshal(t *testing.T) {
+	data, err := axdr.MarshalData(ScalerUnit{Scaler: -2, Unit: UnitWattHour})
+	assert.NoError(t, err)
+	assert.Equal(t, axdr.CreateAxdrStructure([]*axdr.DlmsData{
+		axdr.CreateAxdrInteger(-2),
+		axdr.CreateAxdrEnum(uint8(UnitWattHour)),
+	}), data)
+
+	var su ScalerUnit
+	err = axdr.UnmarshalData(*data, &su)
+	assert.NoError(t, err)
+	assert.Equal(t, ScalerUnit{Scaler: -2, Unit: UnitWattHour}, su)
+}
+
+func TestPhysicalValueString(t *testing.T) {
+	assert.Equal(t, "123.45 Wh", PhysicalValue{123.45, UnitWattHour}.String())
+	assert.Equal(t, "7", PhysicalValue{7, UnitCount}.String())
+}
@@ -0,0 +1,263 @@
+package dlms
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+type variableAccessTag uint8
+
+const (
+	TagVariableName         variableAccessTag = 0x2
+	TagParameterizedAccess  variableAccessTag = 0x4
+	TagBlockNumberAccess    variableAccessTag = 0x5
+	TagReadDataBlockAccess  variableAccessTag = 0x6
+	TagWriteDataBlockAccess variableAccessTag = 0x7
+)
+
+// Attributes of an object are addressed in steps of 8 from its base name
+const shortNameAttributeOffset = 0x08
+
+// Value will return primitive value of the target.
+// This is used for comparing with non custom typed object
+func (s variableAccessTag) Value() uint8 {
+	return uint8(s)
+}
+
+// ShortName returns the short name of an attribute given the base name of its object.
+func ShortName(baseName uint16, attribute int8) uint16 {
+	return baseName + uint16(attribute-1)*shortNameAttributeOffset
+}
+
+// VariableAccessSpecification addresses a variable in the SN services. Only the
+// fields related to the Tag are used when encoding.
+type VariableAccessSpecification struct {
+	Tag          variableAccessTag
+	VariableName uint16
+	Selector     uint8
+	Parameter    axdr.DlmsData
+	BlockNumber  uint16
+	LastBlock    bool
+	RawData      []byte
+}
+
+func CreateVariableNameAccess(variableName uint16) *VariableAccessSpecification {
+	return &VariableAccessSpecification{Tag: TagVariableName, VariableName: variableName}
+}
+
+func CreateParameterizedAccess(variableName uint16, selector uint8, parameter axdr.DlmsData) *VariableAccessSpecification {
+	return &VariableAccessSpecification{Tag: TagParameterizedAccess, VariableName: variableName, Selector: selector, Parameter: parameter}
+}
+
+func CreateBlockNumberAccess(blockNumber uint16) *VariableAccessSpecification {
+	return &VariableAccessSpecification{Tag: TagBlockNumberAccess, BlockNumber: blockNumber}
+}
+
+func CreateReadDataBlockAccess(lastBlock bool, blockNumber uint16, rawData []byte) *VariableAccessSpecification {
+	return &VariableAccessSpecification{Tag: TagReadDataBlockAccess, LastBlock: lastBlock, BlockNumber: blockNumber, RawData: rawData}
+}
+
+func CreateWriteDataBlockAccess(lastBlock bool, blockNumber uint16) *VariableAccessSpecification {
+	return &VariableAccessSpecification{Tag: TagWriteDataBlockAccess, LastBlock: lastBlock, BlockNumber: blockNumber}
+}
+
+func (v VariableAccessSpecification) String() string {
+	switch v.Tag {
+	case TagVariableName:
+		return fmt.Sprintf("{ 0x%04X }", v.VariableName)
+	case TagParameterizedAccess:
+		return fmt.Sprintf("{ 0x%04X, %d }", v.VariableName, v.Selector)
+	default:
+		return fmt.Sprintf("{ block %d }", v.BlockNumber)
+	}
+}
+
+func (v VariableAccessSpecification) Encode() (out []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte(v.Tag.Value())
+
+	switch v.Tag {
+	case TagVariableName:
+		buf.Write(encodeUint16(v.VariableName))
+	case TagParameterizedAccess:
+		buf.Write(encodeUint16(v.VariableName))
+		buf.WriteByte(v.Selector)
+		param, e := v.Parameter.Encode()
+		if e != nil {
+			err = e
+			return
+		}
+		buf.Write(param)
+	case TagBlockNumberAccess:
+		buf.Write(encodeUint16(v.BlockNumber))
+	case TagReadDataBlockAccess:
+		buf.WriteByte(encodeBool(v.LastBlock))
+		buf.Write(encodeUint16(v.BlockNumber))
+		raw, e := encodeOctetString(v.RawData)
+		if e != nil {
+			err = e
+			return
+		}
+		buf.Write(raw)
+	case TagWriteDataBlockAccess:
+		buf.WriteByte(encodeBool(v.LastBlock))
+		buf.Write(encodeUint16(v.BlockNumber))
+	default:
+		err = fmt.Errorf("variable access tag not recognized (%v)", v.Tag)
+		return
+	}
+
+	out = buf.Bytes()
+	return
+}
+
+func DecodeVariableAccessSpecification(ori *[]byte) (out VariableAccessSpecification, err error) {
+	src := *ori
+
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
+
+	out.Tag = variableAccessTag(src[0])
+	src = src[1:]
+
+	switch out.Tag {
+	case TagVariableName:
+		_, out.VariableName, err = axdr.DecodeLongUnsigned(&src)
+	case TagParameterizedAccess:
+		_, out.VariableName, err = axdr.DecodeLongUnsigned(&src)
+		if err != nil {
+			return
+		}
+		_, out.Selector, err = axdr.DecodeUnsigned(&src)
+		if err != nil {
+			return
+		}
+		if len(src) < 1 {
+			err = ErrWrongLength(len(src), 1)
+			return
+		}
+		decoder := axdr.NewDataDecoder(&src)
+		out.Parameter, err = decoder.Decode(&src)
+	case TagBlockNumberAccess:
+		_, out.BlockNumber, err = axdr.DecodeLongUnsigned(&src)
+	case TagReadDataBlockAccess:
+		_, out.LastBlock, err = axdr.DecodeBoolean(&src)
+		if err != nil {
+			return
+		}
+		_, out.BlockNumber, err = axdr.DecodeLongUnsigned(&src)
+		if err != nil {
+			return
+		}
+		out.RawData, err = decodeOctetString(&src)
+	case TagWriteDataBlockAccess:
+		_, out.LastBlock, err = axdr.DecodeBoolean(&src)
+		if err != nil {
+			return
+		}
+		_, out.BlockNumber, err = axdr.DecodeLongUnsigned(&src)
+	default:
+		err = fmt.Errorf("byte tag not recognized (%v)", out.Tag)
+	}
+
+	if err != nil {
+		return
+	}
+
+	(*ori) = (*ori)[len((*ori))-len(src):]
+	return
+}
+
+func encodeVariableAccessList(list []VariableAccessSpecification) (out []byte, err error) {
+	var buf bytes.Buffer
+
+	length, err := axdr.EncodeLength(len(list))
+	if err != nil {
+		return
+	}
+	buf.Write(length)
+
+	for _, v := range list {
+		val, e := v.Encode()
+		if e != nil {
+			err = e
+			return
+		}
+		buf.Write(val)
+	}
+
+	out = buf.Bytes()
+	return
+}
+
+func decodeVariableAccessList(src *[]byte) (out []VariableAccessSpecification, err error) {
+	if len(*src) < 1 {
+		err = ErrWrongLength(len(*src), 1)
+		return
+	}
+
+	_, count, err := axdr.DecodeLength(src)
+	if err != nil {
+		return
+	}
+
+	out = make([]VariableAccessSpecification, 0)
+	for i := uint64(0); i < count; i++ {
+		v, e := DecodeVariableAccessSpecification(src)
+		if e != nil {
+			err = e
+			return
+		}
+		out = append(out, v)
+	}
+
+	return
+}
+
+func encodeUint16(value uint16) []byte {
+	out := make([]byte, 2)
+	binary.BigEndian.PutUint16(out, value)
+	return out
+}
+
+func encodeBool(value bool) byte {
+	if value {
+		return 0x1
+	}
+	return 0x0
+}
+
+func encodeOctetString(value []byte) (out []byte, err error) {
+	out, err = axdr.EncodeLength(len(value))
+	if err != nil {
+		return
+	}
+	out = append(out, value...)
+	return
+}
+
+func decodeOctetString(src *[]byte) (out []byte, err error) {
+	if len(*src) < 1 {
+		err = ErrWrongLength(len(*src), 1)
+		return
+	}
+
+	_, length, err := axdr.DecodeLength(src)
+	if err != nil {
+		return
+	}
+
+	if uint64(len(*src)) < length {
+		err = ErrWrongLength(len(*src), int(length))
+		return
+	}
+
+	out = (*src)[:length]
+	(*src) = (*src)[length:]
+	return
+}
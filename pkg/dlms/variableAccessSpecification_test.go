@@ -0,0 +1,55 @@
+package dlms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+func TestVariableAccessSpecification(t *testing.T) {
+	tests := []struct {
+		name string
+		va   VariableAccessSpecification
+		hex  string
+	}{
+		{"variable name", *CreateVariableNameAccess(0xFA00), "02FA00"},
+		{"parameterized access", *CreateParameterizedAccess(0xFA08, 1, *axdr.CreateAxdrLongUnsigned(5)), "04FA0801120005"},
+		{"block number access", *CreateBlockNumberAccess(2), "050002"},
+		{"read data block access", *CreateReadDataBlockAccess(true, 3, []byte{0x01, 0x02}), "0601000302" + "0102"},
+		{"write data block access", *CreateWriteDataBlockAccess(false, 4), "07000004"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.va.Encode()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.hex, encodeHexString(out))
+
+			src := decodeHexString(tt.hex)
+			va, err := DecodeVariableAccessSpecification(&src)
+			assert.NoError(t, err)
+			assert.Empty(t, src)
+			assert.Equal(t, tt.va.Tag, va.Tag)
+			assert.Equal(t, tt.va.VariableName, va.VariableName)
+			assert.Equal(t, tt.va.Selector, va.Selector)
+			assert.Equal(t, tt.va.BlockNumber, va.BlockNumber)
+			assert.Equal(t, tt.va.LastBlock, va.LastBlock)
+			assert.Equal(t, len(tt.va.RawData), len(va.RawData))
+		})
+	}
+}
+
+func TestVariableAccessSpecificationInvalid(t *testing.T) {
+	for _, s := range []string{"", "01FA00", "02FA", "0601000302"} {
+		src := decodeHexString(s)
+		_, err := DecodeVariableAccessSpecification(&src)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestShortName(t *testing.T) {
+	assert.Equal(t, uint16(0xFA00), ShortName(0xFA00, 1))
+	assert.Equal(t, uint16(0xFA08), ShortName(0xFA00, 2))
+	assert.Equal(t, uint16(0xFA10), ShortName(0xFA00, 3))
+}
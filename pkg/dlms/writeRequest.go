@@ -0,0 +1,170 @@
+package dlms
+
+import (
+	"bytes"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+// WriteRequest implement CosemPDU. It is the SN counterpart of the SET service
+type WriteRequest struct {
+	Variables []VariableAccessSpecification
+	Data      []axdr.DlmsData
+}
+
+func CreateWriteRequest(variables []VariableAccessSpecification, data []axdr.DlmsData) *WriteRequest {
+	return &WriteRequest{Variables: variables, Data: data}
+}
+
+func (wr WriteRequest) Encode() (out []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte(byte(TagWriteRequest))
+
+	content, err := encodeWriteContent(wr.Variables, wr.Data)
+	if err != nil {
+		return
+	}
+	buf.Write(content)
+
+	out = buf.Bytes()
+	return
+}
+
+// EncodeContent returns the WriteRequest without its tag, which is what is split
+// in blocks when the request does not fit in a single PDU.
+func (wr WriteRequest) EncodeContent() (out []byte, err error) {
+	return encodeWriteContent(wr.Variables, wr.Data)
+}
+
+func DecodeWriteRequest(ori *[]byte) (out WriteRequest, err error) {
+	src := *ori
+
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
+	if src[0] != TagWriteRequest.Value() {
+		err = ErrWrongTag(0, src[0], byte(TagWriteRequest))
+		return
+	}
+	src = src[1:]
+
+	out.Variables, out.Data, err = decodeWriteContent(&src)
+	if err != nil {
+		return
+	}
+
+	(*ori) = (*ori)[len((*ori))-len(src):]
+	return
+}
+
+// UnconfirmedWriteRequest implement CosemPDU. The server does not send any response to it
+type UnconfirmedWriteRequest struct {
+	Variables []VariableAccessSpecification
+	Data      []axdr.DlmsData
+}
+
+func CreateUnconfirmedWriteRequest(variables []VariableAccessSpecification, data []axdr.DlmsData) *UnconfirmedWriteRequest {
+	return &UnconfirmedWriteRequest{Variables: variables, Data: data}
+}
+
+func (wr UnconfirmedWriteRequest) Encode() (out []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte(byte(TagUnconfirmedWriteRequest))
+
+	content, err := encodeWriteContent(wr.Variables, wr.Data)
+	if err != nil {
+		return
+	}
+	buf.Write(content)
+
+	out = buf.Bytes()
+	return
+}
+
+func DecodeUnconfirmedWriteRequest(ori *[]byte) (out UnconfirmedWriteRequest, err error) {
+	src := *ori
+
+	if len(src) < 3 {
+		err = ErrWrongLength(len(src), 3)
+		return
+	}
+
+	if src[0] != TagUnconfirmedWriteRequest.Value() {
+		err = ErrWrongTag(0, src[0], byte(TagUnconfirmedWriteRequest))
+		return
+	}
+	src = src[1:]
+
+	out.Variables, out.Data, err = decodeWriteContent(&src)
+	if err != nil {
+		return
+	}
+
+	(*ori) = (*ori)[len((*ori))-len(src):]
+	return
+}
+
+func encodeWriteContent(variables []VariableAccessSpecification, data []axdr.DlmsData) (out []byte, err error) {
+	var buf bytes.Buffer
+
+	list, err := encodeVariableAccessList(variables)
+	if err != nil {
+		return
+	}
+	buf.Write(list)
+
+	length, err := axdr.EncodeLength(len(data))
+	if err != nil {
+		return
+	}
+	buf.Write(length)
+
+	for _, d := range data {
+		val, e := d.Encode()
+		if e != nil {
+			err = e
+			return
+		}
+		buf.Write(val)
+	}
+
+	out = buf.Bytes()
+	return
+}
+
+func decodeWriteContent(src *[]byte) (variables []VariableAccessSpecification, data []axdr.DlmsData, err error) {
+	variables, err = decodeVariableAccessList(src)
+	if err != nil {
+		return
+	}
+
+	if len(*src) < 1 {
+		err = ErrWrongLength(len(*src), 1)
+		return
+	}
+
+	_, count, err := axdr.DecodeLength(src)
+	if err != nil {
+		return
+	}
+
+	data = make([]axdr.DlmsData, 0)
+	for i := uint64(0); i < count; i++ {
+		if len(*src) < 1 {
+			err = ErrWrongLength(len(*src), 1)
+			return
+		}
+
+		decoder := axdr.NewDataDecoder(src)
+		d, e := decoder.Decode(src)
+		if e != nil {
+			err = e
+			return
+		}
+		data = append(data, d)
+	}
+
+	return
+}
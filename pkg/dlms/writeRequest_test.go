@@ -0,0 +1,47 @@
+package dlms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+func TestWriteRequest(t *testing.T) {
+	a := *CreateWriteRequest([]VariableAccessSpecification{*CreateVariableNameAccess(0xFA08)}, []axdr.DlmsData{*axdr.CreateAxdrLongUnsigned(5)})
+	out, err := a.Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, "060102FA0801120005", encodeHexString(out))
+
+	content, err := a.EncodeContent()
+	assert.NoError(t, err)
+	assert.Equal(t, out[1:], content)
+
+	src := decodeHexString("060102FA0801120005")
+	b, err := DecodeWriteRequest(&src)
+	assert.NoError(t, err)
+	assert.Empty(t, src)
+	assert.Equal(t, uint16(0xFA08), b.Variables[0].VariableName)
+	assert.Equal(t, uint16(5), b.Data[0].Value)
+
+	src = decodeHexString("060102FA0801")
+	_, err = DecodeWriteRequest(&src)
+	assert.Error(t, err)
+}
+
+func TestUnconfirmedWriteRequest(t *testing.T) {
+	a := *CreateUnconfirmedWriteRequest([]VariableAccessSpecification{*CreateVariableNameAccess(0xFA08)}, []axdr.DlmsData{*axdr.CreateAxdrLongUnsigned(5)})
+	out, err := a.Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, "160102FA0801120005", encodeHexString(out))
+
+	src := decodeHexString("160102FA0801120005")
+	b, err := DecodeUnconfirmedWriteRequest(&src)
+	assert.NoError(t, err)
+	assert.Empty(t, src)
+	assert.Equal(t, uint16(0xFA08), b.Variables[0].VariableName)
+
+	src = decodeHexString("060102FA0801120005")
+	_, err = DecodeUnconfirmedWriteRequest(&src)
+	assert.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package dlms
+
+import (
+	"bytes"
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+type writeResultTag uint8
+
+const (
+	TagWriteResultSuccess         writeResultTag = 0x0
+	TagWriteResultDataAccessError writeResultTag = 0x1
+	TagWriteResultBlockNumber     writeResultTag = 0x2
+)
+
+// Value will return primitive value of the target.
+// This is used for comparing with non custom typed object
+func (s writeResultTag) Value() uint8 {
+	return uint8(s)
+}
+
+// WriteResult is each of the results of the WRITE-response. Only the field related to
+// the Tag is meaningful.
+type WriteResult struct {
+	Tag         writeResultTag
+	AccessError AccessResultTag
+	BlockNumber uint16
+}
+
+func CreateWriteResultAsSuccess() *WriteResult {
+	return &WriteResult{Tag: TagWriteResultSuccess}
+}
+
+func CreateWriteResultAsAccessError(access AccessResultTag) *WriteResult {
+	return &WriteResult{Tag: TagWriteResultDataAccessError, AccessError: access}
+}
+
+func CreateWriteResultAsBlockNumber(blockNumber uint16) *WriteResult {
+	return &WriteResult{Tag: TagWriteResultBlockNumber, BlockNumber: blockNumber}
+}
+
+func (wr WriteResult) Encode() (out []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte(wr.Tag.Value())
+
+	switch wr.Tag {
+	case TagWriteResultSuccess:
+	case TagWriteResultDataAccessError:
+		buf.WriteByte(byte(wr.AccessError))
+	case TagWriteResultBlockNumber:
+		buf.Write(encodeUint16(wr.BlockNumber))
+	default:
+		err = fmt.Errorf("write result tag not recognized (%v)", wr.Tag)
+		return
+	}
+
+	out = buf.Bytes()
+	return
+}
+
+func DecodeWriteResult(ori *[]byte) (out WriteResult, err error) {
+	src := *ori
+
+	if len(src) < 1 {
+		err = ErrWrongLength(len(src), 1)
+		return
+	}
+
+	out.Tag = writeResultTag(src[0])
+	src = src[1:]
+
+	switch out.Tag {
+	case TagWriteResultSuccess:
+	case TagWriteResultDataAccessError:
+		if len(src) < 1 {
+			err = ErrWrongLength(len(src), 1)
+			return
+		}
+		out.AccessError, err = GetAccessTag(src[0])
+		src = src[1:]
+	case TagWriteResultBlockNumber:
+		_, out.BlockNumber, err = axdr.DecodeLongUnsigned(&src)
+	default:
+		err = fmt.Errorf("byte tag not recognized (%v)", out.Tag)
+	}
+
+	if err != nil {
+		return
+	}
+
+	(*ori) = (*ori)[len((*ori))-len(src):]
+	return
+}
+
+// WriteResponse implement CosemPDU. It contains a result for each variable of the WriteRequest
+type WriteResponse struct {
+	Results []WriteResult
+}
+
+func CreateWriteResponse(results []WriteResult) *WriteResponse {
+	return &WriteResponse{Results: results}
+}
+
+func (wr WriteResponse) Encode() (out []byte, err error) {
+	var buf bytes.Buffer
+	buf.WriteByte(byte(TagWriteResponse))
+
+	length, err := axdr.EncodeLength(len(wr.Results))
+	if err != nil {
+		return
+	}
+	buf.Write(length)
+
+	for _, res := range wr.Results {
+		val, e := res.Encode()
+		if e != nil {
+			err = e
+			return
+		}
+		buf.Write(val)
+	}
+
+	out = buf.Bytes()
+	return
+}
+
+func DecodeWriteResponse(ori *[]byte) (out WriteResponse, err error) {
+	src := *ori
+
+	if len(src) < 2 {
+		err = ErrWrongLength(len(src), 2)
+		return
+	}
+
+	if src[0] != TagWriteResponse.Value() {
+		err = ErrWrongTag(0, src[0], byte(TagWriteResponse))
+		return
+	}
+	src = src[1:]
+
+	_, count, err := axdr.DecodeLength(&src)
+	if err != nil {
+		return
+	}
+
+	out.Results = make([]WriteResult, 0)
+	for i := uint64(0); i < count; i++ {
+		res, e := DecodeWriteResult(&src)
+		if e != nil {
+			err = e
+			return
+		}
+		out.Results = append(out.Results, res)
+	}
+
+	(*ori) = (*ori)[len((*ori))-len(src):]
+	return
+}
@@ -0,0 +1,35 @@
+package dlms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteResponse(t *testing.T) {
+	a := *CreateWriteResponse([]WriteResult{
+		*CreateWriteResultAsSuccess(),
+		*CreateWriteResultAsAccessError(TagAccTypeUnmatched),
+		*CreateWriteResultAsBlockNumber(3),
+	})
+	out, err := a.Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, "0D0300010C020003", encodeHexString(out))
+
+	src := decodeHexString("0D0300010C020003")
+	b, err := DecodeWriteResponse(&src)
+	assert.NoError(t, err)
+	assert.Empty(t, src)
+	assert.Len(t, b.Results, 3)
+	assert.Equal(t, TagWriteResultSuccess, b.Results[0].Tag)
+	assert.Equal(t, TagAccTypeUnmatched, b.Results[1].AccessError)
+	assert.Equal(t, uint16(3), b.Results[2].BlockNumber)
+}
+
+func TestWriteResponseInvalid(t *testing.T) {
+	for _, s := range []string{"0D", "0C0100", "0D0101", "0D0105"} {
+		src := decodeHexString(s)
+		_, err := DecodeWriteResponse(&src)
+		assert.Error(t, err, s)
+	}
+}
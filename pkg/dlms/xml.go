@@ -0,0 +1,392 @@
+package dlms
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+// EncodeXML returns the XML representation of the PDU defined in the Green Book,
+// as shown by other DLMS tools. The elements are named as in the ASN.1 definition
+// of the PDU in CamelCase, e.g.:
+//
+//	<GetRequest>
+//	  <GetRequestNormal>
+//	    <InvokeIdAndPriority Value="C1"></InvokeIdAndPriority>
+//	    <AttributeDescriptor>
+//	      <ClassId Value="0008"></ClassId>
+//	      <InstanceId Value="0000010000FF"></InstanceId>
+//	      <AttributeId Value="02"></AttributeId>
+//	    </AttributeDescriptor>
+//	  </GetRequestNormal>
+//	</GetRequest>
+//
+// Numbers and octet-strings are given in hex in the Value attribute, results and
+// errors by their name, lists have their number of elements in the hex Qty
+// attribute and data is encoded as in axdr.DlmsData.MarshalXML.
+func EncodeXML(pdu CosemPDU) ([]byte, error) {
+	if pdu == nil {
+		return nil, fmt.Errorf("pdu cannot be nil")
+	}
+
+	// Pointers to the PDUs are encoded as the PDUs
+	if v := reflect.ValueOf(pdu); v.Kind() == reflect.Ptr && !v.IsNil() {
+		if p, ok := v.Elem().Interface().(CosemPDU); ok {
+			pdu = p
+		}
+	}
+
+	node, err := pduXML(pdu)
+	if err != nil {
+		return nil, err
+	}
+
+	return xml.MarshalIndent(node, "", "  ")
+}
+
+// DecodeXML decodes a PDU from the XML representation given by EncodeXML. Comments
+// are ignored, so XML shown by other tools can be decoded as long as it has the
+// same elements. The PDU is returned as by DecodeCosem.
+func DecodeXML(src []byte) (CosemPDU, error) {
+	var node xmlNode
+	if err := xml.Unmarshal(src, &node); err != nil {
+		return nil, fmt.Errorf("invalid XML: %w", err)
+	}
+
+	name := node.XMLName.Local
+	if xmlServices()[name] {
+		if len(node.Nodes) != 1 {
+			return nil, fmt.Errorf("%s must have one element", name)
+		}
+
+		node = node.Nodes[0]
+		name = node.XMLName.Local
+	}
+
+	decode, ok := xmlDecoders()[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown PDU %s", name)
+	}
+
+	pdu, err := decode(&node)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	return pdu, nil
+}
+
+// xmlServices are the elements holding the PDUs of each service
+func xmlServices() map[string]bool {
+	return map[string]bool{
+		"GetRequest":     true,
+		"GetResponse":    true,
+		"SetRequest":     true,
+		"SetResponse":    true,
+		"ActionRequest":  true,
+		"ActionResponse": true,
+	}
+}
+
+// xmlNode is an element of the XML representation of a PDU. When encoding, it
+// holds either the data or the nodes inside it; when decoding, Inner holds its
+// raw contents, used to decode the data in it.
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Inner   []byte     `xml:",innerxml"`
+	Nodes   []xmlNode  `xml:",any"`
+	data    []axdr.DlmsData
+}
+
+func (n xmlNode) MarshalXML(e *xml.Encoder, _ xml.StartElement) error {
+	start := xml.StartElement{Name: n.XMLName, Attr: n.Attrs}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, data := range n.data {
+		if err := data.MarshalXML(e, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+
+	for _, node := range n.Nodes {
+		if err := node.MarshalXML(e, xml.StartElement{}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+func newXMLNode(name string, nodes ...xmlNode) xmlNode {
+	return xmlNode{XMLName: xml.Name{Local: name}, Nodes: nodes}
+}
+
+func xmlValueNode(name string, value string) xmlNode {
+	node := newXMLNode(name)
+	node.Attrs = []xml.Attr{{Name: xml.Name{Local: "Value"}, Value: value}}
+
+	return node
+}
+
+// xmlUintNode returns a node with the value in hex of size bytes
+func xmlUintNode(name string, value uint64, size int) xmlNode {
+	return xmlValueNode(name, fmt.Sprintf("%0*X", size*2, value))
+}
+
+func xmlBoolNode(name string, value bool) xmlNode {
+	return xmlUintNode(name, uint64(encodeBool(value)), 1)
+}
+
+func xmlBytesNode(name string, value []byte) xmlNode {
+	return xmlValueNode(name, strings.ToUpper(hex.EncodeToString(value)))
+}
+
+// xmlEnumNode returns a node with the name of the value, or its hex if it has none
+func xmlEnumNode(name string, value uint8, names map[uint8]string) xmlNode {
+	if s, ok := names[value]; ok {
+		return xmlValueNode(name, s)
+	}
+
+	return xmlUintNode(name, uint64(value), 1)
+}
+
+func xmlDataNode(name string, data ...axdr.DlmsData) xmlNode {
+	node := newXMLNode(name)
+	node.data = data
+
+	return node
+}
+
+// xmlListNode returns a node with the nodes and their number in the Qty attribute
+func xmlListNode(name string, nodes []xmlNode) xmlNode {
+	node := newXMLNode(name, nodes...)
+	node.Attrs = []xml.Attr{xmlQty(len(nodes))}
+
+	return node
+}
+
+func xmlDataListNode(name string, data []axdr.DlmsData) xmlNode {
+	node := xmlDataNode(name, data...)
+	node.Attrs = []xml.Attr{xmlQty(len(data))}
+
+	return node
+}
+
+func xmlQty(n int) xml.Attr {
+	return xml.Attr{Name: xml.Name{Local: "Qty"}, Value: fmt.Sprintf("%02X", n)}
+}
+
+func (n *xmlNode) name() string {
+	return n.XMLName.Local
+}
+
+func (n *xmlNode) attr(name string) (string, bool) {
+	for _, attr := range n.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// optionalChild returns the first node with the name, or nil if there is none
+func (n *xmlNode) optionalChild(name string) *xmlNode {
+	for i := range n.Nodes {
+		if n.Nodes[i].name() == name {
+			return &n.Nodes[i]
+		}
+	}
+
+	return nil
+}
+
+func (n *xmlNode) child(name string) (*xmlNode, error) {
+	if child := n.optionalChild(name); child != nil {
+		return child, nil
+	}
+
+	return nil, fmt.Errorf("missing %s in %s", name, n.name())
+}
+
+// choice returns the only node inside n, which is one of the alternatives of a
+// CHOICE.
+func (n *xmlNode) choice() (*xmlNode, error) {
+	if len(n.Nodes) != 1 {
+		return nil, fmt.Errorf("%s must have one element", n.name())
+	}
+
+	return &n.Nodes[0], nil
+}
+
+func (n *xmlNode) value() (string, error) {
+	value, ok := n.attr("Value")
+	if !ok {
+		return "", fmt.Errorf("%s without value", n.name())
+	}
+
+	return value, nil
+}
+
+// uintValue returns the hex value of size bytes of the child node with the name
+func (n *xmlNode) uintValue(name string, size int) (uint64, error) {
+	child, err := n.child(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return child.uint(size)
+}
+
+func (n *xmlNode) uint(size int) (uint64, error) {
+	value, err := n.value()
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.ParseUint(value, 16, size*8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", n.name(), value)
+	}
+
+	return v, nil
+}
+
+func (n *xmlNode) boolValue(name string) (bool, error) {
+	v, err := n.uintValue(name, 1)
+	return v != 0, err
+}
+
+func (n *xmlNode) bytesValue(name string) ([]byte, error) {
+	child, err := n.child(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.bytes()
+}
+
+func (n *xmlNode) bytes() ([]byte, error) {
+	value, err := n.value()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := hex.DecodeString(strings.ReplaceAll(value, " ", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value %q", n.name(), value)
+	}
+
+	return b, nil
+}
+
+// enumValue returns the value of the child node with the name, given by the name
+// of the value or its hex.
+func (n *xmlNode) enumValue(name string, names map[uint8]string) (uint8, error) {
+	child, err := n.child(name)
+	if err != nil {
+		return 0, err
+	}
+
+	return child.enum(names)
+}
+
+func (n *xmlNode) enum(names map[uint8]string) (uint8, error) {
+	value, err := n.value()
+	if err != nil {
+		return 0, err
+	}
+
+	for v, s := range names {
+		if s == value {
+			return v, nil
+		}
+	}
+
+	v, err := strconv.ParseUint(value, 16, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", n.name(), value)
+	}
+
+	return uint8(v), nil
+}
+
+// list returns the nodes inside the child node with the name, checking their
+// number with its Qty attribute, if any.
+func (n *xmlNode) list(name string) ([]xmlNode, error) {
+	child, err := n.child(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return child.Nodes, child.checkQty(len(child.Nodes))
+}
+
+func (n *xmlNode) checkQty(length int) error {
+	qty, ok := n.attr("Qty")
+	if !ok {
+		return nil
+	}
+
+	if v, err := strconv.ParseUint(qty, 16, 32); err != nil || v != uint64(length) {
+		return fmt.Errorf("%s with quantity %s and %d elements", n.name(), qty, length)
+	}
+
+	return nil
+}
+
+// dataList decodes the data inside the node.
+func (n *xmlNode) dataList() ([]axdr.DlmsData, error) {
+	dec := xml.NewDecoder(bytes.NewReader(n.Inner))
+
+	list := make([]axdr.DlmsData, 0)
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			break
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			var data axdr.DlmsData
+			if err := dec.DecodeElement(&data, &start); err != nil {
+				return nil, fmt.Errorf("invalid data in %s: %w", n.name(), err)
+			}
+			list = append(list, data)
+		}
+	}
+
+	return list, n.checkQty(len(list))
+}
+
+// dataValue decodes the data inside the child node with the name.
+func (n *xmlNode) dataValue(name string) (axdr.DlmsData, error) {
+	child, err := n.child(name)
+	if err != nil {
+		return axdr.DlmsData{}, err
+	}
+
+	return child.singleData()
+}
+
+// singleData decodes the only data inside the node.
+func (n *xmlNode) singleData() (axdr.DlmsData, error) {
+	list, err := n.dataList()
+	if err != nil {
+		return axdr.DlmsData{}, err
+	}
+
+	if len(list) != 1 {
+		return axdr.DlmsData{}, fmt.Errorf("%s must have one data", n.name())
+	}
+
+	return list[0], nil
+}
@@ -0,0 +1,1698 @@
+package dlms
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+// pduXML returns the node of a PDU, inside the node of its service if it has one.
+func pduXML(pdu CosemPDU) (xmlNode, error) {
+	switch p := pdu.(type) {
+	case GetRequestNormal:
+		return newXMLNode("GetRequest", getRequestNormalXML(p)), nil
+	case GetRequestNext:
+		return newXMLNode("GetRequest", getRequestNextXML(p)), nil
+	case GetRequestWithList:
+		return newXMLNode("GetRequest", getRequestWithListXML(p)), nil
+	case GetResponseNormal:
+		node, err := getResponseNormalXML(p)
+		return newXMLNode("GetResponse", node), err
+	case GetResponseWithDataBlock:
+		node, err := getResponseWithDataBlockXML(p)
+		return newXMLNode("GetResponse", node), err
+	case GetResponseWithList:
+		node, err := getResponseWithListXML(p)
+		return newXMLNode("GetResponse", node), err
+	case SetRequestNormal:
+		return newXMLNode("SetRequest", setRequestNormalXML(p)), nil
+	case SetRequestWithFirstDataBlock:
+		return newXMLNode("SetRequest", setRequestWithFirstDataBlockXML(p)), nil
+	case SetRequestWithDataBlock:
+		return newXMLNode("SetRequest", setRequestWithDataBlockXML(p)), nil
+	case SetRequestWithList:
+		return newXMLNode("SetRequest", setRequestWithListXML(p)), nil
+	case SetRequestWithListAndFirstDataBlock:
+		return newXMLNode("SetRequest", setRequestWithListAndFirstDataBlockXML(p)), nil
+	case SetResponseNormal:
+		return newXMLNode("SetResponse", setResponseNormalXML(p)), nil
+	case SetResponseDataBlock:
+		return newXMLNode("SetResponse", setResponseDataBlockXML(p)), nil
+	case SetResponseLastDataBlock:
+		return newXMLNode("SetResponse", setResponseLastDataBlockXML(p)), nil
+	case SetResponseLastDataBlockWithList:
+		return newXMLNode("SetResponse", setResponseLastDataBlockWithListXML(p)), nil
+	case SetResponseWithList:
+		return newXMLNode("SetResponse", setResponseWithListXML(p)), nil
+	case ActionRequestNormal:
+		return newXMLNode("ActionRequest", actionRequestNormalXML(p)), nil
+	case ActionRequestNextPBlock:
+		return newXMLNode("ActionRequest", actionRequestNextPBlockXML(p)), nil
+	case ActionRequestWithList:
+		return newXMLNode("ActionRequest", actionRequestWithListXML(p)), nil
+	case ActionRequestWithFirstPBlock:
+		return newXMLNode("ActionRequest", actionRequestWithFirstPBlockXML(p)), nil
+	case ActionRequestWithListAndFirstPBlock:
+		return newXMLNode("ActionRequest", actionRequestWithListAndFirstPBlockXML(p)), nil
+	case ActionRequestWithPBlock:
+		return newXMLNode("ActionRequest", actionRequestWithPBlockXML(p)), nil
+	case ActionResponseNormal:
+		node, err := actionResponseNormalXML(p)
+		return newXMLNode("ActionResponse", node), err
+	case ActionResponseWithPBlock:
+		return newXMLNode("ActionResponse", actionResponseWithPBlockXML(p)), nil
+	case ActionResponseWithList:
+		node, err := actionResponseWithListXML(p)
+		return newXMLNode("ActionResponse", node), err
+	case ActionResponseNextPBlock:
+		return newXMLNode("ActionResponse", actionResponseNextPBlockXML(p)), nil
+	case ReadRequest:
+		return readRequestXML(p)
+	case ReadResponse:
+		return readResponseXML(p)
+	case WriteRequest:
+		return writeRequestXML("WriteRequest", p.Variables, p.Data)
+	case UnconfirmedWriteRequest:
+		return writeRequestXML("UnconfirmedWriteRequest", p.Variables, p.Data)
+	case WriteResponse:
+		return writeResponseXML(p)
+	case DataNotification:
+		return dataNotificationXML(p)
+	case EventNotificationRequest:
+		return eventNotificationRequestXML(p)
+	case ExceptionResponse:
+		return exceptionResponseXML(p), nil
+	case ConfirmedServiceError:
+		return confirmedServiceErrorXML(p)
+	default:
+		return xmlNode{}, fmt.Errorf("PDU %T cannot be encoded to XML", pdu)
+	}
+}
+
+// xmlDecoders are the decoders of the nodes of the PDUs, by their name
+func xmlDecoders() map[string]func(*xmlNode) (CosemPDU, error) {
+	return map[string]func(*xmlNode) (CosemPDU, error){
+		"GetRequestNormal":                    decodeGetRequestNormalXML,
+		"GetRequestNext":                      decodeGetRequestNextXML,
+		"GetRequestWithList":                  decodeGetRequestWithListXML,
+		"GetResponseNormal":                   decodeGetResponseNormalXML,
+		"GetResponseWithDataBlock":            decodeGetResponseWithDataBlockXML,
+		"GetResponseWithList":                 decodeGetResponseWithListXML,
+		"SetRequestNormal":                    decodeSetRequestNormalXML,
+		"SetRequestWithFirstDataBlock":        decodeSetRequestWithFirstDataBlockXML,
+		"SetRequestWithDataBlock":             decodeSetRequestWithDataBlockXML,
+		"SetRequestWithList":                  decodeSetRequestWithListXML,
+		"SetRequestWithListAndFirstDataBlock": decodeSetRequestWithListAndFirstDataBlockXML,
+		"SetResponseNormal":                   decodeSetResponseNormalXML,
+		"SetResponseDataBlock":                decodeSetResponseDataBlockXML,
+		"SetResponseLastDataBlock":            decodeSetResponseLastDataBlockXML,
+		"SetResponseLastDataBlockWithList":    decodeSetResponseLastDataBlockWithListXML,
+		"SetResponseWithList":                 decodeSetResponseWithListXML,
+		"ActionRequestNormal":                 decodeActionRequestNormalXML,
+		"ActionRequestNextPBlock":             decodeActionRequestNextPBlockXML,
+		"ActionRequestWithList":               decodeActionRequestWithListXML,
+		"ActionRequestWithFirstPBlock":        decodeActionRequestWithFirstPBlockXML,
+		"ActionRequestWithListAndFirstPBlock": decodeActionRequestWithListAndFirstPBlockXML,
+		"ActionRequestWithPBlock":             decodeActionRequestWithPBlockXML,
+		"ActionResponseNormal":                decodeActionResponseNormalXML,
+		"ActionResponseWithPBlock":            decodeActionResponseWithPBlockXML,
+		"ActionResponseWithList":              decodeActionResponseWithListXML,
+		"ActionResponseNextPBlock":            decodeActionResponseNextPBlockXML,
+		"ReadRequest":                         decodeReadRequestXML,
+		"ReadResponse":                        decodeReadResponseXML,
+		"WriteRequest":                        decodeWriteRequestXML,
+		"UnconfirmedWriteRequest":             decodeUnconfirmedWriteRequestXML,
+		"WriteResponse":                       decodeWriteResponseXML,
+		"DataNotification":                    decodeDataNotificationXML,
+		"EventNotificationRequest":            decodeEventNotificationRequestXML,
+		"ExceptionResponse":                   decodeExceptionResponseXML,
+		"ConfirmedServiceError":               decodeConfirmedServiceErrorXML,
+	}
+}
+
+// ---- names of the enumerated values
+
+// xmlCamelCase converts the ASN.1 names, e.g. object-undefined, to the names of
+// the XML, e.g. ObjectUndefined.
+func xmlCamelCase(name string) string {
+	out := make([]rune, 0, len(name))
+	upper := true
+	for _, r := range name {
+		if r == '-' {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		out = append(out, r)
+	}
+
+	return string(out)
+}
+
+func accessResultNames() map[uint8]string {
+	names := make(map[uint8]string)
+	for i := 0; i <= 0xff; i++ {
+		if s := AccessResultTag(i).String(); s != "" {
+			names[uint8(i)] = xmlCamelCase(s)
+		}
+	}
+
+	return names
+}
+
+func actionResultNames() map[uint8]string {
+	names := make(map[uint8]string)
+	for i := 0; i <= 0xff; i++ {
+		if s := ActionResultTag(i).String(); s != "" {
+			names[uint8(i)] = xmlCamelCase(s)
+		}
+	}
+
+	return names
+}
+
+func exceptionStateErrorNames() map[uint8]string {
+	return map[uint8]string{
+		TagExcServiceNotAllowed.Value(): "ServiceNotAllowed",
+		TagExcServiceUnknown.Value():    "ServiceUnknown",
+	}
+}
+
+func exceptionServiceErrorNames() map[uint8]string {
+	return map[uint8]string{
+		TagExcOperationNotPossible.Value(): "OperationNotPossible",
+		TagExcServiceNotSupported.Value():  "ServiceNotSupported",
+		TagExcOtherReason.Value():          "OtherReason",
+	}
+}
+
+func confirmedServiceErrorNames() map[uint8]string {
+	return map[uint8]string{
+		1:  "InitiateError",
+		2:  "GetStatus",
+		3:  "GetNameList",
+		4:  "GetVariableAttribute",
+		5:  "Read",
+		6:  "Write",
+		7:  "GetDataSetAttribute",
+		8:  "GetTIAttribute",
+		9:  "ChangeScope",
+		10: "Start",
+		11: "Stop",
+		12: "Resume",
+		13: "MakeUsable",
+		14: "InitiateLoad",
+		15: "LoadSegment",
+		16: "TerminateLoad",
+		17: "InitiateUpLoad",
+		18: "UpLoadSegment",
+		19: "TerminateUpLoad",
+	}
+}
+
+func serviceErrorNames() map[uint8]string {
+	return map[uint8]string{
+		0:  "ApplicationReference",
+		1:  "HardwareResource",
+		2:  "VdeStateError",
+		3:  "Service",
+		4:  "Definition",
+		5:  "Access",
+		6:  "Initiate",
+		7:  "LoadDataSet",
+		8:  "ChangeScope",
+		9:  "Task",
+		10: "OtherError",
+	}
+}
+
+// nameOf returns the value with the name
+func nameOf(name string, names map[uint8]string) (uint8, bool) {
+	for v, s := range names {
+		if s == name {
+			return v, true
+		}
+	}
+
+	return 0, false
+}
+
+// ---- parts shared by the PDUs
+
+func invokeIDXML(invokePriority uint8) xmlNode {
+	return xmlUintNode("InvokeIdAndPriority", uint64(invokePriority), 1)
+}
+
+func decodeInvokeIDXML(n *xmlNode) (uint8, error) {
+	v, err := n.uintValue("InvokeIdAndPriority", 1)
+	return uint8(v), err
+}
+
+func blockNumberXML(blockNumber uint32) xmlNode {
+	return xmlUintNode("BlockNumber", uint64(blockNumber), 4)
+}
+
+func decodeBlockNumberXML(n *xmlNode) (uint32, error) {
+	v, err := n.uintValue("BlockNumber", 4)
+	return uint32(v), err
+}
+
+func descriptorXML(name string, classID uint16, instanceID Obis, idName string, id int8) xmlNode {
+	return newXMLNode(name,
+		xmlUintNode("ClassId", uint64(classID), 2),
+		xmlBytesNode("InstanceId", instanceID.Bytes()),
+		xmlUintNode(idName, uint64(uint8(id)), 1),
+	)
+}
+
+func decodeDescriptorXML(n *xmlNode, name string, idName string) (classID uint16, instanceID Obis, id int8, err error) {
+	descriptor, err := n.child(name)
+	if err != nil {
+		return
+	}
+
+	v, err := descriptor.uintValue("ClassId", 2)
+	if err != nil {
+		return
+	}
+	classID = uint16(v)
+
+	obis, err := descriptor.bytesValue("InstanceId")
+	if err != nil {
+		return
+	}
+
+	if len(obis) != 6 {
+		err = fmt.Errorf("invalid instance id %X", obis)
+		return
+	}
+
+	if instanceID, err = DecodeObis(&obis); err != nil {
+		return
+	}
+
+	v, err = descriptor.uintValue(idName, 1)
+	id = int8(v)
+
+	return
+}
+
+func attributeDescriptorXML(ad AttributeDescriptor) xmlNode {
+	return descriptorXML("AttributeDescriptor", ad.ClassID, ad.InstanceID, "AttributeId", ad.AttributeID)
+}
+
+func decodeAttributeDescriptorXML(n *xmlNode) (ad AttributeDescriptor, err error) {
+	ad.ClassID, ad.InstanceID, ad.AttributeID, err = decodeDescriptorXML(n, "AttributeDescriptor", "AttributeId")
+	return
+}
+
+func methodDescriptorXML(md MethodDescriptor) xmlNode {
+	return descriptorXML("MethodDescriptor", md.ClassID, md.InstanceID, "MethodId", md.MethodID)
+}
+
+func decodeMethodDescriptorXML(n *xmlNode) (md MethodDescriptor, err error) {
+	md.ClassID, md.InstanceID, md.MethodID, err = decodeDescriptorXML(n, "MethodDescriptor", "MethodId")
+	return
+}
+
+// appendAccessSelectionXML appends the node of the selective access, if any
+func appendAccessSelectionXML(nodes []xmlNode, sad *SelectiveAccessDescriptor) []xmlNode {
+	if sad == nil {
+		return nodes
+	}
+
+	return append(nodes, newXMLNode("AccessSelection",
+		xmlUintNode("AccessSelector", uint64(sad.AccessSelector), 1),
+		xmlDataNode("AccessParameters", sad.AccessParameter),
+	))
+}
+
+func decodeAccessSelectionXML(n *xmlNode) (*SelectiveAccessDescriptor, error) {
+	selection := n.optionalChild("AccessSelection")
+	if selection == nil {
+		return nil, nil
+	}
+
+	selector, err := selection.uintValue("AccessSelector", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	parameter, err := selection.dataValue("AccessParameters")
+	if err != nil {
+		return nil, err
+	}
+
+	return &SelectiveAccessDescriptor{AccessSelector: accessSelector(selector), AccessParameter: parameter}, nil
+}
+
+func attributeDescriptorListXML(list []AttributeDescriptorWithSelection) xmlNode {
+	nodes := make([]xmlNode, len(list))
+	for i, ad := range list {
+		nodes[i] = newXMLNode("_AttributeDescriptorWithSelection", appendAccessSelectionXML([]xmlNode{
+			attributeDescriptorXML(AttributeDescriptor{ClassID: ad.ClassID, InstanceID: ad.InstanceID, AttributeID: ad.AttributeID}),
+		}, ad.AccessDescriptor)...)
+	}
+
+	return xmlListNode("AttributeDescriptorList", nodes)
+}
+
+func decodeAttributeDescriptorListXML(n *xmlNode) ([]AttributeDescriptorWithSelection, error) {
+	nodes, err := n.list("AttributeDescriptorList")
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]AttributeDescriptorWithSelection, len(nodes))
+	for i := range nodes {
+		ad, err := decodeAttributeDescriptorXML(&nodes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		sad, err := decodeAccessSelectionXML(&nodes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		list[i] = AttributeDescriptorWithSelection{ClassID: ad.ClassID, InstanceID: ad.InstanceID, AttributeID: ad.AttributeID, AccessDescriptor: sad}
+	}
+
+	return list, nil
+}
+
+func methodDescriptorListXML(list []MethodDescriptor) xmlNode {
+	nodes := make([]xmlNode, len(list))
+	for i, md := range list {
+		nodes[i] = methodDescriptorXML(md)
+	}
+
+	return xmlListNode("MethodDescriptorList", nodes)
+}
+
+func decodeMethodDescriptorListXML(n *xmlNode) ([]MethodDescriptor, error) {
+	list, err := n.child("MethodDescriptorList")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = list.checkQty(len(list.Nodes)); err != nil {
+		return nil, err
+	}
+
+	mds := make([]MethodDescriptor, len(list.Nodes))
+	for i := range list.Nodes {
+		// Each descriptor is decoded from a list holding only it
+		if mds[i], err = decodeMethodDescriptorXML(&xmlNode{Nodes: list.Nodes[i : i+1]}); err != nil {
+			return nil, err
+		}
+	}
+
+	return mds, nil
+}
+
+func dataBlockSAXML(name string, block DataBlockSA) xmlNode {
+	return newXMLNode(name,
+		xmlBoolNode("LastBlock", block.LastBlock),
+		blockNumberXML(block.BlockNumber),
+		xmlBytesNode("RawData", block.Raw),
+	)
+}
+
+func decodeDataBlockSAXML(n *xmlNode, name string) (block DataBlockSA, err error) {
+	node, err := n.child(name)
+	if err != nil {
+		return
+	}
+
+	if block.LastBlock, err = node.boolValue("LastBlock"); err != nil {
+		return
+	}
+
+	if block.BlockNumber, err = decodeBlockNumberXML(node); err != nil {
+		return
+	}
+
+	block.Raw, err = node.bytesValue("RawData")
+
+	return
+}
+
+// getDataResultXML returns the node of the alternative of the result
+func getDataResultXML(result GetDataResult) (xmlNode, error) {
+	if result.IsData {
+		data, ok := result.Value.(axdr.DlmsData)
+		if !ok {
+			return xmlNode{}, fmt.Errorf("result value must be axdr.DlmsData")
+		}
+
+		return xmlDataNode("Data", data), nil
+	}
+
+	access, ok := result.Value.(AccessResultTag)
+	if !ok {
+		return xmlNode{}, fmt.Errorf("result value must be AccessResultTag")
+	}
+
+	return xmlEnumNode("DataAccessError", uint8(access), accessResultNames()), nil
+}
+
+func decodeGetDataResultXML(n *xmlNode) (GetDataResult, error) {
+	switch n.name() {
+	case "Data":
+		data, err := n.singleData()
+		return GetDataResult{IsData: true, Value: data}, err
+	case "DataAccessError":
+		access, err := n.enum(accessResultNames())
+		return GetDataResult{IsData: false, Value: AccessResultTag(access)}, err
+	default:
+		return GetDataResult{}, fmt.Errorf("unexpected %s in result", n.name())
+	}
+}
+
+func decodeAccessResultListXML(n *xmlNode, name string) ([]AccessResultTag, error) {
+	nodes, err := n.list(name)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]AccessResultTag, len(nodes))
+	for i := range nodes {
+		v, err := nodes[i].enum(accessResultNames())
+		if err != nil {
+			return nil, err
+		}
+		results[i] = AccessResultTag(v)
+	}
+
+	return results, nil
+}
+
+func accessResultListXML(name string, results []AccessResultTag) xmlNode {
+	nodes := make([]xmlNode, len(results))
+	for i, result := range results {
+		nodes[i] = xmlEnumNode("_DataAccessResult", uint8(result), accessResultNames())
+	}
+
+	return xmlListNode(name, nodes)
+}
+
+func actResponseXML(response ActResponse) (xmlNode, error) {
+	node := newXMLNode("SingleResponse", xmlEnumNode("Result", uint8(response.Result), actionResultNames()))
+	if response.ReturnParam != nil {
+		result, err := getDataResultXML(*response.ReturnParam)
+		if err != nil {
+			return xmlNode{}, err
+		}
+
+		node.Nodes = append(node.Nodes, newXMLNode("ReturnParameters", result))
+	}
+
+	return node, nil
+}
+
+func decodeActResponseXML(n *xmlNode) (response ActResponse, err error) {
+	result, err := n.enumValue("Result", actionResultNames())
+	if err != nil {
+		return
+	}
+	response.Result = ActionResultTag(result)
+
+	if parameters := n.optionalChild("ReturnParameters"); parameters != nil {
+		choice, err := parameters.choice()
+		if err != nil {
+			return response, err
+		}
+
+		param, err := decodeGetDataResultXML(choice)
+		if err != nil {
+			return response, err
+		}
+		response.ReturnParam = &param
+	}
+
+	return
+}
+
+// ---- GET
+
+func getRequestNormalXML(p GetRequestNormal) xmlNode {
+	return newXMLNode("GetRequestNormal", appendAccessSelectionXML([]xmlNode{
+		invokeIDXML(p.InvokePriority),
+		attributeDescriptorXML(p.AttributeInfo),
+	}, p.SelectiveAccessInfo)...)
+}
+
+func decodeGetRequestNormalXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p GetRequestNormal
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.AttributeInfo, err = decodeAttributeDescriptorXML(n); err != nil {
+		return
+	}
+
+	if p.SelectiveAccessInfo, err = decodeAccessSelectionXML(n); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func getRequestNextXML(p GetRequestNext) xmlNode {
+	return newXMLNode("GetRequestNext", invokeIDXML(p.InvokePriority), blockNumberXML(p.BlockNum))
+}
+
+func decodeGetRequestNextXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p GetRequestNext
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.BlockNum, err = decodeBlockNumberXML(n); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func getRequestWithListXML(p GetRequestWithList) xmlNode {
+	return newXMLNode("GetRequestWithList", invokeIDXML(p.InvokePriority), attributeDescriptorListXML(p.AttributeInfoList))
+}
+
+func decodeGetRequestWithListXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p GetRequestWithList
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.AttributeInfoList, err = decodeAttributeDescriptorListXML(n); err != nil {
+		return
+	}
+	p.AttributeCount = uint8(len(p.AttributeInfoList))
+
+	return p, nil
+}
+
+func getResponseNormalXML(p GetResponseNormal) (xmlNode, error) {
+	result, err := getDataResultXML(p.Result)
+	if err != nil {
+		return xmlNode{}, err
+	}
+
+	return newXMLNode("GetResponseNormal", invokeIDXML(p.InvokePriority), newXMLNode("Result", result)), nil
+}
+
+func decodeGetResponseNormalXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p GetResponseNormal
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	result, err := n.child("Result")
+	if err != nil {
+		return
+	}
+
+	choice, err := result.choice()
+	if err != nil {
+		return
+	}
+
+	if p.Result, err = decodeGetDataResultXML(choice); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func getResponseWithDataBlockXML(p GetResponseWithDataBlock) (xmlNode, error) {
+	var result xmlNode
+	if p.Result.IsResult {
+		access, ok := p.Result.Result.(AccessResultTag)
+		if !ok {
+			return xmlNode{}, fmt.Errorf("result must be AccessResultTag")
+		}
+
+		result = xmlEnumNode("DataAccessResult", uint8(access), accessResultNames())
+	} else {
+		raw, ok := p.Result.Result.([]byte)
+		if !ok {
+			return xmlNode{}, fmt.Errorf("result must be a byte slice")
+		}
+
+		result = xmlBytesNode("RawData", raw)
+	}
+
+	return newXMLNode("GetResponseWithDataBlock",
+		invokeIDXML(p.InvokePriority),
+		newXMLNode("Result",
+			xmlBoolNode("LastBlock", p.Result.LastBlock),
+			blockNumberXML(p.Result.BlockNumber),
+			newXMLNode("Result", result),
+		),
+	), nil
+}
+
+func decodeGetResponseWithDataBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p GetResponseWithDataBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	block, err := n.child("Result")
+	if err != nil {
+		return
+	}
+
+	if p.Result.LastBlock, err = block.boolValue("LastBlock"); err != nil {
+		return
+	}
+
+	if p.Result.BlockNumber, err = decodeBlockNumberXML(block); err != nil {
+		return
+	}
+
+	result, err := block.child("Result")
+	if err != nil {
+		return
+	}
+
+	choice, err := result.choice()
+	if err != nil {
+		return
+	}
+
+	switch choice.name() {
+	case "RawData":
+		p.Result.Result, err = choice.bytes()
+	case "DataAccessResult":
+		var access uint8
+		access, err = choice.enum(accessResultNames())
+		p.Result.IsResult = true
+		p.Result.Result = AccessResultTag(access)
+	default:
+		err = fmt.Errorf("unexpected %s in result", choice.name())
+	}
+
+	if err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func getResponseWithListXML(p GetResponseWithList) (xmlNode, error) {
+	nodes := make([]xmlNode, len(p.ResultList))
+	for i, result := range p.ResultList {
+		node, err := getDataResultXML(result)
+		if err != nil {
+			return xmlNode{}, err
+		}
+		nodes[i] = node
+	}
+
+	return newXMLNode("GetResponseWithList", invokeIDXML(p.InvokePriority), xmlListNode("Result", nodes)), nil
+}
+
+func decodeGetResponseWithListXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p GetResponseWithList
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	nodes, err := n.list("Result")
+	if err != nil {
+		return
+	}
+
+	p.ResultList = make([]GetDataResult, len(nodes))
+	for i := range nodes {
+		if p.ResultList[i], err = decodeGetDataResultXML(&nodes[i]); err != nil {
+			return
+		}
+	}
+	p.ResultCount = uint8(len(p.ResultList))
+
+	return p, nil
+}
+
+// ---- SET
+
+func setRequestNormalXML(p SetRequestNormal) xmlNode {
+	nodes := appendAccessSelectionXML([]xmlNode{
+		invokeIDXML(p.InvokePriority),
+		attributeDescriptorXML(p.AttributeInfo),
+	}, p.SelectiveAccessInfo)
+
+	return newXMLNode("SetRequestNormal", append(nodes, xmlDataNode("Value", p.Value))...)
+}
+
+func decodeSetRequestNormalXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetRequestNormal
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.AttributeInfo, err = decodeAttributeDescriptorXML(n); err != nil {
+		return
+	}
+
+	if p.SelectiveAccessInfo, err = decodeAccessSelectionXML(n); err != nil {
+		return
+	}
+
+	if p.Value, err = n.dataValue("Value"); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func setRequestWithFirstDataBlockXML(p SetRequestWithFirstDataBlock) xmlNode {
+	nodes := appendAccessSelectionXML([]xmlNode{
+		invokeIDXML(p.InvokePriority),
+		attributeDescriptorXML(p.AttributeInfo),
+	}, p.SelectiveAccessInfo)
+
+	return newXMLNode("SetRequestWithFirstDataBlock", append(nodes, dataBlockSAXML("DataBlock", p.DataBlock))...)
+}
+
+func decodeSetRequestWithFirstDataBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetRequestWithFirstDataBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.AttributeInfo, err = decodeAttributeDescriptorXML(n); err != nil {
+		return
+	}
+
+	if p.SelectiveAccessInfo, err = decodeAccessSelectionXML(n); err != nil {
+		return
+	}
+
+	if p.DataBlock, err = decodeDataBlockSAXML(n, "DataBlock"); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func setRequestWithDataBlockXML(p SetRequestWithDataBlock) xmlNode {
+	return newXMLNode("SetRequestWithDataBlock", invokeIDXML(p.InvokePriority), dataBlockSAXML("DataBlock", p.DataBlock))
+}
+
+func decodeSetRequestWithDataBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetRequestWithDataBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.DataBlock, err = decodeDataBlockSAXML(n, "DataBlock"); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func setRequestWithListXML(p SetRequestWithList) xmlNode {
+	return newXMLNode("SetRequestWithList",
+		invokeIDXML(p.InvokePriority),
+		attributeDescriptorListXML(p.AttributeInfoList),
+		xmlDataListNode("ValueList", p.ValueList),
+	)
+}
+
+func decodeSetRequestWithListXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetRequestWithList
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.AttributeInfoList, err = decodeAttributeDescriptorListXML(n); err != nil {
+		return
+	}
+	p.AttributeCount = uint8(len(p.AttributeInfoList))
+
+	values, err := n.child("ValueList")
+	if err != nil {
+		return
+	}
+
+	if p.ValueList, err = values.dataList(); err != nil {
+		return
+	}
+	p.ValueCount = uint8(len(p.ValueList))
+
+	return p, nil
+}
+
+func setRequestWithListAndFirstDataBlockXML(p SetRequestWithListAndFirstDataBlock) xmlNode {
+	return newXMLNode("SetRequestWithListAndFirstDataBlock",
+		invokeIDXML(p.InvokePriority),
+		attributeDescriptorListXML(p.AttributeInfoList),
+		dataBlockSAXML("DataBlock", p.DataBlock),
+	)
+}
+
+func decodeSetRequestWithListAndFirstDataBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetRequestWithListAndFirstDataBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.AttributeInfoList, err = decodeAttributeDescriptorListXML(n); err != nil {
+		return
+	}
+	p.AttributeCount = uint8(len(p.AttributeInfoList))
+
+	if p.DataBlock, err = decodeDataBlockSAXML(n, "DataBlock"); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func setResponseNormalXML(p SetResponseNormal) xmlNode {
+	return newXMLNode("SetResponseNormal",
+		invokeIDXML(p.InvokePriority),
+		xmlEnumNode("Result", uint8(p.Result), accessResultNames()),
+	)
+}
+
+func decodeSetResponseNormalXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetResponseNormal
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	result, err := n.enumValue("Result", accessResultNames())
+	if err != nil {
+		return
+	}
+	p.Result = AccessResultTag(result)
+
+	return p, nil
+}
+
+func setResponseDataBlockXML(p SetResponseDataBlock) xmlNode {
+	return newXMLNode("SetResponseDataBlock", invokeIDXML(p.InvokePriority), blockNumberXML(p.BlockNum))
+}
+
+func decodeSetResponseDataBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetResponseDataBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.BlockNum, err = decodeBlockNumberXML(n); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func setResponseLastDataBlockXML(p SetResponseLastDataBlock) xmlNode {
+	return newXMLNode("SetResponseLastDataBlock",
+		invokeIDXML(p.InvokePriority),
+		xmlEnumNode("Result", uint8(p.Result), accessResultNames()),
+		blockNumberXML(p.BlockNum),
+	)
+}
+
+func decodeSetResponseLastDataBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetResponseLastDataBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	result, err := n.enumValue("Result", accessResultNames())
+	if err != nil {
+		return
+	}
+	p.Result = AccessResultTag(result)
+
+	if p.BlockNum, err = decodeBlockNumberXML(n); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func setResponseLastDataBlockWithListXML(p SetResponseLastDataBlockWithList) xmlNode {
+	return newXMLNode("SetResponseLastDataBlockWithList",
+		invokeIDXML(p.InvokePriority),
+		accessResultListXML("Result", p.ResultList),
+		blockNumberXML(p.BlockNum),
+	)
+}
+
+func decodeSetResponseLastDataBlockWithListXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetResponseLastDataBlockWithList
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.ResultList, err = decodeAccessResultListXML(n, "Result"); err != nil {
+		return
+	}
+	p.ResultCount = uint8(len(p.ResultList))
+
+	if p.BlockNum, err = decodeBlockNumberXML(n); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func setResponseWithListXML(p SetResponseWithList) xmlNode {
+	return newXMLNode("SetResponseWithList", invokeIDXML(p.InvokePriority), accessResultListXML("Result", p.ResultList))
+}
+
+func decodeSetResponseWithListXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p SetResponseWithList
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.ResultList, err = decodeAccessResultListXML(n, "Result"); err != nil {
+		return
+	}
+	p.ResultCount = uint8(len(p.ResultList))
+
+	return p, nil
+}
+
+// ---- ACTION
+
+func actionRequestNormalXML(p ActionRequestNormal) xmlNode {
+	node := newXMLNode("ActionRequestNormal", invokeIDXML(p.InvokePriority), methodDescriptorXML(p.MethodInfo))
+	if p.MethodParam != nil {
+		node.Nodes = append(node.Nodes, xmlDataNode("MethodInvocationParameters", *p.MethodParam))
+	}
+
+	return node
+}
+
+func decodeActionRequestNormalXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionRequestNormal
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.MethodInfo, err = decodeMethodDescriptorXML(n); err != nil {
+		return
+	}
+
+	if parameters := n.optionalChild("MethodInvocationParameters"); parameters != nil {
+		data, err := parameters.singleData()
+		if err != nil {
+			return nil, err
+		}
+		p.MethodParam = &data
+	}
+
+	return p, nil
+}
+
+func actionRequestNextPBlockXML(p ActionRequestNextPBlock) xmlNode {
+	return newXMLNode("ActionRequestNextPBlock", invokeIDXML(p.InvokePriority), blockNumberXML(p.BlockNum))
+}
+
+func decodeActionRequestNextPBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionRequestNextPBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.BlockNum, err = decodeBlockNumberXML(n); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func actionRequestWithListXML(p ActionRequestWithList) xmlNode {
+	return newXMLNode("ActionRequestWithList",
+		invokeIDXML(p.InvokePriority),
+		methodDescriptorListXML(p.MethodInfoList),
+		xmlDataListNode("MethodInvocationParameters", p.MethodParamList),
+	)
+}
+
+func decodeActionRequestWithListXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionRequestWithList
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.MethodInfoList, err = decodeMethodDescriptorListXML(n); err != nil {
+		return
+	}
+	p.MethodInfoCount = uint8(len(p.MethodInfoList))
+
+	parameters, err := n.child("MethodInvocationParameters")
+	if err != nil {
+		return
+	}
+
+	if p.MethodParamList, err = parameters.dataList(); err != nil {
+		return
+	}
+	p.MethodParamCount = uint8(len(p.MethodParamList))
+
+	return p, nil
+}
+
+func actionRequestWithFirstPBlockXML(p ActionRequestWithFirstPBlock) xmlNode {
+	return newXMLNode("ActionRequestWithFirstPBlock",
+		invokeIDXML(p.InvokePriority),
+		methodDescriptorXML(p.MethodInfo),
+		dataBlockSAXML("PBlock", p.PBlock),
+	)
+}
+
+func decodeActionRequestWithFirstPBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionRequestWithFirstPBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.MethodInfo, err = decodeMethodDescriptorXML(n); err != nil {
+		return
+	}
+
+	if p.PBlock, err = decodeDataBlockSAXML(n, "PBlock"); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func actionRequestWithListAndFirstPBlockXML(p ActionRequestWithListAndFirstPBlock) xmlNode {
+	return newXMLNode("ActionRequestWithListAndFirstPBlock",
+		invokeIDXML(p.InvokePriority),
+		methodDescriptorListXML(p.MethodInfoList),
+		dataBlockSAXML("PBlock", p.PBlock),
+	)
+}
+
+func decodeActionRequestWithListAndFirstPBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionRequestWithListAndFirstPBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.MethodInfoList, err = decodeMethodDescriptorListXML(n); err != nil {
+		return
+	}
+	p.MethodInfoCount = uint8(len(p.MethodInfoList))
+
+	if p.PBlock, err = decodeDataBlockSAXML(n, "PBlock"); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func actionRequestWithPBlockXML(p ActionRequestWithPBlock) xmlNode {
+	return newXMLNode("ActionRequestWithPBlock", invokeIDXML(p.InvokePriority), dataBlockSAXML("PBlock", p.PBlock))
+}
+
+func decodeActionRequestWithPBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionRequestWithPBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.PBlock, err = decodeDataBlockSAXML(n, "PBlock"); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func actionResponseNormalXML(p ActionResponseNormal) (xmlNode, error) {
+	response, err := actResponseXML(p.Response)
+	if err != nil {
+		return xmlNode{}, err
+	}
+
+	return newXMLNode("ActionResponseNormal", invokeIDXML(p.InvokePriority), response), nil
+}
+
+func decodeActionResponseNormalXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionResponseNormal
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	response, err := n.child("SingleResponse")
+	if err != nil {
+		return
+	}
+
+	if p.Response, err = decodeActResponseXML(response); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func actionResponseWithPBlockXML(p ActionResponseWithPBlock) xmlNode {
+	return newXMLNode("ActionResponseWithPBlock", invokeIDXML(p.InvokePriority), dataBlockSAXML("PBlock", p.PBlock))
+}
+
+func decodeActionResponseWithPBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionResponseWithPBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.PBlock, err = decodeDataBlockSAXML(n, "PBlock"); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+func actionResponseWithListXML(p ActionResponseWithList) (xmlNode, error) {
+	nodes := make([]xmlNode, len(p.ResponseList))
+	for i, response := range p.ResponseList {
+		node, err := actResponseXML(response)
+		if err != nil {
+			return xmlNode{}, err
+		}
+		nodes[i] = node
+	}
+
+	return newXMLNode("ActionResponseWithList", invokeIDXML(p.InvokePriority), xmlListNode("ListOfResponses", nodes)), nil
+}
+
+func decodeActionResponseWithListXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionResponseWithList
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	nodes, err := n.list("ListOfResponses")
+	if err != nil {
+		return
+	}
+
+	p.ResponseList = make([]ActResponse, len(nodes))
+	for i := range nodes {
+		if p.ResponseList[i], err = decodeActResponseXML(&nodes[i]); err != nil {
+			return
+		}
+	}
+	p.ResponseCount = uint8(len(p.ResponseList))
+
+	return p, nil
+}
+
+func actionResponseNextPBlockXML(p ActionResponseNextPBlock) xmlNode {
+	return newXMLNode("ActionResponseNextPBlock", invokeIDXML(p.InvokePriority), blockNumberXML(p.BlockNum))
+}
+
+func decodeActionResponseNextPBlockXML(n *xmlNode) (pdu CosemPDU, err error) {
+	var p ActionResponseNextPBlock
+	if p.InvokePriority, err = decodeInvokeIDXML(n); err != nil {
+		return
+	}
+
+	if p.BlockNum, err = decodeBlockNumberXML(n); err != nil {
+		return
+	}
+
+	return p, nil
+}
+
+// ---- SN services
+
+func variableAccessXML(v VariableAccessSpecification) (xmlNode, error) {
+	switch v.Tag {
+	case TagVariableName:
+		return xmlUintNode("VariableName", uint64(v.VariableName), 2), nil
+	case TagParameterizedAccess:
+		return newXMLNode("ParameterizedAccess",
+			xmlUintNode("VariableName", uint64(v.VariableName), 2),
+			xmlUintNode("Selector", uint64(v.Selector), 1),
+			xmlDataNode("Parameter", v.Parameter),
+		), nil
+	case TagBlockNumberAccess:
+		return newXMLNode("BlockNumberAccess", xmlUintNode("BlockNumber", uint64(v.BlockNumber), 2)), nil
+	case TagReadDataBlockAccess:
+		return newXMLNode("ReadDataBlockAccess",
+			xmlBoolNode("LastBlock", v.LastBlock),
+			xmlUintNode("BlockNumber", uint64(v.BlockNumber), 2),
+			xmlBytesNode("RawData", v.RawData),
+		), nil
+	case TagWriteDataBlockAccess:
+		return newXMLNode("WriteDataBlockAccess",
+			xmlBoolNode("LastBlock", v.LastBlock),
+			xmlUintNode("BlockNumber", uint64(v.BlockNumber), 2),
+		), nil
+	default:
+		return xmlNode{}, fmt.Errorf("variable access tag not recognized (%v)", v.Tag)
+	}
+}
+
+func decodeVariableAccessXML(n *xmlNode) (v VariableAccessSpecification, err error) {
+	var value uint64
+	switch n.name() {
+	case "VariableName":
+		v.Tag = TagVariableName
+		value, err = n.uint(2)
+		v.VariableName = uint16(value)
+	case "ParameterizedAccess":
+		v.Tag = TagParameterizedAccess
+		if value, err = n.uintValue("VariableName", 2); err != nil {
+			return
+		}
+		v.VariableName = uint16(value)
+
+		if value, err = n.uintValue("Selector", 1); err != nil {
+			return
+		}
+		v.Selector = uint8(value)
+
+		v.Parameter, err = n.dataValue("Parameter")
+	case "BlockNumberAccess":
+		v.Tag = TagBlockNumberAccess
+		value, err = n.uintValue("BlockNumber", 2)
+		v.BlockNumber = uint16(value)
+	case "ReadDataBlockAccess", "WriteDataBlockAccess":
+		v.Tag = TagWriteDataBlockAccess
+		if v.LastBlock, err = n.boolValue("LastBlock"); err != nil {
+			return
+		}
+
+		if value, err = n.uintValue("BlockNumber", 2); err != nil {
+			return
+		}
+		v.BlockNumber = uint16(value)
+
+		if n.name() == "ReadDataBlockAccess" {
+			v.Tag = TagReadDataBlockAccess
+			v.RawData, err = n.bytesValue("RawData")
+		}
+	default:
+		err = fmt.Errorf("unexpected %s in variable access specification", n.name())
+	}
+
+	return
+}
+
+func variableAccessListXML(name string, variables []VariableAccessSpecification) (xmlNode, error) {
+	nodes := make([]xmlNode, len(variables))
+	for i, v := range variables {
+		node, err := variableAccessXML(v)
+		if err != nil {
+			return xmlNode{}, err
+		}
+		nodes[i] = node
+	}
+
+	return xmlListNode(name, nodes), nil
+}
+
+func decodeVariableAccessListXML(nodes []xmlNode) ([]VariableAccessSpecification, error) {
+	variables := make([]VariableAccessSpecification, len(nodes))
+	for i := range nodes {
+		v, err := decodeVariableAccessXML(&nodes[i])
+		if err != nil {
+			return nil, err
+		}
+		variables[i] = v
+	}
+
+	return variables, nil
+}
+
+func readRequestXML(p ReadRequest) (xmlNode, error) {
+	return variableAccessListXML("ReadRequest", p.Variables)
+}
+
+func decodeReadRequestXML(n *xmlNode) (CosemPDU, error) {
+	if err := n.checkQty(len(n.Nodes)); err != nil {
+		return nil, err
+	}
+
+	variables, err := decodeVariableAccessListXML(n.Nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadRequest{Variables: variables}, nil
+}
+
+func readResponseXML(p ReadResponse) (xmlNode, error) {
+	nodes := make([]xmlNode, len(p.Results))
+	for i, result := range p.Results {
+		switch result.Tag {
+		case TagReadResultData:
+			nodes[i] = xmlDataNode("Data", result.Data)
+		case TagReadResultDataAccessError:
+			nodes[i] = xmlEnumNode("DataAccessError", uint8(result.AccessError), accessResultNames())
+		case TagReadResultDataBlockResult:
+			nodes[i] = newXMLNode("DataBlockResult",
+				xmlBoolNode("LastBlock", result.DataBlock.LastBlock),
+				xmlUintNode("BlockNumber", uint64(result.DataBlock.BlockNumber), 2),
+				xmlBytesNode("RawData", result.DataBlock.RawData),
+			)
+		case TagReadResultBlockNumber:
+			nodes[i] = xmlUintNode("BlockNumber", uint64(result.BlockNumber), 2)
+		default:
+			return xmlNode{}, fmt.Errorf("read result tag not recognized (%v)", result.Tag)
+		}
+	}
+
+	return xmlListNode("ReadResponse", nodes), nil
+}
+
+func decodeReadResponseXML(n *xmlNode) (CosemPDU, error) {
+	if err := n.checkQty(len(n.Nodes)); err != nil {
+		return nil, err
+	}
+
+	results := make([]ReadResult, len(n.Nodes))
+	for i := range n.Nodes {
+		node := &n.Nodes[i]
+
+		var err error
+		var value uint64
+		switch node.name() {
+		case "Data":
+			results[i].Tag = TagReadResultData
+			results[i].Data, err = node.singleData()
+		case "DataAccessError":
+			results[i].Tag = TagReadResultDataAccessError
+			var access uint8
+			access, err = node.enum(accessResultNames())
+			results[i].AccessError = AccessResultTag(access)
+		case "DataBlockResult":
+			results[i].Tag = TagReadResultDataBlockResult
+			if results[i].DataBlock.LastBlock, err = node.boolValue("LastBlock"); err != nil {
+				return nil, err
+			}
+
+			if value, err = node.uintValue("BlockNumber", 2); err != nil {
+				return nil, err
+			}
+			results[i].DataBlock.BlockNumber = uint16(value)
+
+			results[i].DataBlock.RawData, err = node.bytesValue("RawData")
+		case "BlockNumber":
+			results[i].Tag = TagReadResultBlockNumber
+			value, err = node.uint(2)
+			results[i].BlockNumber = uint16(value)
+		default:
+			err = fmt.Errorf("unexpected %s in read response", node.name())
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ReadResponse{Results: results}, nil
+}
+
+func writeRequestXML(name string, variables []VariableAccessSpecification, data []axdr.DlmsData) (xmlNode, error) {
+	list, err := variableAccessListXML("ListOfVariableAccessSpecification", variables)
+	if err != nil {
+		return xmlNode{}, err
+	}
+
+	return newXMLNode(name, list, xmlDataListNode("ListOfData", data)), nil
+}
+
+func decodeWriteContentXML(n *xmlNode) (variables []VariableAccessSpecification, data []axdr.DlmsData, err error) {
+	nodes, err := n.list("ListOfVariableAccessSpecification")
+	if err != nil {
+		return
+	}
+
+	if variables, err = decodeVariableAccessListXML(nodes); err != nil {
+		return
+	}
+
+	list, err := n.child("ListOfData")
+	if err != nil {
+		return
+	}
+
+	data, err = list.dataList()
+
+	return
+}
+
+func decodeWriteRequestXML(n *xmlNode) (CosemPDU, error) {
+	variables, data, err := decodeWriteContentXML(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return WriteRequest{Variables: variables, Data: data}, nil
+}
+
+func decodeUnconfirmedWriteRequestXML(n *xmlNode) (CosemPDU, error) {
+	variables, data, err := decodeWriteContentXML(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return UnconfirmedWriteRequest{Variables: variables, Data: data}, nil
+}
+
+func writeResponseXML(p WriteResponse) (xmlNode, error) {
+	nodes := make([]xmlNode, len(p.Results))
+	for i, result := range p.Results {
+		switch result.Tag {
+		case TagWriteResultSuccess:
+			nodes[i] = newXMLNode("Success")
+		case TagWriteResultDataAccessError:
+			nodes[i] = xmlEnumNode("DataAccessError", uint8(result.AccessError), accessResultNames())
+		case TagWriteResultBlockNumber:
+			nodes[i] = xmlUintNode("BlockNumber", uint64(result.BlockNumber), 2)
+		default:
+			return xmlNode{}, fmt.Errorf("write result tag not recognized (%v)", result.Tag)
+		}
+	}
+
+	return xmlListNode("WriteResponse", nodes), nil
+}
+
+func decodeWriteResponseXML(n *xmlNode) (CosemPDU, error) {
+	if err := n.checkQty(len(n.Nodes)); err != nil {
+		return nil, err
+	}
+
+	results := make([]WriteResult, len(n.Nodes))
+	for i := range n.Nodes {
+		node := &n.Nodes[i]
+
+		switch node.name() {
+		case "Success":
+			results[i].Tag = TagWriteResultSuccess
+		case "DataAccessError":
+			access, err := node.enum(accessResultNames())
+			if err != nil {
+				return nil, err
+			}
+			results[i].Tag = TagWriteResultDataAccessError
+			results[i].AccessError = AccessResultTag(access)
+		case "BlockNumber":
+			value, err := node.uint(2)
+			if err != nil {
+				return nil, err
+			}
+			results[i].Tag = TagWriteResultBlockNumber
+			results[i].BlockNumber = uint16(value)
+		default:
+			return nil, fmt.Errorf("unexpected %s in write response", node.name())
+		}
+	}
+
+	return WriteResponse{Results: results}, nil
+}
+
+// ---- notifications and errors
+
+// appendDateTimeXML appends the node of the date-time, if any, as the contents of
+// its octet-string
+func appendDateTimeXML(nodes []xmlNode, name string, tm *time.Time) ([]xmlNode, error) {
+	if tm == nil {
+		return nodes, nil
+	}
+
+	dt, err := axdr.EncodeDateTime(*tm)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(nodes, xmlBytesNode(name, dt)), nil
+}
+
+func decodeDateTimeXML(n *xmlNode, name string) (*time.Time, error) {
+	node := n.optionalChild(name)
+	if node == nil {
+		return nil, nil
+	}
+
+	src, err := node.bytes()
+	if err != nil || len(src) == 0 {
+		return nil, err
+	}
+
+	if len(src) != 12 {
+		return nil, ErrWrongLength(len(src), 12)
+	}
+
+	_, tm, err := axdr.DecodeDateTime(&src)
+
+	return &tm, err
+}
+
+func dataNotificationXML(p DataNotification) (xmlNode, error) {
+	nodes, err := appendDateTimeXML([]xmlNode{xmlUintNode("LongInvokeIdAndPriority", uint64(p.InvokeIDAndPriority), 4)}, "DateTime", p.DateTime)
+	if err != nil {
+		return xmlNode{}, err
+	}
+
+	nodes = append(nodes, newXMLNode("NotificationBody", xmlDataNode("DataValue", p.DataValue)))
+
+	return newXMLNode("DataNotification", nodes...), nil
+}
+
+func decodeDataNotificationXML(n *xmlNode) (CosemPDU, error) {
+	var p DataNotification
+
+	invokeID, err := n.uintValue("LongInvokeIdAndPriority", 4)
+	if err != nil {
+		return nil, err
+	}
+	p.InvokeIDAndPriority = uint32(invokeID)
+
+	if p.DateTime, err = decodeDateTimeXML(n, "DateTime"); err != nil {
+		return nil, err
+	}
+
+	body, err := n.child("NotificationBody")
+	if err != nil {
+		return nil, err
+	}
+
+	if p.DataValue, err = body.dataValue("DataValue"); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func eventNotificationRequestXML(p EventNotificationRequest) (xmlNode, error) {
+	nodes, err := appendDateTimeXML(nil, "Time", p.Time)
+	if err != nil {
+		return xmlNode{}, err
+	}
+
+	nodes = append(nodes, attributeDescriptorXML(p.AttributeInfo), xmlDataNode("AttributeValue", p.AttributeValue))
+
+	return newXMLNode("EventNotificationRequest", nodes...), nil
+}
+
+func decodeEventNotificationRequestXML(n *xmlNode) (CosemPDU, error) {
+	var p EventNotificationRequest
+
+	var err error
+	if p.Time, err = decodeDateTimeXML(n, "Time"); err != nil {
+		return nil, err
+	}
+
+	if p.AttributeInfo, err = decodeAttributeDescriptorXML(n); err != nil {
+		return nil, err
+	}
+
+	if p.AttributeValue, err = n.dataValue("AttributeValue"); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func exceptionResponseXML(p ExceptionResponse) xmlNode {
+	return newXMLNode("ExceptionResponse",
+		xmlEnumNode("StateError", p.StateError.Value(), exceptionStateErrorNames()),
+		xmlEnumNode("ServiceError", p.ServiceError.Value(), exceptionServiceErrorNames()),
+	)
+}
+
+func decodeExceptionResponseXML(n *xmlNode) (CosemPDU, error) {
+	state, err := n.enumValue("StateError", exceptionStateErrorNames())
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := n.enumValue("ServiceError", exceptionServiceErrorNames())
+	if err != nil {
+		return nil, err
+	}
+
+	return ExceptionResponse{StateError: exceptionStateErrorTag(state), ServiceError: exceptionServiceErrorTag(service)}, nil
+}
+
+func confirmedServiceErrorXML(p ConfirmedServiceError) (xmlNode, error) {
+	service, ok := confirmedServiceErrorNames()[p.ConfirmedServiceError.Value()]
+	if !ok {
+		return xmlNode{}, fmt.Errorf("confirmed service error %d not recognized", p.ConfirmedServiceError)
+	}
+
+	serviceError, ok := serviceErrorNames()[p.ServiceError.Value()]
+	if !ok {
+		return xmlNode{}, fmt.Errorf("service error %d not recognized", p.ServiceError)
+	}
+
+	return newXMLNode("ConfirmedServiceError", newXMLNode(service, xmlUintNode(serviceError, uint64(p.Value), 1))), nil
+}
+
+func decodeConfirmedServiceErrorXML(n *xmlNode) (CosemPDU, error) {
+	service, err := n.choice()
+	if err != nil {
+		return nil, err
+	}
+
+	serviceTag, ok := nameOf(service.name(), confirmedServiceErrorNames())
+	if !ok {
+		return nil, fmt.Errorf("unknown confirmed service error %s", service.name())
+	}
+
+	serviceError, err := service.choice()
+	if err != nil {
+		return nil, err
+	}
+
+	errorTag, ok := nameOf(serviceError.name(), serviceErrorNames())
+	if !ok {
+		return nil, fmt.Errorf("unknown service error %s", serviceError.name())
+	}
+
+	value, err := serviceError.uint(1)
+	if err != nil {
+		return nil, err
+	}
+
+	return ConfirmedServiceError{
+		ConfirmedServiceError: confirmedServiceErrorTag(serviceTag),
+		ServiceError:          serviceErrorTag(errorTag),
+		Value:                 uint8(value),
+	}, nil
+}
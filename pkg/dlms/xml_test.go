@@ -0,0 +1,183 @@
+package dlms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+)
+
+func TestXML(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"ConfirmedServiceError", "0E010601"},
+		{"GetRequestNormal", "C0015100010100000300FF020102020406000000000600000005120000120000"},
+		{"GetRequestNext", "C0025100000002"},
+		{"GetRequestWithList", "C003450100010100000300FF020102020406000000000600000005120000120000"},
+		{"GetResponseNormal", "C40151000500000045"},
+		{"GetResponseWithDataBlock", "C402510100000001000C07D20C04030A060BFF007800"},
+		{"GetResponseWithList", "C40345020100000500000001"},
+		{"SetRequestNormal", "C1015100010100000300FF02010202040600000000060000000512000012000009050102030405"},
+		{"SetRequestWithFirstDataBlock", "C1025100010100000300FF0201020204060000000006000000051200001200000100000001050102030405"},
+		{"SetRequestWithDataBlock", "C103510100000001050102030405"},
+		{"SetRequestWithList", "C104450100010100000300FF0201020204060000000006000000051200001200000109050102030405"},
+		{"SetRequestWithListAndFirstDataBlock", "C105450100010100000300FF0201020204060000000006000000051200001200000100000001050102030405"},
+		{"SetResponseNormal", "C5015100"},
+		{"SetResponseDataBlock", "C5025100000001"},
+		{"SetResponseLastDataBlock", "C503510000000001"},
+		{"SetResponseLastDataBlockWithList", "C50451030001FA00000001"},
+		{"SetResponseWithList", "C50551030001FA"},
+		{"ActionRequestNormal", "C3015100010100000300FF020109050102030405"},
+		{"ActionRequestNextPBlock", "C3025100000001"},
+		{"ActionRequestWithList", "C303510100010100000300FF020109050102030405"},
+		{"ActionRequestWithFirstPBlock", "C3045100010100000300FF020100000001050102030405"},
+		{"ActionRequestWithListAndFirstPBlock", "C305510100010100000300FF020100000001050102030405"},
+		{"ActionRequestWithPBlock", "C306510100000001050102030405"},
+		{"ActionResponseNormal", "C7015100010100"},
+		{"ActionResponseWithPBlock", "C702510100000001050102030405"},
+		{"ActionResponseWithList", "C703510100010100"},
+		{"ActionResponseNextPBlock", "C7045100000001"},
+		{"ExceptionResponse", "D80102"},
+		{"ReadRequest", "050102FA00"},
+		{"ReadResponse", "0C010012003C"},
+		{"WriteRequest", "060102FA0801120005"},
+		{"WriteResponse", "0D0100"},
+		{"UnconfirmedWriteRequest", "160102FA0801120005"},
+		{"DataNotification", "0F0063D76A0C07E7011F02122217000000000301"},
+		{"DataNotificationWithoutDateTime", "0F0063D76A000301"},
+		{"EventNotificationRequest", "C2010C05DC0101010000000000000000010100000300FF020301"},
+		{"EventNotificationRequestWithoutTime", "C20000010100000300FF020301"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := decodeHexString(tt.src)
+			pdu, err := DecodeCosem(&src)
+			require.NoError(t, err)
+
+			out, err := EncodeXML(pdu)
+			require.NoError(t, err)
+
+			decoded, err := DecodeXML(out)
+			require.NoError(t, err, string(out))
+			assert.Equal(t, pdu, decoded, string(out))
+
+			expected, err := pdu.Encode()
+			require.NoError(t, err)
+
+			encoded, err := decoded.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, expected, encoded, string(out))
+		})
+	}
+}
+
+func TestEncodeXML(t *testing.T) {
+	src := decodeHexString("C0015100080000010000FF0200")
+	pdu, err := DecodeCosem(&src)
+	require.NoError(t, err)
+
+	normal := pdu.(GetRequestNormal)
+	out, err := EncodeXML(&normal)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"<GetRequest>",
+		"  <GetRequestNormal>",
+		`    <InvokeIdAndPriority Value="51"></InvokeIdAndPriority>`,
+		"    <AttributeDescriptor>",
+		`      <ClassId Value="0008"></ClassId>`,
+		`      <InstanceId Value="0000010000FF"></InstanceId>`,
+		`      <AttributeId Value="02"></AttributeId>`,
+		"    </AttributeDescriptor>",
+		"  </GetRequestNormal>",
+		"</GetRequest>",
+	}, "\n"), string(out))
+
+	src = decodeHexString("C40151010B")
+	pdu, err = DecodeCosem(&src)
+	require.NoError(t, err)
+
+	out, err = EncodeXML(pdu)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `<DataAccessError Value="ObjectUnavailable"></DataAccessError>`)
+
+	_, err = EncodeXML(nil)
+	assert.Error(t, err)
+}
+
+func TestDecodeXML(t *testing.T) {
+	// As given by other tools, with comments and empty elements
+	src := `
+<SetRequest>
+  <SetRequestNormal>
+    <!-- Priority: HIGH ServiceClass: CONFIRMED invokeID: 1 -->
+    <InvokeIdAndPriority Value="C1" />
+    <AttributeDescriptor>
+      <!-- CLOCK -->
+      <ClassId Value="0008" />
+      <!-- 0.0.1.0.0.255 -->
+      <InstanceId Value="0000010000FF" />
+      <AttributeId Value="02" />
+    </AttributeDescriptor>
+    <Value>
+      <OctetString Value="07E8030A0700000000FFFFFF" />
+    </Value>
+  </SetRequestNormal>
+</SetRequest>`
+
+	pdu, err := DecodeXML([]byte(src))
+	require.NoError(t, err)
+
+	out, err := pdu.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, decodeHexString("C101C100080000010000FF0200090C07E8030A0700000000FFFFFF"), out)
+
+	src = `
+<GetResponse>
+  <GetResponseWithList>
+    <InvokeIdAndPriority Value="45" />
+    <Result Qty="02">
+      <Data><LongUnsigned Value="00E6" /></Data>
+      <DataAccessError Value="ReadWriteDenied" />
+    </Result>
+  </GetResponseWithList>
+</GetResponse>`
+
+	pdu, err = DecodeXML([]byte(src))
+	require.NoError(t, err)
+	assert.Equal(t, *CreateGetResponseWithList(0x45, []GetDataResult{
+		*CreateGetDataResultAsData(*axdr.CreateAxdrLongUnsigned(230)),
+		*CreateGetDataResultAsResult(TagAccReadWriteDenied),
+	}), pdu)
+}
+
+func TestDecodeXMLFail(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"Invalid", "<GetRequest>"},
+		{"Unknown", "<InitiateRequest></InitiateRequest>"},
+		{"Empty service", "<GetRequest></GetRequest>"},
+		{"Missing element", `<GetRequestNext><InvokeIdAndPriority Value="C1"/></GetRequestNext>`},
+		{"Invalid value", `<GetRequestNext><InvokeIdAndPriority Value="C1C1"/><BlockNumber Value="00000001"/></GetRequestNext>`},
+		{"Missing value", `<GetRequestNext><InvokeIdAndPriority/><BlockNumber Value="00000001"/></GetRequestNext>`},
+		{"Invalid instance id", `<ActionRequestNormal><InvokeIdAndPriority Value="C1"/><MethodDescriptor><ClassId Value="0008"/><InstanceId Value="0000010000"/><MethodId Value="01"/></MethodDescriptor></ActionRequestNormal>`},
+		{"Wrong quantity", `<GetResponseWithList><InvokeIdAndPriority Value="C1"/><Result Qty="02"><DataAccessError Value="ReadWriteDenied"/></Result></GetResponseWithList>`},
+		{"Unknown result", `<SetResponseNormal><InvokeIdAndPriority Value="C1"/><Result Value="Unknown"/></SetResponseNormal>`},
+		{"Invalid data", `<GetResponseNormal><InvokeIdAndPriority Value="C1"/><Result><Data><Unsigned Value="0100"/></Data></Result></GetResponseNormal>`},
+		{"Two data", `<GetResponseNormal><InvokeIdAndPriority Value="C1"/><Result><Data><Unsigned Value="01"/><Unsigned Value="02"/></Data></Result></GetResponseNormal>`},
+		{"Unknown service error", `<ConfirmedServiceError><Read><Unknown Value="01"/></Read></ConfirmedServiceError>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeXML([]byte(tt.src))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package dlmsclient
 
 import (
+	"context"
 	"fmt"
 
 	"gitlab.com/circutor-library/gosem/pkg/axdr"
@@ -8,8 +9,18 @@ import (
 )
 
 func (c *client) ActionRequest(mth *dlms.MethodDescriptor, data interface{}) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	return c.ActionRequestContext(context.Background(), mth, data)
+}
+
+func (c *client) ActionRequestContext(ctx context.Context, mth *dlms.MethodDescriptor, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
+
+	if err := c.restoreAssociation(ctx); err != nil {
+		return err
+	}
 
 	if mth == nil {
 		return dlms.NewError(dlms.ErrorInvalidParameter, "method descriptor must be non-nil")
@@ -17,25 +28,25 @@ func (c *client) ActionRequest(mth *dlms.MethodDescriptor, data interface{}) (er
 
 	dt, ok := data.(*axdr.DlmsData)
 	if !ok {
-		dt, err = axdr.MarshalData(data)
+		dt, err = c.marshalData(data)
 		if err != nil {
 			return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("error marshaling %s data: %v", mth.String(), err))
 		}
 	}
 
-	invokeID := unicastInvokeID
-	if c.settings.UseBroadcast {
-		invokeID = broadcastInvokeID
+	invokeID := broadcastInvokeID
+	if !c.settings.UseBroadcast {
+		invokeID = c.nextInvokeID()
 	}
 
 	req := dlms.CreateActionRequestNormal(invokeID, *mth, dt)
 
-	pdu, err := c.encodeSendReceiveAndDecode(req)
+	pdu, err := c.encodeSendReceiveAndDecode(ctx, req)
 	if err != nil {
 		return
 	}
 
-	if pdu == nil && c.settings.UseBroadcast {
+	if pdu == nil && c.isUnanswered() {
 		return nil
 	}
 
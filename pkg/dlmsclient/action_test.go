@@ -35,19 +35,19 @@ func TestClient_ActionRequestFail(t *testing.T) {
 	assert.Equal(t, dlms.ErrorActionRejected, clientError.Code())
 
 	// Unexpected response
-	sendReceive(tm, rdc, "C301C10046000060030AFF01010F00", "0E010203")
+	sendReceive(tm, rdc, "C301C20046000060030AFF01010F00", "0E010203")
 	err = c.ActionRequest(disconnectorMethodDescriptor, data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Invalid response
-	sendReceive(tm, rdc, "C301C10046000060030AFF01010F00", "AE12")
+	sendReceive(tm, rdc, "C301C30046000060030AFF01010F00", "AE12")
 	err = c.ActionRequest(disconnectorMethodDescriptor, data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Send failed
-	tm.On("Send", decodeHexString("C301C10046000060030AFF01010F00")).Return(fmt.Errorf("error")).Once()
+	tm.On("Send", decodeHexString("C301C40046000060030AFF01010F00")).Return(fmt.Errorf("error")).Once()
 	tm.On("IsConnected").Return(false).Once()
 
 	err = c.ActionRequest(disconnectorMethodDescriptor, data)
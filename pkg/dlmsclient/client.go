@@ -1,6 +1,7 @@
 package dlmsclient
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"log"
@@ -12,7 +13,6 @@ import (
 )
 
 const (
-	unicastInvokeID   uint8 = 0xC1
 	broadcastInvokeID uint8 = 0x87
 )
 
@@ -22,30 +22,60 @@ type client struct {
 	replyTimeout       time.Duration
 	associationTimeout time.Duration
 	isAssociated       bool
+	wasAssociated      bool
+	associationID      uint64
+	conformance        uint32
+	reconnectPolicy    dlms.ReconnectPolicy
+	invokeID           uint8
 	timeoutTimer       *time.Timer
 	tc                 dlms.DataChannel
-	dc                 dlms.DataChannel
+	subs               []*subscription
+	window             int
 	notificationID     string
 	notificationChan   chan dlms.Notification
-	mutex              sync.Mutex
+	mutex              requestLock
+	sendMutex          sync.Mutex
+	stateMutex         sync.Mutex
 	subsMutex          sync.Mutex
+	scalers            map[string]dlms.ScalerUnit
+	scalersMutex       sync.Mutex
 	logger             *log.Logger
 }
 
-func New(settings dlms.Settings, transport dlms.Transport, replyTimeout time.Duration, associationTimeout time.Duration) dlms.Client {
+func New(settings dlms.Settings, transport dlms.Transport, replyTimeout time.Duration, associationTimeout time.Duration) dlms.ContextClient {
+	window := settings.MaxPendingRequests
+	if window < 1 {
+		window = 1
+	}
+
+	// Each request in flight needs its own invoke-id
+	if window > int(dlms.InvokeIDMask) {
+		window = int(dlms.InvokeIDMask)
+	}
+
 	c := &client{
 		settings:           settings,
 		transport:          transport,
 		replyTimeout:       replyTimeout,
 		associationTimeout: associationTimeout,
 		isAssociated:       false,
+		wasAssociated:      false,
+		associationID:      0,
+		conformance:        0,
+		reconnectPolicy:    dlms.ReconnectPolicy{},
+		invokeID:           0,
 		timeoutTimer:       nil,
 		tc:                 make(dlms.DataChannel, 10),
-		dc:                 nil,
+		subs:               nil,
+		window:             window,
 		notificationID:     "",
 		notificationChan:   nil,
-		mutex:              sync.Mutex{},
+		mutex:              newRequestLock(window),
+		sendMutex:          sync.Mutex{},
+		stateMutex:         sync.Mutex{},
 		subsMutex:          sync.Mutex{},
+		scalers:            make(map[string]dlms.ScalerUnit),
+		scalersMutex:       sync.Mutex{},
 		logger:             nil,
 	}
 
@@ -58,21 +88,42 @@ func New(settings dlms.Settings, transport dlms.Transport, replyTimeout time.Dur
 
 func (c *client) SetAddress(client int, server int) {
 	c.transport.SetAddress(client, server)
+
+	// Another device may answer now, so its scalers must be read again
+	c.clearScalers()
 }
 
 func (c *client) Connect() error {
-	c.mutex.Lock()
+	return c.ConnectContext(context.Background())
+}
+
+func (c *client) ConnectContext(ctx context.Context) error {
+	if err := c.mutex.LockContext(ctx); err != nil {
+		return err
+	}
 	defer c.mutex.Unlock()
 
-	err := c.transport.Connect()
+	return c.connect(ctx)
+}
+
+func (c *client) connect(ctx context.Context) error {
+	var err error
+	if twc, ok := c.transport.(dlms.TransportWithContext); ok {
+		err = twc.ConnectContext(ctx)
+	} else {
+		err = c.transport.Connect()
+	}
+
 	if err != nil {
+		if ctx.Err() != nil {
+			return dlms.ContextError(ctx.Err())
+		}
+
 		return dlms.NewError(dlms.ErrorCommunicationFailed, fmt.Sprintf("error connecting: %v", err))
 	}
 
 	if c.associationTimeout != 0 {
-		c.timeoutTimer = time.AfterFunc(c.associationTimeout, func() {
-			c.Disconnect()
-		})
+		c.timeoutTimer = time.AfterFunc(c.associationTimeout, c.expireAssociation)
 	}
 
 	return nil
@@ -82,7 +133,7 @@ func (c *client) Disconnect() error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.closeAssociation()
+	c.forgetAssociation()
 
 	err := c.transport.Disconnect()
 	if err != nil {
@@ -113,10 +164,16 @@ func (c *client) SetNotificationChannel(id string, nc chan dlms.Notification) {
 }
 
 func (c *client) GetSettings() dlms.Settings {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
 	return c.settings
 }
 
 func (c *client) SetSettings(settings dlms.Settings) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
 	c.settings = settings
 }
 
@@ -126,24 +183,40 @@ func (c *client) SetLogger(logger *log.Logger) {
 }
 
 func (c *client) Associate() error {
-	c.mutex.Lock()
+	return c.AssociateContext(context.Background())
+}
+
+func (c *client) AssociateContext(ctx context.Context) error {
+	if err := c.mutex.LockContext(ctx); err != nil {
+		return err
+	}
 	defer c.mutex.Unlock()
 
+	return c.associate(ctx)
+}
+
+func (c *client) associate(ctx context.Context) error {
 	if !c.transport.IsConnected() {
 		return dlms.NewError(dlms.ErrorInvalidState, "not connected")
 	}
 
+	c.stateMutex.Lock()
 	src, err := dlms.EncodeAARQ(&c.settings)
+	c.stateMutex.Unlock()
+
 	if err != nil {
 		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("error encoding AARQ: %v", err))
 	}
 
-	out, err := c.sendReceive(src)
+	out, err := c.sendReceive(ctx, src)
 	if err != nil {
 		return err
 	}
 
+	c.stateMutex.Lock()
 	aare, err := dlms.DecodeAARE(&c.settings, &out)
+	c.stateMutex.Unlock()
+
 	if err != nil {
 		er, eerr := dlms.DecodeExceptionResponse(&out)
 		if eerr == nil {
@@ -178,6 +251,10 @@ func (c *client) Associate() error {
 		return dlms.NewError(dlms.ErrorAuthenticationFailed, fmt.Sprintf("association failed: %d - %d", aare.AssociationResult, aare.SourceDiagnostic))
 	}
 
+	// The dispatcher reads the settings to decipher the replies of pipelined requests
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
 	if aare.ReceivedIC != nil {
 		if *aare.ReceivedIC < c.settings.Ciphering.UnicastExpectedIC {
 			return dlms.NewError(dlms.ErrorFailureInvocationCounter, fmt.Sprintf("wrong expected invocation counter: %d is lower than %d", *aare.ReceivedIC, c.settings.Ciphering.UnicastExpectedIC))
@@ -186,6 +263,8 @@ func (c *client) Associate() error {
 	}
 
 	if aare.InitiateResponse != nil {
+		c.conformance = aare.InitiateResponse.NegotiatedConformance
+
 		maxPduSendSize := int(aare.InitiateResponse.ServerMaxReceivePduSize)
 		if maxPduSendSize < c.settings.MaxPduSendSize {
 			c.settings.MaxPduSendSize = maxPduSendSize
@@ -193,23 +272,35 @@ func (c *client) Associate() error {
 	}
 
 	c.isAssociated = true
+	c.wasAssociated = true
+	c.associationID++
+
 	return nil
 }
 
 func (c *client) CloseAssociation() error {
-	c.mutex.Lock()
+	return c.CloseAssociationContext(context.Background())
+}
+
+func (c *client) CloseAssociationContext(ctx context.Context) error {
+	if err := c.mutex.LockContext(ctx); err != nil {
+		return err
+	}
 	defer c.mutex.Unlock()
 
 	if !c.transport.IsConnected() {
 		return dlms.NewError(dlms.ErrorInvalidState, "not connected")
 	}
 
+	c.stateMutex.Lock()
 	src, err := dlms.EncodeRLRQ(&c.settings)
+	c.stateMutex.Unlock()
+
 	if err != nil {
 		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("error encoding RLRQ: %v", err))
 	}
 
-	out, err := c.sendReceive(src)
+	out, err := c.sendReceive(ctx, src)
 	if err != nil {
 		return err
 	}
@@ -219,7 +310,7 @@ func (c *client) CloseAssociation() error {
 		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error decoding RLRE: %v", err))
 	}
 
-	c.closeAssociation()
+	c.forgetAssociation()
 
 	return nil
 }
@@ -229,10 +320,12 @@ func (c *client) IsAssociated() bool {
 	defer c.mutex.Unlock()
 
 	if !c.transport.IsConnected() {
-		c.isAssociated = false
+		c.closeAssociation()
 	}
 
-	return c.isAssociated
+	_, isAssociated, _ := c.associationState()
+
+	return isAssociated
 }
 
 func (c *client) manager() {
@@ -259,61 +352,195 @@ func (c *client) manager() {
 			}
 			c.subsMutex.Unlock()
 		} else {
-			c.subsMutex.Lock()
-			if c.dc != nil {
-				c.dc <- data
+			c.dispatch(data)
+		}
+	}
+}
+
+// dispatch routes a received frame to the request waiting for it. Serial requests
+// and the association receive the raw frame, while pipelined requests receive the
+// decoded PDU routed by its invoke-id.
+func (c *client) dispatch(data []byte) {
+	c.subsMutex.Lock()
+	count := len(c.subs)
+	isPipelined := count > 0 && c.subs[0].decoded
+	if !isPipelined {
+		for _, s := range c.subs {
+			s.deliver(reply{data: data})
+		}
+	}
+	c.subsMutex.Unlock()
+
+	if count == 0 && c.logger != nil {
+		c.logger.Printf("Discarded unexpected frame: %s", encodeHexString(data))
+	}
+
+	if !isPipelined {
+		return
+	}
+
+	var pdu dlms.CosemPDU
+	var err error
+	if !isACSE(data) {
+		pdu, err = c.decodeResponse(data)
+	}
+	invokeID, hasInvokeID := dlms.GetInvokeID(pdu)
+
+	c.subsMutex.Lock()
+	defer c.subsMutex.Unlock()
+
+	// Frames without invoke-id (as the AARE or the RLRE) go to the request waiting
+	// for the raw frame, if any
+	if !hasInvokeID {
+		for _, s := range c.subs {
+			if !s.decoded {
+				s.deliver(reply{data: data})
+				return
 			}
-			c.subsMutex.Unlock()
 		}
 	}
+
+	for _, s := range c.subs {
+		// Frames that cannot be matched can only be given to a lone request
+		if (hasInvokeID && s.invokeID == invokeID) || (!hasInvokeID && len(c.subs) == 1) {
+			s.deliver(reply{data: data, pdu: pdu, err: err})
+			return
+		}
+	}
+
+	if c.logger != nil {
+		c.logger.Printf("Discarded stale frame with invoke-id %d: %s", invokeID, encodeHexString(data))
+	}
 }
 
-func (c *client) sendReceive(src []byte) ([]byte, error) {
-	c.subscribe()
-	defer c.unsubscribe()
+// isACSE returns whether the frame is an association control APDU, which is
+// never ciphered as the xDLMS ones.
+func isACSE(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+
+	switch dlms.CosemTag(data[0]) {
+	case dlms.TagAARE, dlms.TagRLRE:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *client) sendReceive(ctx context.Context, src []byte) ([]byte, error) {
+	s := c.subscribe(0, false)
+	defer c.unsubscribe(s)
+
+	err := c.send(ctx, src)
+	if err != nil || c.settings.UseBroadcast {
+		return nil, err
+	}
+
+	// Wait for the device response
+	timeout := time.NewTimer(c.replyTimeout)
+	defer timeout.Stop()
+
+	r, err := c.receive(ctx, s, timeout.C)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.data, nil
+}
+
+// send sends the frame to the transport. The caller must be subscribed beforehand
+// so the reply cannot be lost.
+func (c *client) send(ctx context.Context, src []byte) error {
+	if err := ctx.Err(); err != nil {
+		return dlms.ContextError(err)
+	}
 
 	if c.settings.UseBroadcast {
 		if twb, ok := c.transport.(dlms.TransportWithBroadcast); ok {
-			return nil, twb.SendBroadcast(src)
+			return twb.SendBroadcast(src)
 		}
 	}
 
 	err := c.transport.Send(src)
 	if err != nil {
-		return nil, dlms.NewError(dlms.ErrorCommunicationFailed, fmt.Sprintf("error sending AARQ: %v", err))
+		return dlms.NewError(dlms.ErrorCommunicationFailed, fmt.Sprintf("error sending AARQ: %v", err))
 	}
 
-	if c.settings.UseBroadcast {
-		return nil, nil
+	return nil
+}
+
+func (c *client) receive(ctx context.Context, s *subscription, timeout <-chan time.Time) (reply, error) {
+	select {
+	case r := <-s.ch:
+		return r, nil
+	case <-timeout:
+		return reply{}, dlms.NewError(dlms.ErrorCommunicationFailed, "timeout reached")
+	case <-ctx.Done():
+		return reply{}, dlms.ContextError(ctx.Err())
 	}
+}
 
-	// Wait for the device response
-	timeout := time.NewTimer(c.replyTimeout)
-	defer timeout.Stop()
+// reply is a frame received for a request. If pdu or err are set, the frame has
+// already been deciphered and decoded by the dispatcher.
+type reply struct {
+	data []byte
+	pdu  dlms.CosemPDU
+	err  error
+}
 
+type subscription struct {
+	invokeID uint8
+	decoded  bool
+	ch       chan reply
+	done     chan struct{}
+}
+
+// deliver waits until the requester takes the reply, so none is lost when several
+// arrive together. It returns at once if the requester has already given up.
+func (s *subscription) deliver(r reply) {
 	select {
-	case data := <-c.dc:
-		return data, nil
-	case <-timeout.C:
-		return nil, dlms.NewError(dlms.ErrorCommunicationFailed, "timeout reached")
+	case s.ch <- r:
+	case <-s.done:
 	}
 }
 
-func (c *client) subscribe() {
+// subscribe registers a request waiting for a reply. If decoded is true, replies are
+// decoded by the dispatcher and matched against the invoke-id.
+func (c *client) subscribe(invokeID uint8, decoded bool) *subscription {
 	c.subsMutex.Lock()
 	defer c.subsMutex.Unlock()
 
-	c.dc = make(dlms.DataChannel)
+	s := &subscription{
+		invokeID: invokeID,
+		decoded:  decoded,
+		ch:       make(chan reply, 1),
+		done:     make(chan struct{}),
+	}
+	c.subs = append(c.subs, s)
+
+	return s
 }
 
-func (c *client) unsubscribe() {
+func (c *client) unsubscribe(s *subscription) {
+	// Release the dispatcher first, as it may be waiting to deliver while holding the lock
+	close(s.done)
+
 	c.subsMutex.Lock()
 	defer c.subsMutex.Unlock()
 
-	c.dc = nil
+	for i, v := range c.subs {
+		if v == s {
+			c.subs = append(c.subs[:i], c.subs[i+1:]...)
+			break
+		}
+	}
 }
 
-func (c *client) encodeSendReceiveAndDecode(req dlms.CosemPDU) (dlms.CosemPDU, error) {
+func (c *client) encodeRequest(req dlms.CosemPDU) ([]byte, error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
 	if !c.isAssociated {
 		return nil, dlms.NewError(dlms.ErrorInvalidState, "client is not associated")
 	}
@@ -334,23 +561,154 @@ func (c *client) encodeSendReceiveAndDecode(req dlms.CosemPDU) (dlms.CosemPDU, e
 		}
 	}
 
-	out, err := c.sendReceive(src)
+	return src, nil
+}
+
+func (c *client) encodeAndSend(req dlms.CosemPDU) error {
+	src, err := c.encodeRequest(req)
+	if err != nil {
+		return err
+	}
+
+	err = c.transport.Send(src)
 	if err != nil {
 		if !c.transport.IsConnected() {
 			c.closeAssociation()
 		}
 
-		return nil, err
+		return dlms.NewError(dlms.ErrorCommunicationFailed, fmt.Sprintf("error sending PDU: %v", err))
 	}
 
 	if c.timeoutTimer != nil {
 		c.timeoutTimer.Reset(c.associationTimeout)
 	}
 
-	if out == nil && c.settings.UseBroadcast {
+	return nil
+}
+
+// nextInvokeID rotates the invoke-id and returns the Invoke-Id-And-Priority field
+// for a new request. Invoke-ids of the requests still in flight are skipped.
+func (c *client) nextInvokeID() uint8 {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	c.subsMutex.Lock()
+	defer c.subsMutex.Unlock()
+
+	for i := 0; i <= int(dlms.InvokeIDMask); i++ {
+		c.invokeID = (c.invokeID + 1) & dlms.InvokeIDMask
+
+		if !c.isInvokeIDPending(c.invokeID) {
+			break
+		}
+	}
+
+	return c.settings.InvokeIDAndPriority(c.invokeID)
+}
+
+func (c *client) isInvokeIDPending(invokeID uint8) bool {
+	for _, s := range c.subs {
+		if s.decoded && s.invokeID == invokeID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isUnanswered returns whether the server does not answer the requests, as they
+// are broadcast or unconfirmed.
+func (c *client) isUnanswered() bool {
+	return c.settings.UseBroadcast || c.settings.ServiceClass == dlms.ServiceClassUnconfirmed
+}
+
+// checkAnswered returns an error if the server does not answer the requests, as
+// happens with unconfirmed or broadcast requests, for services that need the response.
+func (c *client) checkAnswered(name string) error {
+	if c.isUnanswered() {
+		return dlms.NewError(dlms.ErrorInvalidState, fmt.Sprintf("cannot get %s without a response from the server", name))
+	}
+
+	return nil
+}
+
+func (c *client) encodeSendReceiveAndDecode(ctx context.Context, req dlms.CosemPDU) (dlms.CosemPDU, error) {
+	invokeID, hasInvokeID := dlms.GetInvokeID(req)
+
+	// Ciphered requests must reach the server in the same order their invocation
+	// counters were assigned
+	c.sendMutex.Lock()
+
+	src, err := c.encodeRequest(req)
+	if err != nil {
+		c.sendMutex.Unlock()
+		return nil, err
+	}
+
+	s := c.subscribe(invokeID, hasInvokeID && c.window > 1)
+	defer c.unsubscribe(s)
+
+	err = c.send(ctx, src)
+	c.sendMutex.Unlock()
+
+	if err == nil && c.isUnanswered() {
+		// No response is expected
+		if c.timeoutTimer != nil {
+			c.timeoutTimer.Reset(c.associationTimeout)
+		}
+
 		return nil, nil
 	}
 
+	// Wait for the device response. Frames answering other requests do not extend the timeout.
+	timeout := time.NewTimer(c.replyTimeout)
+	defer timeout.Stop()
+
+	for err == nil {
+		var r reply
+
+		r, err = c.receive(ctx, s, timeout.C)
+		if err != nil {
+			break
+		}
+
+		if c.timeoutTimer != nil {
+			c.timeoutTimer.Reset(c.associationTimeout)
+		}
+
+		pdu := r.pdu
+		if pdu == nil && r.err == nil {
+			pdu, r.err = c.decodeResponse(r.data)
+		}
+
+		if r.err != nil {
+			return nil, r.err
+		}
+
+		if rid, ok := dlms.GetInvokeID(pdu); hasInvokeID && ok && rid != invokeID {
+			if c.logger != nil {
+				c.logger.Printf("Discarded stale frame with invoke-id %d (expected %d): %s", rid, invokeID, encodeHexString(r.data))
+			}
+
+			continue
+		}
+
+		return pdu, nil
+	}
+
+	if !c.transport.IsConnected() {
+		c.closeAssociation()
+	}
+
+	return nil, err
+}
+
+func (c *client) decodeResponse(out []byte) (dlms.CosemPDU, error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	var err error
+
 	if c.settings.Ciphering.Level != dlms.SecurityLevelNone {
 		out, err = c.decipherData(out)
 		if err != nil {
@@ -377,7 +735,14 @@ func encodeHexString(b []byte) string {
 
 func (c *client) cipherData(src []byte) ([]byte, error) {
 	tag := dlms.CosemTag(src[0])
-	if tag != dlms.TagGetRequest && tag != dlms.TagSetRequest && tag != dlms.TagActionRequest {
+	switch tag {
+	case dlms.TagGetRequest, dlms.TagSetRequest, dlms.TagActionRequest:
+	case dlms.TagReadRequest, dlms.TagWriteRequest, dlms.TagUnconfirmedWriteRequest:
+		// SN services have no dedicated ciphered PDUs
+		if c.settings.Ciphering.Level != dlms.SecurityLevelGlobalKey {
+			return nil, fmt.Errorf("tag %d can only be ciphered with the global key", tag)
+		}
+	default:
 		return nil, fmt.Errorf("unexpected tag %d", tag)
 	}
 
@@ -395,6 +760,12 @@ func (c *client) cipherData(src []byte) ([]byte, error) {
 			cipher.Tag = dlms.TagGloSetRequest
 		case dlms.TagActionRequest:
 			cipher.Tag = dlms.TagGloActionRequest
+		case dlms.TagReadRequest:
+			cipher.Tag = dlms.TagGloReadRequest
+		case dlms.TagWriteRequest:
+			cipher.Tag = dlms.TagGloWriteRequest
+		case dlms.TagUnconfirmedWriteRequest:
+			cipher.Tag = dlms.TagGloUnconfirmedWriteRequest
 		}
 
 		if len(c.settings.Ciphering.UnicastKey) != 16 {
@@ -461,9 +832,23 @@ func (c *client) decipherData(src []byte) ([]byte, error) {
 }
 
 func (c *client) closeAssociation() {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
 	c.isAssociated = false
 	if c.timeoutTimer != nil {
 		c.timeoutTimer.Stop()
 		c.timeoutTimer = nil
 	}
 }
+
+// forgetAssociation closes the association so the reconnect policy does not
+// establish it again.
+func (c *client) forgetAssociation() {
+	c.closeAssociation()
+
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	c.wasAssociated = false
+}
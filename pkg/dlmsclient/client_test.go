@@ -1,8 +1,12 @@
 package dlmsclient_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/hex"
 	"fmt"
+	"log"
+	"sync"
 	"testing"
 	"time"
 
@@ -443,6 +447,59 @@ func TestClient_NotificationWithGet(t *testing.T) {
 	tm.AssertExpectations(t)
 }
 
+func TestClient_InvokeIDRotation(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	// The invoke-id wraps around after 15
+	for i := 1; i <= 16; i++ {
+		id := fmt.Sprintf("%02X", 0xC0|(i&0x0F))
+		sendReceive(tm, rdc, "C001"+id+"00080000010000FF0300", "C401"+id+"0010003C")
+		assert.NoError(t, c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 3), nil))
+	}
+
+	// Priority and service class are taken from the settings
+	settings := c.GetSettings()
+	settings.Priority = dlms.PriorityNormal
+	c.SetSettings(settings)
+
+	sendReceive(tm, rdc, "C0014100080000010000FF0300", "C401410010003C")
+	assert.NoError(t, c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 3), nil))
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_DiscardStaleResponse(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	var buf bytes.Buffer
+	tm.On("SetLogger", mock.Anything).Once()
+	c.SetLogger(log.New(&buf, "", 0))
+
+	// The first request is abandoned before the meter answers
+	tm.On("Send", decodeHexString("C001C100080000010000FF0300")).Return(nil).Once()
+	tm.On("IsConnected").Return(true).Once()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := c.GetRequestContext(ctx, dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 3), nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Its late reply arrives while waiting for the next one, and must be discarded
+	tm.On("Send", decodeHexString("C001C200080000010000FF0200")).Run(func(_ mock.Arguments) {
+		rdc <- decodeHexString("C401C10010003C")
+		rdc <- decodeHexString("C401C2000600000005")
+	}).Return(nil).Once()
+
+	var value uint32
+	err = c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), &value)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), value)
+	assert.Contains(t, buf.String(), "Discarded stale frame with invoke-id 1 (expected 2): C401C10010003C")
+
+	tm.AssertExpectations(t)
+}
+
 func TestClient_CompleteCommunication(t *testing.T) {
 	tm := mocks.NewTransportMock(t)
 
@@ -510,7 +567,7 @@ func TestClient_CompleteSecureCommunication(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Get fails due failure invocation counter (two replies with the same invocation counter)
-	sendReceive(tm, rdc, "D01E3000000002CDA00847BC0032D323FB29C26A51683A141E2CE1C14FDEB851", "D4233000000001AA07A549F82E6B8EEA919659D91689BF995BE6F93C95A7208718A3B84EE4")
+	sendReceive(tm, rdc, "D01E3000000002CDA00B47BC0032D323FB29C26AF5D9AE57298DB997B8900EA7", "D4233000000001AA07A549F82E6B8EEA919659D91689BF995BE6F93C95A7208718A3B84EE4")
 	err = c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), nil)
 	var clientError *dlms.Error
 	assert.ErrorAs(t, err, &clientError)
@@ -537,3 +594,58 @@ func decodeHexString(s string) []byte {
 	b, _ := hex.DecodeString(s)
 	return b
 }
+
+func TestClient_PipelinedRequests(t *testing.T) {
+	settings, _ := dlms.NewSettingsWithoutAuthentication()
+	settings.MaxPendingRequests = 4
+
+	c, tm, rdc := associateWithSettings(t, settings)
+
+	// The meter answers once the three requests are in flight, in reverse order
+	var mutex sync.Mutex
+	requests := make([][]byte, 0)
+
+	tm.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		requests = append(requests, args.Get(0).([]byte))
+		if len(requests) < 3 {
+			return
+		}
+
+		for i := len(requests) - 1; i >= 0; i-- {
+			req := requests[i]
+			rdc <- []byte{0xC4, 0x01, req[2], 0x00, 0x06, 0x00, 0x00, 0x00, req[9]}
+		}
+	}).Return(nil).Times(3)
+
+	var wg sync.WaitGroup
+	values := make([]uint32, 3)
+	errs := make([]error, 3)
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			obis := fmt.Sprintf("1-0:1.8.%d.255", i+1)
+			errs[i] = c.GetRequest(dlms.CreateAttributeDescriptor(3, obis, 2), &values[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, uint32(i+1), values[i])
+	}
+
+	// Every request used its own invoke-id
+	assert.NotEqual(t, requests[0][2], requests[1][2])
+	assert.NotEqual(t, requests[1][2], requests[2][2])
+	assert.NotEqual(t, requests[0][2], requests[2][2])
+
+	tm.AssertExpectations(t)
+}
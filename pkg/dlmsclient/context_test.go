@@ -0,0 +1,128 @@
+package dlmsclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+	"gitlab.com/circutor-library/gosem/pkg/dlms/mocks"
+	"gitlab.com/circutor-library/gosem/pkg/dlmsclient"
+)
+
+func TestClient_ConnectContextCanceled(t *testing.T) {
+	tm := mocks.NewTransportMock(t)
+	tm.On("SetReception", mock.Anything).Once()
+	settings, _ := dlms.NewSettingsWithoutAuthentication()
+	c := dlmsclient.New(settings, tm, 5*time.Second, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.ConnectContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorCanceled, clientError.Code())
+
+	tm.AssertNotCalled(t, "Connect")
+}
+
+func TestClient_GetRequestContextCanceled(t *testing.T) {
+	c, tm, _ := associate(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var data uint32
+	err := c.GetRequestContext(ctx, dlms.CreateAttributeDescriptor(3, "1-0:1.8.0.255", 2), &data)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_GetRequestContextCanceledDuringDataBlock(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The user gives up after receiving the first block: the next one must not be requested
+	tm.On("Send", decodeHexString("C001C100070100630100FF0200")).Run(func(_ mock.Arguments) {
+		rdc <- decodeHexString("C402C10000000001000C010506000000010600000002")
+		cancel()
+	}).Return(nil).Once()
+	tm.On("IsConnected").Return(true).Once()
+
+	var data []uint32
+	err := c.GetRequestContext(ctx, dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), &data)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorCanceled, clientError.Code())
+
+	tm.AssertExpectations(t)
+	tm.AssertNumberOfCalls(t, "Send", 2)
+
+}
+
+func TestClient_GetRequestContextDeadline(t *testing.T) {
+	c, tm, _ := associate(t)
+
+	// The meter never answers, but the deadline is shorter than the reply timeout
+	tm.On("Send", decodeHexString("C001C100030100010800FF0200")).Return(nil).Once()
+	tm.On("IsConnected").Return(true).Once()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	var data uint32
+	err := c.GetRequestContext(ctx, dlms.CreateAttributeDescriptor(3, "1-0:1.8.0.255", 2), &data)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Second)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_SetRequestContextCanceledDuringDataBlock(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	settings := c.GetSettings()
+	settings.MaxPduSendSize = 30
+	c.SetSettings(settings)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tm.On("Send", mock.Anything).Run(func(_ mock.Arguments) {
+		rdc <- decodeHexString("C502C10000000001")
+		cancel()
+	}).Return(nil).Once()
+	tm.On("IsConnected").Return(true).Once()
+
+	data := make([]byte, 64)
+	err := c.SetRequestContext(ctx, dlms.CreateAttributeDescriptor(1, "0-0:96.1.0.255", 2), data)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	tm.AssertExpectations(t)
+	tm.AssertNumberOfCalls(t, "Send", 2)
+}
+
+func TestClient_UnconfirmedWriteRequestContextCanceled(t *testing.T) {
+	c, tm, _ := associateShortName(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.UnconfirmedWriteRequestContext(ctx, 0xFA08, uint16(5))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	tm.AssertExpectations(t)
+}
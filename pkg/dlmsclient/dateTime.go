@@ -0,0 +1,48 @@
+package dlmsclient
+
+import (
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// timeZone returns the convention of the meter for the deviation of date-times
+func (c *client) timeZone() axdr.TimeZone {
+	if c.settings.TimeZone != nil {
+		return *c.settings.TimeZone
+	}
+
+	return axdr.TimeZoneDeviation //nolint:staticcheck // default when the settings have no time zone
+}
+
+func (c *client) marshalData(data interface{}) (*axdr.DlmsData, error) {
+	return axdr.MarshalDataWithTimeZone(data, c.timeZone())
+}
+
+func (c *client) unmarshalData(data axdr.DlmsData, v interface{}) error {
+	return axdr.UnmarshalDataWithTimeZone(data, v, c.timeZone())
+}
+
+// rangeDescriptor returns the range descriptor with its date-times encoded with the
+// time zone of the meter.
+func (c *client) rangeDescriptor(rd dlms.RangeDescriptor) dlms.RangeDescriptor {
+	if from, ok := rd.From.(time.Time); ok {
+		rd.From = axdr.NewCosemDateTime(from, c.timeZone())
+	}
+
+	if to, ok := rd.To.(time.Time); ok {
+		rd.To = axdr.NewCosemDateTime(to, c.timeZone())
+	}
+
+	return rd
+}
+
+func (c *client) selectiveAccessByDate(start time.Time, end time.Time, values []dlms.AttributeDescriptor) (*dlms.SelectiveAccessDescriptor, error) {
+	rd := dlms.RangeDescriptor{RestrictingObject: dlms.ClockRestrictingObject(), From: start, To: end}
+	for _, v := range values {
+		rd.Columns = append(rd.Columns, dlms.SelectedValue{Attribute: v})
+	}
+
+	return dlms.CreateSelectiveAccessByRange(c.rangeDescriptor(rd))
+}
@@ -1,10 +1,11 @@
 package dlmsclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
-	"strconv"
 	"strings"
 	"time"
 
@@ -13,84 +14,188 @@ import (
 )
 
 func (c *client) GetRequest(att *dlms.AttributeDescriptor, data interface{}) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	return c.GetRequestContext(context.Background(), att, data)
+}
+
+func (c *client) GetRequestContext(ctx context.Context, att *dlms.AttributeDescriptor, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
 
-	return c.getRequestWithUnmarshal(att, nil, data)
+	return c.withReconnect(ctx, func() error {
+		return c.getRequestWithUnmarshal(ctx, att, nil, data)
+	})
 }
 
 func (c *client) GetRequestWithSelectiveAccess(att *dlms.AttributeDescriptor, selectiveAccess axdr.DlmsData, data interface{}) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	return c.GetRequestWithSelectiveAccessContext(context.Background(), att, selectiveAccess, data)
+}
+
+func (c *client) GetRequestWithSelectiveAccessContext(ctx context.Context, att *dlms.AttributeDescriptor, selectiveAccess axdr.DlmsData, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
 
 	acc := &dlms.SelectiveAccessDescriptor{AccessSelector: dlms.AccessSelectorRange, AccessParameter: selectiveAccess}
-	return c.getRequestWithUnmarshal(att, acc, data)
+	return c.withReconnect(ctx, func() error {
+		return c.getRequestWithUnmarshal(ctx, att, acc, data)
+	})
+}
+
+func (c *client) GetRequestWithRange(att *dlms.AttributeDescriptor, rd dlms.RangeDescriptor, data interface{}) (err error) {
+	return c.GetRequestWithRangeContext(context.Background(), att, rd, data)
+}
+
+func (c *client) GetRequestWithRangeContext(ctx context.Context, att *dlms.AttributeDescriptor, rd dlms.RangeDescriptor, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
+
+	acc, err := dlms.CreateSelectiveAccessByRange(c.rangeDescriptor(rd))
+	if err != nil {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid range descriptor: %v", err))
+	}
+
+	return c.withReconnect(ctx, func() error {
+		return c.getRequestWithUnmarshal(ctx, att, acc, data)
+	})
+}
+
+func (c *client) GetRequestWithEntries(att *dlms.AttributeDescriptor, ed dlms.EntryDescriptor, data interface{}) (err error) {
+	return c.GetRequestWithEntriesContext(context.Background(), att, ed, data)
+}
+
+func (c *client) GetRequestWithEntriesContext(ctx context.Context, att *dlms.AttributeDescriptor, ed dlms.EntryDescriptor, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
+
+	if ed.ToEntry != 0 && ed.ToEntry < ed.FromEntry {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid entries: from %d to %d", ed.FromEntry, ed.ToEntry))
+	}
+
+	if ed.ToColumn != 0 && ed.ToColumn < ed.FromColumn {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid columns: from %d to %d", ed.FromColumn, ed.ToColumn))
+	}
+
+	acc, err := dlms.CreateSelectiveAccessByEntry(ed)
+	if err != nil {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid entries: %v", err))
+	}
+
+	return c.withReconnect(ctx, func() error {
+		return c.getRequestWithUnmarshal(ctx, att, acc, data)
+	})
 }
 
 func (c *client) GetRequestWithSelectiveAccessByDate(att *dlms.AttributeDescriptor, start time.Time, end time.Time, data interface{}) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	return c.GetRequestWithSelectiveAccessByDateContext(context.Background(), att, start, end, data)
+}
+
+func (c *client) GetRequestWithSelectiveAccessByDateContext(ctx context.Context, att *dlms.AttributeDescriptor, start time.Time, end time.Time, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
 
-	acc := dlms.CreateSelectiveAccessByRangeDescriptor(start, end, nil)
-	return c.getRequestWithUnmarshal(att, acc, data)
+	acc, err := c.selectiveAccessByDate(start, end, nil)
+	if err != nil {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid dates: %v", err))
+	}
+
+	return c.withReconnect(ctx, func() error {
+		return c.getRequestWithUnmarshal(ctx, att, acc, data)
+	})
 }
 
 func (c *client) GetRequestWithSelectiveAccessByDateAndValues(att *dlms.AttributeDescriptor, start time.Time, end time.Time, values []dlms.AttributeDescriptor, data interface{}) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	return c.GetRequestWithSelectiveAccessByDateAndValuesContext(context.Background(), att, start, end, values, data)
+}
+
+func (c *client) GetRequestWithSelectiveAccessByDateAndValuesContext(ctx context.Context, att *dlms.AttributeDescriptor, start time.Time, end time.Time, values []dlms.AttributeDescriptor, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
+
+	acc, err := c.selectiveAccessByDate(start, end, values)
+	if err != nil {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid dates: %v", err))
+	}
 
-	acc := dlms.CreateSelectiveAccessByRangeDescriptor(start, end, values)
-	return c.getRequestWithUnmarshal(att, acc, data)
+	return c.withReconnect(ctx, func() error {
+		return c.getRequestWithUnmarshal(ctx, att, acc, data)
+	})
+}
+
+func (c *client) GetRequestWithList(atts []*dlms.AttributeDescriptor, data []interface{}) (err error) {
+	return c.GetRequestWithListContext(context.Background(), atts, data)
+}
+
+func (c *client) GetRequestWithListContext(ctx context.Context, atts []*dlms.AttributeDescriptor, data []interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
+
+	return c.withReconnect(ctx, func() error {
+		return c.getRequestWithList(ctx, atts, data)
+	})
 }
 
 func (c *client) GetRequestWithStructOfElements(data interface{}) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	return c.GetRequestWithStructOfElementsContext(context.Background(), data)
+}
+
+func (c *client) GetRequestWithStructOfElementsContext(ctx context.Context, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
 
-	return c.getRequestWithStructOfElements(data)
+	return c.withReconnect(ctx, func() error {
+		return c.getRequestWithStructOfElements(ctx, data)
+	})
 }
 
 func (c *client) CheckRequestWithStructOfElements(data interface{}) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	return c.checkRequestWithStructOfElements(data)
+	return c.CheckRequestWithStructOfElementsContext(context.Background(), data)
 }
 
-func (c *client) getAttributeDescriptor(field reflect.StructField) (*dlms.AttributeDescriptor, error) {
-	tag := field.Tag.Get("obis")
-	if tag == "" {
-		return nil, nil
+func (c *client) CheckRequestWithStructOfElementsContext(ctx context.Context, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
 	}
+	defer c.mutex.Release()
 
-	values := strings.Split(tag, ",")
-	if len(values) != 3 {
-		return nil, dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid obis tag: %s", tag))
-	}
+	return c.withReconnect(ctx, func() error {
+		return c.checkRequestWithStructOfElements(ctx, data)
+	})
+}
 
-	class, err := strconv.ParseUint(values[0], 0, 16)
-	if err != nil {
-		return nil, dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid class: %s", tag))
-	}
-	obis := values[1]
-	att, err := strconv.ParseUint(values[2], 0, 8)
+// getAttributeDescriptor parses the obis tag of a field: "class,obis,attribute" and,
+// optionally, a fourth "scaled" option to apply the scaler_unit of the object.
+func (c *client) getAttributeDescriptor(field reflect.StructField) (*dlms.AttributeDescriptor, bool, error) {
+	attribute, scaled, err := dlms.ParseObisTag(field.Tag.Get("obis"))
 	if err != nil {
-		return nil, dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("invalid attribute: %s", tag))
+		return nil, false, dlms.NewError(dlms.ErrorInvalidParameter, err.Error())
 	}
 
-	attribute := dlms.CreateAttributeDescriptor(uint16(class), obis, int8(att))
-
-	return attribute, nil
+	return attribute, scaled, nil
 }
 
-func (c *client) getRequestWithUnmarshal(att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor, data interface{}) (err error) {
-	axdrData, err := c.getRequest(att, acc)
+func (c *client) getRequestWithUnmarshal(ctx context.Context, att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor, data interface{}) (err error) {
+	axdrData, err := c.getRequest(ctx, att, acc)
 	if err != nil {
 		return
 	}
 
 	if data != nil {
-		err = axdr.UnmarshalData(axdrData, data)
+		err = c.unmarshalData(axdrData, data)
 		if err != nil {
 			return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error unmarshaling %s data: %v", att.String(), err))
 		}
@@ -99,15 +204,20 @@ func (c *client) getRequestWithUnmarshal(att *dlms.AttributeDescriptor, acc *dlm
 	return
 }
 
-func (c *client) getRequest(att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor) (data axdr.DlmsData, err error) {
+func (c *client) getRequest(ctx context.Context, att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor) (data axdr.DlmsData, err error) {
 	if att == nil {
 		err = dlms.NewError(dlms.ErrorInvalidParameter, "attribute descriptor cannot be nil")
 		return
 	}
 
-	req := dlms.CreateGetRequestNormal(unicastInvokeID, *att, acc)
+	if err = c.checkAnswered(att.String()); err != nil {
+		return
+	}
 
-	pdu, err := c.encodeSendReceiveAndDecode(req)
+	invokeID := c.nextInvokeID()
+	req := dlms.CreateGetRequestNormal(invokeID, *att, acc)
+
+	pdu, err := c.encodeSendReceiveAndDecode(ctx, req)
 	if err != nil {
 		return
 	}
@@ -120,58 +230,245 @@ func (c *client) getRequest(att *dlms.AttributeDescriptor, acc *dlms.SelectiveAc
 			err = dlms.NewError(dlms.ErrorGetRejected, fmt.Sprintf("get %s rejected: %s", att.String(), access.String()))
 		}
 	case dlms.GetResponseWithDataBlock:
-		blockNumber := 1
-		out := make([]byte, 0)
-		for {
-			if resp.Result.IsResult {
-				access, _ := resp.Result.ResultAsAccess()
-				err = dlms.NewError(dlms.ErrorGetRejected, fmt.Sprintf("get %s rejected: %s", att.String(), access.String()))
-				return
+		var out []byte
+		out, err = c.getDataBlocks(ctx, invokeID, resp, att.String())
+		if err != nil {
+			return
+		}
+
+		decoder := axdr.NewDataDecoder(&out)
+		data, err = decoder.Decode(&out)
+		if err != nil {
+			err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error decoding %s data: %v", att.String(), err))
+			return
+		}
+	default:
+		err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected PDU response type: %T", att.String(), pdu))
+	}
+
+	return
+}
+
+// getDataBlocks receives the rest of the blocks of a response, starting with resp,
+// and returns the raw data of all of them.
+func (c *client) getDataBlocks(ctx context.Context, invokeID uint8, resp dlms.GetResponseWithDataBlock, name string) ([]byte, error) {
+	return io.ReadAll(c.newBlockReader(ctx, invokeID, resp, name))
+}
+
+// blockReader reads the raw data of the blocks of a response, asking for each
+// block when the previous one has been read.
+type blockReader struct {
+	c           *client
+	ctx         context.Context
+	invokeID    uint8
+	name        string
+	resp        *dlms.GetResponseWithDataBlock
+	blockNumber int
+	block       []byte
+	last        bool
+}
+
+func (c *client) newBlockReader(ctx context.Context, invokeID uint8, resp dlms.GetResponseWithDataBlock, name string) *blockReader {
+	return &blockReader{c: c, ctx: ctx, invokeID: invokeID, name: name, resp: &resp, blockNumber: 1}
+}
+
+func (r *blockReader) Read(p []byte) (int, error) {
+	for len(r.block) == 0 {
+		if r.resp == nil {
+			if r.last {
+				return 0, io.EOF
 			}
 
-			if blockNumber != int(resp.Result.BlockNumber) {
-				err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("block number mismatch in %s: expected %d, got %d", att.String(), blockNumber, resp.Result.BlockNumber))
-				return
+			if err := r.requestNext(); err != nil {
+				return 0, err
 			}
+		}
+
+		if err := r.take(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.block)
+	r.block = r.block[n:]
+
+	return n, nil
+}
+
+// take checks the current response and takes its data.
+func (r *blockReader) take() error {
+	resp := r.resp
+	r.resp = nil
+
+	if resp.Result.IsResult {
+		access, _ := resp.Result.ResultAsAccess()
+		return dlms.NewError(dlms.ErrorGetRejected, fmt.Sprintf("get %s rejected: %s", r.name, access.String()))
+	}
+
+	if r.blockNumber != int(resp.Result.BlockNumber) {
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("block number mismatch in %s: expected %d, got %d", r.name, r.blockNumber, resp.Result.BlockNumber))
+	}
+
+	r.block, _ = resp.Result.ResultAsBytes()
+	r.last = resp.Result.LastBlock
 
-			res, _ := resp.Result.ResultAsBytes()
-			out = append(out, res...)
+	return nil
+}
+
+// requestNext asks for the block after the current one.
+func (r *blockReader) requestNext() error {
+	req := dlms.CreateGetRequestNext(r.invokeID, uint32(r.blockNumber))
+	r.blockNumber++
+
+	pdu, err := r.c.encodeSendReceiveAndDecode(r.ctx, req)
+	if err != nil {
+		return err
+	}
+
+	next, ok := pdu.(dlms.GetResponseWithDataBlock)
+	if !ok {
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s expected GetResponseWithDataBlock response, got %T", r.name, pdu))
+	}
+	r.resp = &next
+
+	return nil
+}
 
-			if resp.Result.LastBlock {
-				break
+// getRequestWithList reads several attributes at once. Get-Request-With-List is only
+// used when the server negotiated multiple references, otherwise the attributes are
+// read one by one.
+func (c *client) getRequestWithList(ctx context.Context, atts []*dlms.AttributeDescriptor, data []interface{}) (err error) {
+	if len(atts) == 0 || len(atts) != len(data) {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("expected the same number of attributes and data, got %d and %d", len(atts), len(data)))
+	}
+
+	for _, att := range atts {
+		if att == nil {
+			return dlms.NewError(dlms.ErrorInvalidParameter, "attribute descriptor cannot be nil")
+		}
+	}
+
+	if len(atts) == 1 || c.conformance&dlms.ConformanceBlockMultipleReferences == 0 {
+		for i, att := range atts {
+			if err = c.getRequestWithUnmarshal(ctx, att, nil, data[i]); err != nil {
+				return
 			}
+		}
+
+		return
+	}
+
+	// Every attribute descriptor takes 10 bytes in the request
+	lenHeader := 4
+	if c.settings.Ciphering.Level != dlms.SecurityLevelNone {
+		lenHeader += 21
+	}
+
+	chunkSize := (c.settings.MaxPduSendSize - lenHeader) / 10
+	if chunkSize > 255 {
+		chunkSize = 255
+	}
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+
+	for start := 0; start < len(atts); start += chunkSize {
+		end := start + chunkSize
+		if end > len(atts) {
+			end = len(atts)
+		}
+
+		results, err := c.getRequestList(ctx, atts[start:end])
+		if err != nil {
+			return err
+		}
 
-			req := dlms.CreateGetRequestNext(unicastInvokeID, uint32(blockNumber))
-			blockNumber++
+		for i, result := range results {
+			att := atts[start+i]
 
-			pdu, err = c.encodeSendReceiveAndDecode(req)
+			value, err := result.ValueAsData()
 			if err != nil {
-				return
+				access, _ := result.ValueAsAccess()
+				return dlms.NewError(dlms.ErrorGetRejected, fmt.Sprintf("get %s rejected: %s", att.String(), access.String()))
 			}
 
-			var ok bool
-			resp, ok = pdu.(dlms.GetResponseWithDataBlock)
-			if !ok {
-				err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s expected GetResponseWithDataBlock response, got %T", att.String(), pdu))
-				return
+			if data[start+i] != nil {
+				if err = c.unmarshalData(value, data[start+i]); err != nil {
+					return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error unmarshaling %s data: %v", att.String(), err))
+				}
 			}
 		}
+	}
 
-		decoder := axdr.NewDataDecoder(&out)
-		data, err = decoder.Decode(&out)
+	return nil
+}
+
+func (c *client) getRequestList(ctx context.Context, atts []*dlms.AttributeDescriptor) (results []dlms.GetDataResult, err error) {
+	list := make([]dlms.AttributeDescriptorWithSelection, len(atts))
+	for i, att := range atts {
+		list[i] = dlms.AttributeDescriptorWithSelection{ClassID: att.ClassID, InstanceID: att.InstanceID, AttributeID: att.AttributeID}
+	}
+
+	name := fmt.Sprintf("list of %d attributes", len(atts))
+
+	if err = c.checkAnswered(name); err != nil {
+		return
+	}
+
+	invokeID := c.nextInvokeID()
+	req := dlms.CreateGetRequestWithList(invokeID, list)
+
+	pdu, err := c.encodeSendReceiveAndDecode(ctx, req)
+	if err != nil {
+		return
+	}
+
+	switch resp := pdu.(type) {
+	case dlms.GetResponseWithList:
+		results = resp.ResultList
+	case dlms.GetResponseWithDataBlock:
+		var out []byte
+		out, err = c.getDataBlocks(ctx, invokeID, resp, name)
 		if err != nil {
-			err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error decoding %s data: %v", att.String(), err))
 			return
 		}
+
+		// The blocks carry the encoded sequence of results
+		var count uint64
+		_, count, err = axdr.DecodeLength(&out)
+		if err != nil {
+			err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error decoding %s: %v", name, err))
+			return
+		}
+
+		for i := uint64(0); i < count; i++ {
+			var result dlms.GetDataResult
+			if len(out) == 0 {
+				err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error decoding %s: missing result %d", name, i))
+				return
+			}
+
+			result, err = dlms.DecodeGetDataResult(&out)
+			if err != nil {
+				err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error decoding %s: %v", name, err))
+				return
+			}
+			results = append(results, result)
+		}
 	default:
-		err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected PDU response type: %T", att.String(), pdu))
+		err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected PDU response type: %T", name, pdu))
+		return
+	}
+
+	if len(results) != len(atts) {
+		err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s expected %d results, got %d", name, len(atts), len(results)))
 	}
 
 	return
 }
 
 //nolint:nestif
-func (c *client) getRequestWithStructOfElements(data interface{}) (err error) {
+func (c *client) getRequestWithStructOfElements(ctx context.Context, data interface{}) (err error) {
 	rv := reflect.ValueOf(data)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return dlms.NewError(dlms.ErrorInvalidParameter, "data must be a non-nil pointer")
@@ -183,7 +480,7 @@ func (c *client) getRequestWithStructOfElements(data interface{}) (err error) {
 	}
 
 	for i := 0; i < v.NumField(); i++ {
-		ad, err := c.getAttributeDescriptor(v.Type().Field(i))
+		ad, scaled, err := c.getAttributeDescriptor(v.Type().Field(i))
 		if err != nil {
 			return err
 		}
@@ -191,7 +488,11 @@ func (c *client) getRequestWithStructOfElements(data interface{}) (err error) {
 		field := v.Field(i)
 
 		if ad != nil {
-			err = c.getRequestWithUnmarshal(ad, nil, field.Addr().Interface())
+			if scaled {
+				err = c.getScaledRequest(ctx, ad, field.Addr().Interface())
+			} else {
+				err = c.getRequestWithUnmarshal(ctx, ad, nil, field.Addr().Interface())
+			}
 			if err != nil {
 				// If a get is rejected in a field which is a pointer, then we will continue without any error
 				var dlmsError *dlms.Error
@@ -202,7 +503,7 @@ func (c *client) getRequestWithStructOfElements(data interface{}) (err error) {
 				}
 			}
 		} else if field.Kind() == reflect.Struct {
-			err = c.getRequestWithStructOfElements(field.Addr().Interface())
+			err = c.getRequestWithStructOfElements(ctx, field.Addr().Interface())
 			if err != nil {
 				return err
 			}
@@ -213,7 +514,7 @@ func (c *client) getRequestWithStructOfElements(data interface{}) (err error) {
 }
 
 //nolint:nestif
-func (c *client) checkRequestWithStructOfElements(data interface{}) (err error) {
+func (c *client) checkRequestWithStructOfElements(ctx context.Context, data interface{}) (err error) {
 	rv := reflect.ValueOf(data)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return dlms.NewError(dlms.ErrorInvalidParameter, "data must be a non-nil pointer")
@@ -225,11 +526,15 @@ func (c *client) checkRequestWithStructOfElements(data interface{}) (err error)
 	}
 
 	for i := 0; i < v.NumField(); i++ {
-		ad, err := c.getAttributeDescriptor(v.Type().Field(i))
+		ad, scaled, err := c.getAttributeDescriptor(v.Type().Field(i))
 		if err != nil {
 			return err
 		}
 
+		if scaled {
+			return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("scaled field %s cannot be checked", v.Type().Field(i).Name))
+		}
+
 		field := v.Field(i)
 
 		if ad != nil {
@@ -246,7 +551,7 @@ func (c *client) checkRequestWithStructOfElements(data interface{}) (err error)
 			// Copy the expected value
 			value := reflect.New(reflect.Indirect(field).Type())
 
-			err = c.getRequestWithUnmarshal(ad, nil, value.Interface())
+			err = c.getRequestWithUnmarshal(ctx, ad, nil, value.Interface())
 			if err != nil {
 				return err
 			}
@@ -263,7 +568,7 @@ func (c *client) checkRequestWithStructOfElements(data interface{}) (err error)
 				return dlms.NewError(dlms.ErrorCheckDoesNotMatch, fmt.Sprintf("values are not equal. Expected %v, got %v", expected, got))
 			}
 		} else if field.Kind() == reflect.Struct {
-			err = c.checkRequestWithStructOfElements(field.Addr().Interface())
+			err = c.checkRequestWithStructOfElements(ctx, field.Addr().Interface())
 			if err != nil {
 				return err
 			}
@@ -26,6 +26,41 @@ func TestClient_GetRequest(t *testing.T) {
 	tm.AssertExpectations(t)
 }
 
+func TestClient_GetRequestUnconfirmed(t *testing.T) {
+	c, tm, _ := associate(t)
+
+	settings := c.GetSettings()
+	settings.ServiceClass = dlms.ServiceClassUnconfirmed
+	c.SetSettings(settings)
+
+	var data int16
+
+	// Nothing is sent, as there would be no response
+	err := c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 3), &data)
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorInvalidState, clientError.Code())
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_GetRequestWithTimeZone(t *testing.T) {
+	settings, _ := dlms.NewSettingsWithoutAuthentication()
+	tz := axdr.TimeZoneReversed
+	settings.TimeZone = &tz
+
+	c, tm, rdc := associateWithSettings(t, settings)
+
+	var data time.Time
+
+	sendReceive(tm, rdc, "C001C100080000010000FF0200", "C401C100090C07D00106040F003000003C00")
+	err := c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), &data)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2000, time.January, 6, 14, 0, 48, 0, time.UTC).Unix(), data.Unix())
+
+	tm.AssertExpectations(t)
+}
+
 func TestClient_GetRequestFail(t *testing.T) {
 	c, tm, rdc := associate(t)
 
@@ -41,28 +76,28 @@ func TestClient_GetRequestFail(t *testing.T) {
 	assert.Equal(t, dlms.ErrorGetRejected, clientError.Code())
 
 	// Unexpected response
-	sendReceive(tm, rdc, "C001C100080000010000FF0300", "0E010203")
+	sendReceive(tm, rdc, "C001C200080000010000FF0300", "0E010203")
 
 	err = c.GetRequest(clockAttributeDescriptor, &data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Invalid response
-	sendReceive(tm, rdc, "C001C100080000010000FF0300", "AE12")
+	sendReceive(tm, rdc, "C001C300080000010000FF0300", "AE12")
 
 	err = c.GetRequest(clockAttributeDescriptor, &data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Response type doesn't match
-	sendReceive(tm, rdc, "C001C100080000010000FF0300", "C401C10010003C")
+	sendReceive(tm, rdc, "C001C400080000010000FF0300", "C401C40010003C")
 
 	err = c.GetRequest(clockAttributeDescriptor, &data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Send failed
-	tm.On("Send", decodeHexString("C001C100080000010000FF0300")).Return(fmt.Errorf("error")).Once()
+	tm.On("Send", decodeHexString("C001C500080000010000FF0300")).Return(fmt.Errorf("error")).Once()
 	tm.On("IsConnected").Return(false).Once()
 
 	err = c.GetRequest(clockAttributeDescriptor, &data)
@@ -113,27 +148,27 @@ func TestClient_GetRequestWithDataBlockFail(t *testing.T) {
 	assert.Equal(t, dlms.ErrorGetRejected, clientError.Code())
 
 	// Invalid block number
-	sendReceive(tm, rdc, "C001C100070100630100FF0200", "C402C10000000002000C010506000000010600000002")
+	sendReceive(tm, rdc, "C001C200070100630100FF0200", "C402C20000000002000C010506000000010600000002")
 	err = c.GetRequest(dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), &data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Invalid response
-	sendReceive(tm, rdc, "C001C100070100630100FF0200", "C402C10000000001000C010506000000010600000002")
-	sendReceive(tm, rdc, "C002C100000001", "AE12")
+	sendReceive(tm, rdc, "C001C300070100630100FF0200", "C402C30000000001000C010506000000010600000002")
+	sendReceive(tm, rdc, "C002C300000001", "AE12")
 	err = c.GetRequest(dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), &data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Unexpected response
-	sendReceive(tm, rdc, "C001C100070100630100FF0200", "C402C10000000001000C010506000000010600000002")
-	sendReceive(tm, rdc, "C002C100000001", "0E010203")
+	sendReceive(tm, rdc, "C001C400070100630100FF0200", "C402C40000000001000C010506000000010600000002")
+	sendReceive(tm, rdc, "C002C400000001", "0E010203")
 	err = c.GetRequest(dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), &data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Invalid data
-	sendReceive(tm, rdc, "C001C100070100630100FF0200", "C402C10100000001000C010506000000010600000002")
+	sendReceive(tm, rdc, "C001C500070100630100FF0200", "C402C50100000001000C010506000000010600000002")
 	err = c.GetRequest(dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), &data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
@@ -154,13 +189,63 @@ func TestClient_GetRequestRequestWithSelectiveAccess(t *testing.T) {
 
 	selectiveAccess := *axdr.CreateAxdrStructure([]*axdr.DlmsData{&firstStruct, axdr.CreateAxdrOctetString(timeStart), axdr.CreateAxdrOctetString(timeEnd), axdr.CreateAxdrArray([]*axdr.DlmsData{})})
 
-	err := c.GetRequestWithSelectiveAccess(atrDescriptor, selectiveAccess, &data)
+	err := c.GetRequestWithSelectiveAccess(atrDescriptor, selectiveAccess, &data) //nolint:staticcheck
 	assert.NoError(t, err)
 	assert.Len(t, data, 2)
 
 	tm.AssertExpectations(t)
 }
 
+func TestClient_GetRequestWithRange(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	var data []uint32
+
+	// Entries with sequence number between 10 and 20
+	sendReceive(tm, rdc, "C001C100070100630100FF0201010204020412000109060000600F00FF0F02120000060000000A06000000140100", "C401C100010206000000010600000002")
+	rd := dlms.RangeDescriptor{
+		RestrictingObject: *dlms.CreateAttributeDescriptor(1, "0-0:96.15.0.255", 2),
+		From:              uint32(10),
+		To:                uint32(20),
+	}
+
+	err := c.GetRequestWithRange(dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), rd, &data)
+	assert.NoError(t, err)
+	assert.Len(t, data, 2)
+
+	rd.To = 20
+	err = c.GetRequestWithRange(dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), rd, &data)
+
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorInvalidParameter, clientError.Code())
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_GetRequestWithEntries(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	var data []uint32
+
+	// Entries 1 to 100, only the second column
+	sendReceive(tm, rdc, "C001C100070100630100FF020102020406000000010600000064120002120002", "C401C100010206000000010600000002")
+	ed := dlms.EntryDescriptor{FromEntry: 1, ToEntry: 100, FromColumn: 2, ToColumn: 2}
+
+	err := c.GetRequestWithEntries(dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), ed, &data)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 2}, data)
+
+	ed.FromEntry = 101
+	err = c.GetRequestWithEntries(dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2), ed, &data)
+
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorInvalidParameter, clientError.Code())
+
+	tm.AssertExpectations(t)
+}
+
 func TestClient_GetRequestRequestWithSelectiveAccessByDate(t *testing.T) {
 	c, tm, rdc := associate(t)
 
@@ -196,6 +281,65 @@ func TestClient_GetRequestWithSelectiveAccessByDateAndValues(t *testing.T) {
 	tm.AssertExpectations(t)
 }
 
+func TestClient_GetRequestWithList(t *testing.T) {
+	c, tm, rdc := associateWithMultipleReferences(t)
+
+	var value uint32
+	var su dlms.ScalerUnit
+
+	atts := []*dlms.AttributeDescriptor{
+		dlms.CreateAttributeDescriptor(3, "1-0:1.8.0.255", 2),
+		dlms.CreateAttributeDescriptor(3, "1-0:1.8.0.255", 3),
+	}
+
+	sendReceive(tm, rdc, "C003C10200030100010800FF020000030100010800FF0300", "C403C1020006000030390002020FFE161E")
+	err := c.GetRequestWithList(atts, []interface{}{&value, &su})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12345), value)
+	assert.Equal(t, dlms.ScalerUnit{Scaler: -2, Unit: dlms.UnitWattHour}, su)
+
+	// An attribute rejected fails the whole request
+	sendReceive(tm, rdc, "C003C20200030100010800FF020000030100010800FF0300", "C403C202000600003039"+"0104")
+	err = c.GetRequestWithList(atts, []interface{}{&value, &su})
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorGetRejected, clientError.Code())
+
+	// The results may also be sent in blocks
+	value = 0
+	sendReceive(tm, rdc, "C003C30200030100010800FF020000030100010800FF0300", "C402C30100000001000E"+"02000600003039"+"0002020FFE161E")
+	err = c.GetRequestWithList(atts, []interface{}{&value, &su})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12345), value)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_GetRequestWithListWithoutMultipleReferences(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	var value uint32
+	var su dlms.ScalerUnit
+
+	atts := []*dlms.AttributeDescriptor{
+		dlms.CreateAttributeDescriptor(3, "1-0:1.8.0.255", 2),
+		dlms.CreateAttributeDescriptor(3, "1-0:1.8.0.255", 3),
+	}
+
+	// The server did not negotiate multiple references, so both are read one by one
+	sendReceive(tm, rdc, "C001C100030100010800FF0200", "C401C1000600003039")
+	sendReceive(tm, rdc, "C001C200030100010800FF0300", "C401C20002020FFE161E")
+	err := c.GetRequestWithList(atts, []interface{}{&value, &su})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(12345), value)
+	assert.Equal(t, dlms.ScalerUnit{Scaler: -2, Unit: dlms.UnitWattHour}, su)
+
+	err = c.GetRequestWithList(atts, []interface{}{&value})
+	assert.Error(t, err)
+
+	tm.AssertExpectations(t)
+}
+
 func TestClient_GetRequestWithStructOfElements(t *testing.T) {
 	var data struct {
 		Value1 uint  `obis:"1,1-1:94.34.100.255,2"`
@@ -207,9 +351,9 @@ func TestClient_GetRequestWithStructOfElements(t *testing.T) {
 	c, tm, rdc := associate(t)
 
 	sendReceive(tm, rdc, "C001C1000101015E2264FF0200", "C401C1001104")
-	sendReceive(tm, rdc, "C001C1000101015E2268FF0200", "C401C1001101")
-	sendReceive(tm, rdc, "C001C10046000060030AFF0300", "C401C10109")
-	sendReceive(tm, rdc, "C001C100030000600A07FF0200", "C401C10009062043594B3132")
+	sendReceive(tm, rdc, "C001C2000101015E2268FF0200", "C401C2001101")
+	sendReceive(tm, rdc, "C001C30046000060030AFF0300", "C401C30109")
+	sendReceive(tm, rdc, "C001C400030000600A07FF0200", "C401C40009062043594B3132")
 	err := c.GetRequestWithStructOfElements(&data)
 	assert.NoError(t, err)
 	assert.Equal(t, uint(4), data.Value1)
@@ -220,6 +364,24 @@ func TestClient_GetRequestWithStructOfElements(t *testing.T) {
 	tm.AssertExpectations(t)
 }
 
+func TestClient_GetRequestWithStructOfElementsUnmarshaler(t *testing.T) {
+	var data struct {
+		Obis       dlms.Obis       `obis:"1,0-0:96.1.1.255,2"`
+		ScalerUnit dlms.ScalerUnit `obis:"3,1-0:1.8.0.255,3"`
+	}
+
+	c, tm, rdc := associate(t)
+
+	sendReceive(tm, rdc, "C001C100010000600101FF0200", "C401C10009060100010800FF")
+	sendReceive(tm, rdc, "C001C200030100010800FF0300", "C401C20002020FFE161E")
+	err := c.GetRequestWithStructOfElements(&data)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.1.8.0.255", data.Obis.String())
+	assert.Equal(t, dlms.ScalerUnit{Scaler: -2, Unit: dlms.UnitWattHour}, data.ScalerUnit)
+
+	tm.AssertExpectations(t)
+}
+
 func TestClient_GetRequestWithNestedStructOfElements(t *testing.T) {
 	type data2 struct {
 		Value uint `obis:"1,1-1:94.34.104.255,2"`
@@ -238,8 +400,8 @@ func TestClient_GetRequestWithNestedStructOfElements(t *testing.T) {
 	c, tm, rdc := associate(t)
 
 	sendReceive(tm, rdc, "C001C1000101015E2264FF0200", "C401C1001104")
-	sendReceive(tm, rdc, "C001C1000101015E2268FF0200", "C401C1001101")
-	sendReceive(tm, rdc, "C001C10046000060030AFF0300", "C401C10109")
+	sendReceive(tm, rdc, "C001C2000101015E2268FF0200", "C401C2001101")
+	sendReceive(tm, rdc, "C001C30046000060030AFF0300", "C401C30109")
 	err := c.GetRequestWithStructOfElements(&data)
 	assert.NoError(t, err)
 	assert.Equal(t, uint(4), data.Value1)
@@ -266,8 +428,8 @@ func TestClient_CheckRequestWithStructOfElements(t *testing.T) {
 	c, tm, rdc := associate(t)
 
 	sendReceive(tm, rdc, "C001C1000101015E2268FF0200", "C401C1001104")
-	sendReceive(tm, rdc, "C001C100010000600101FF0200", "C401C10009062043594B3132")
-	sendReceive(tm, rdc, "C001C1000300005E2204FF0200", "C401C10001020F030F04")
+	sendReceive(tm, rdc, "C001C200010000600101FF0200", "C401C20009062043594B3132")
+	sendReceive(tm, rdc, "C001C3000300005E2204FF0200", "C401C30001020F030F04")
 	err := c.CheckRequestWithStructOfElements(&data)
 	assert.NoError(t, err)
 
@@ -276,8 +438,8 @@ func TestClient_CheckRequestWithStructOfElements(t *testing.T) {
 	// If the first value is nil, just check the second value
 	data.Value1 = nil
 
-	sendReceive(tm, rdc, "C001C100010000600101FF0200", "C401C10009062043594B3132")
-	sendReceive(tm, rdc, "C001C1000300005E2204FF0200", "C401C10001020F030F04")
+	sendReceive(tm, rdc, "C001C400010000600101FF0200", "C401C40009062043594B3132")
+	sendReceive(tm, rdc, "C001C5000300005E2204FF0200", "C401C50001020F030F04")
 	err = c.CheckRequestWithStructOfElements(&data)
 	assert.NoError(t, err)
 
@@ -287,7 +449,7 @@ func TestClient_CheckRequestWithStructOfElements(t *testing.T) {
 	value1 = 8
 	data.Value1 = &value1
 
-	sendReceive(tm, rdc, "C001C1000101015E2268FF0200", "C401C1001104")
+	sendReceive(tm, rdc, "C001C6000101015E2268FF0200", "C401C6001104")
 	err = c.CheckRequestWithStructOfElements(&data)
 	var clientError *dlms.Error
 	assert.ErrorAs(t, err, &clientError)
@@ -298,15 +460,81 @@ func TestClient_CheckRequestWithStructOfElements(t *testing.T) {
 	// Values in a slice should also be checked
 	data.Value1 = nil
 
-	sendReceive(tm, rdc, "C001C100010000600101FF0200", "C401C10009062043594B3132")
-	sendReceive(tm, rdc, "C001C1000300005E2204FF0200", "C401C10001020F030F05")
+	sendReceive(tm, rdc, "C001C700010000600101FF0200", "C401C70009062043594B3132")
+	sendReceive(tm, rdc, "C001C8000300005E2204FF0200", "C401C80001020F030F05")
 	err = c.CheckRequestWithStructOfElements(&data)
 	assert.Error(t, err)
 
 	tm.AssertExpectations(t)
 }
 
-func associate(t *testing.T) (dlms.Client, *mocks.TransportMock, dlms.DataChannel) {
+func TestClient_GetRequestWithScaledStructOfElements(t *testing.T) {
+	var data struct {
+		Energy float64             `obis:"3,1-0:1.8.0.255,2,scaled"`
+		Power  *dlms.PhysicalValue `obis:"3,1-0:1.7.0.255,2,scaled"`
+	}
+
+	c, tm, rdc := associate(t)
+
+	// The scalers are read the first time only
+	sendReceive(tm, rdc, "C001C100030100010800FF0300", "C401C10002020FFE161E")
+	sendReceive(tm, rdc, "C001C200030100010800FF0200", "C401C2000600003039")
+	sendReceive(tm, rdc, "C001C300030100010700FF0300", "C401C30002020F03161B")
+	sendReceive(tm, rdc, "C001C400030100010700FF0200", "C401C400100007")
+	err := c.GetRequestWithStructOfElements(&data)
+	assert.NoError(t, err)
+	assert.Equal(t, 123.45, data.Energy)
+	assert.Equal(t, &dlms.PhysicalValue{Value: 7000, Unit: dlms.UnitWatt}, data.Power)
+
+	sendReceive(tm, rdc, "C001C500030100010800FF0200", "C401C5000600003040")
+	sendReceive(tm, rdc, "C001C600030100010700FF0200", "C401C6000100")
+	err = c.GetRequestWithStructOfElements(&data)
+	assert.NoError(t, err)
+	assert.Equal(t, 123.52, data.Energy)
+	assert.Nil(t, data.Power)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_GetRequestWithScaledStructOfElementsFail(t *testing.T) {
+	c, _, _ := associate(t)
+
+	var wrongClass struct {
+		Value float64 `obis:"1,0-0:96.1.0.255,2,scaled"`
+	}
+	err := c.GetRequestWithStructOfElements(&wrongClass)
+	assert.Error(t, err)
+
+	var wrongType struct {
+		Value uint32 `obis:"3,1-0:1.8.0.255,2,scaled"`
+	}
+	err = c.GetRequestWithStructOfElements(&wrongType)
+	assert.Error(t, err)
+
+	var wrongOption struct {
+		Value float64 `obis:"3,1-0:1.8.0.255,2,unscaled"`
+	}
+	err = c.GetRequestWithStructOfElements(&wrongOption)
+	assert.Error(t, err)
+
+	// Pointers are not assigned when the value cannot be read
+	var wrongClassPointer struct {
+		Value *dlms.PhysicalValue `obis:"1,0-0:96.1.0.255,2,scaled"`
+	}
+	err = c.GetRequestWithStructOfElements(&wrongClassPointer)
+	assert.Error(t, err)
+	assert.Nil(t, wrongClassPointer.Value)
+}
+
+func associate(t *testing.T) (dlms.ContextClient, *mocks.TransportMock, dlms.DataChannel) {
+	t.Helper()
+
+	settings, _ := dlms.NewSettingsWithoutAuthentication()
+
+	return associateWithSettings(t, settings)
+}
+
+func associateWithSettings(t *testing.T, settings dlms.Settings) (dlms.ContextClient, *mocks.TransportMock, dlms.DataChannel) {
 	t.Helper()
 
 	tm := mocks.NewTransportMock(t)
@@ -316,7 +544,6 @@ func associate(t *testing.T) (dlms.Client, *mocks.TransportMock, dlms.DataChanne
 		rdc = args.Get(0).(dlms.DataChannel)
 	}).Once()
 
-	settings, _ := dlms.NewSettingsWithoutAuthentication()
 	c := dlmsclient.New(settings, tm, 5*time.Second, 0)
 
 	tm.On("Connect").Return(nil).Once()
@@ -330,3 +557,31 @@ func associate(t *testing.T) (dlms.Client, *mocks.TransportMock, dlms.DataChanne
 
 	return c, tm, rdc
 }
+
+// associateWithMultipleReferences associates with a server that negotiates the
+// multiple references conformance bit, so requests with list are allowed.
+func associateWithMultipleReferences(t *testing.T) (dlms.ContextClient, *mocks.TransportMock, dlms.DataChannel) {
+	t.Helper()
+
+	settings, _ := dlms.NewSettingsWithoutAuthentication()
+
+	tm := mocks.NewTransportMock(t)
+
+	rdc := make(dlms.DataChannel, 10)
+	tm.On("SetReception", mock.Anything).Run(func(args mock.Arguments) {
+		rdc = args.Get(0).(dlms.DataChannel)
+	}).Once()
+
+	c := dlmsclient.New(settings, tm, 5*time.Second, 0)
+
+	tm.On("Connect").Return(nil).Once()
+	c.Connect()
+
+	tm.On("IsConnected").Return(true).Once()
+	sendReceive(tm, rdc, "601DA109060760857405080101BE10040E01000000065F1F040000181F0100", "6129A109060760857405080101A203020100A305A103020100BE10040E0800065F1F040000121D00800007")
+
+	err := c.Associate()
+	assert.NoError(t, err)
+
+	return c, tm, rdc
+}
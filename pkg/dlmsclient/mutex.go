@@ -0,0 +1,142 @@
+package dlmsclient
+
+import (
+	"context"
+	"sync/atomic"
+
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// ctxMutex is a mutual exclusion lock whose acquisition can be abandoned when a
+// context is done, so callers waiting for an ongoing request can give up.
+type ctxMutex struct {
+	ch chan struct{}
+}
+
+func newCtxMutex() ctxMutex {
+	return ctxMutex{ch: make(chan struct{}, 1)}
+}
+
+func (m *ctxMutex) Lock() {
+	m.ch <- struct{}{}
+}
+
+func (m *ctxMutex) LockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return dlms.ContextError(err)
+	}
+
+	select {
+	case m.ch <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return dlms.ContextError(ctx.Err())
+	}
+}
+
+func (m *ctxMutex) Unlock() {
+	<-m.ch
+}
+
+// requestLock limits the number of requests in flight. Pipelined requests take a
+// single slot (Acquire) while the operations that need the association for
+// themselves take all of them (Lock). With a single slot it behaves as a mutex.
+type requestLock struct {
+	exclusive ctxMutex
+	slots     chan struct{}
+	locked    atomic.Bool
+}
+
+func newRequestLock(window int) requestLock {
+	return requestLock{
+		exclusive: newCtxMutex(),
+		slots:     make(chan struct{}, window),
+		locked:    atomic.Bool{},
+	}
+}
+
+func (l *requestLock) Acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return dlms.ContextError(err)
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return dlms.ContextError(ctx.Err())
+	}
+}
+
+func (l *requestLock) Release() {
+	<-l.slots
+}
+
+func (l *requestLock) Lock() {
+	l.exclusive.Lock()
+
+	for i := 0; i < cap(l.slots); i++ {
+		l.slots <- struct{}{}
+	}
+
+	l.locked.Store(true)
+}
+
+func (l *requestLock) LockContext(ctx context.Context) error {
+	if err := l.exclusive.LockContext(ctx); err != nil {
+		return err
+	}
+
+	for i := 0; i < cap(l.slots); i++ {
+		if err := l.Acquire(ctx); err != nil {
+			for ; i > 0; i-- {
+				l.Release()
+			}
+			l.exclusive.Unlock()
+
+			return err
+		}
+	}
+
+	l.locked.Store(true)
+
+	return nil
+}
+
+func (l *requestLock) Unlock() {
+	l.locked.Store(false)
+
+	for i := 0; i < cap(l.slots); i++ {
+		l.Release()
+	}
+
+	l.exclusive.Unlock()
+}
+
+// Upgrade gives the whole lock to a caller holding either a slot or the whole lock
+// already, waiting for the other requests in flight to finish. The returned function
+// releases the whole lock but the slot of the caller. While the lock is held as a whole nobody else holds a slot, so
+// a caller that sees it locked is the one holding it.
+func (l *requestLock) Upgrade(ctx context.Context) (func(), error) {
+	if l.locked.Load() {
+		return func() {}, nil
+	}
+
+	l.Release()
+
+	if err := l.LockContext(ctx); err != nil {
+		// The caller still releases its slot
+		l.slots <- struct{}{}
+		return nil, err
+	}
+
+	return func() {
+		l.locked.Store(false)
+
+		for i := 1; i < cap(l.slots); i++ {
+			l.Release()
+		}
+
+		l.exclusive.Unlock()
+	}, nil
+}
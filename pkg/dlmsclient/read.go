@@ -0,0 +1,124 @@
+package dlmsclient
+
+import (
+	"context"
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func (c *client) ReadRequest(sn uint16, data interface{}) (err error) {
+	return c.ReadRequestContext(context.Background(), sn, data)
+}
+
+func (c *client) ReadRequestContext(ctx context.Context, sn uint16, data interface{}) (err error) {
+	if err := c.mutex.LockContext(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Unlock()
+
+	return c.withReconnect(ctx, func() error {
+		return c.readRequestWithUnmarshal(ctx, dlms.CreateVariableNameAccess(sn), data)
+	})
+}
+
+func (c *client) ReadRequestWithParameters(sn uint16, selector uint8, parameter axdr.DlmsData, data interface{}) (err error) {
+	return c.ReadRequestWithParametersContext(context.Background(), sn, selector, parameter, data)
+}
+
+func (c *client) ReadRequestWithParametersContext(ctx context.Context, sn uint16, selector uint8, parameter axdr.DlmsData, data interface{}) (err error) {
+	if err := c.mutex.LockContext(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Unlock()
+
+	return c.withReconnect(ctx, func() error {
+		return c.readRequestWithUnmarshal(ctx, dlms.CreateParameterizedAccess(sn, selector, parameter), data)
+	})
+}
+
+func (c *client) readRequestWithUnmarshal(ctx context.Context, va *dlms.VariableAccessSpecification, data interface{}) (err error) {
+	axdrData, err := c.readRequest(ctx, va)
+	if err != nil {
+		return
+	}
+
+	if data != nil {
+		err = c.unmarshalData(axdrData, data)
+		if err != nil {
+			return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error unmarshaling %s data: %v", va.String(), err))
+		}
+	}
+
+	return
+}
+
+func (c *client) readRequest(ctx context.Context, va *dlms.VariableAccessSpecification) (data axdr.DlmsData, err error) {
+	req := dlms.CreateReadRequest([]dlms.VariableAccessSpecification{*va})
+
+	pdu, err := c.encodeSendReceiveAndDecode(ctx, req)
+	if err != nil {
+		return
+	}
+
+	blockNumber := 1
+	out := make([]byte, 0)
+	for {
+		resp, ok := pdu.(dlms.ReadResponse)
+		if !ok {
+			err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected PDU response type: %T", va.String(), pdu))
+			return
+		}
+
+		if len(resp.Results) != 1 {
+			err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s expected 1 result, got %d", va.String(), len(resp.Results)))
+			return
+		}
+
+		result := resp.Results[0]
+		switch result.Tag {
+		case dlms.TagReadResultData:
+			if blockNumber != 1 {
+				err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s expected data block %d, got data", va.String(), blockNumber))
+				return
+			}
+
+			data = result.Data
+			return
+		case dlms.TagReadResultDataAccessError:
+			err = dlms.NewError(dlms.ErrorReadRejected, fmt.Sprintf("read %s rejected: %s", va.String(), result.AccessError.String()))
+			return
+		case dlms.TagReadResultDataBlockResult:
+			if blockNumber != int(result.DataBlock.BlockNumber) {
+				err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("block number mismatch in %s: expected %d, got %d", va.String(), blockNumber, result.DataBlock.BlockNumber))
+				return
+			}
+
+			out = append(out, result.DataBlock.RawData...)
+		default:
+			err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected read result: %d", va.String(), result.Tag))
+			return
+		}
+
+		if result.DataBlock.LastBlock {
+			break
+		}
+
+		req := dlms.CreateReadRequest([]dlms.VariableAccessSpecification{*dlms.CreateBlockNumberAccess(uint16(blockNumber))})
+		blockNumber++
+
+		pdu, err = c.encodeSendReceiveAndDecode(ctx, req)
+		if err != nil {
+			return
+		}
+	}
+
+	decoder := axdr.NewDataDecoder(&out)
+	data, err = decoder.Decode(&out)
+	if err != nil {
+		err = dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error decoding %s data: %v", va.String(), err))
+	}
+
+	return
+}
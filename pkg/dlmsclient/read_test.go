@@ -0,0 +1,112 @@
+package dlmsclient_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+	"gitlab.com/circutor-library/gosem/pkg/dlms/mocks"
+	"gitlab.com/circutor-library/gosem/pkg/dlmsclient"
+)
+
+func TestClient_ReadRequest(t *testing.T) {
+	c, tm, rdc := associateShortName(t)
+
+	var data uint16
+
+	sendReceive(tm, rdc, "050102FA08", "0C010012003C")
+	err := c.ReadRequest(dlms.ShortName(0xFA00, 2), &data)
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0x003C), data)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_ReadRequestWithParameters(t *testing.T) {
+	c, tm, rdc := associateShortName(t)
+
+	var data []uint16
+
+	sendReceive(tm, rdc, "050104FA1001120005", "0C01000102120001120002")
+	err := c.ReadRequestWithParameters(0xFA10, 1, *axdr.CreateAxdrLongUnsigned(5), &data)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{1, 2}, data)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_ReadRequestWithBlock(t *testing.T) {
+	c, tm, rdc := associateShortName(t)
+
+	var data string
+
+	sendReceive(tm, rdc, "050102FA08", "0C01020000010409060000")
+	sendReceive(tm, rdc, "0501050001", "0C010201000204010000FF")
+	err := c.ReadRequest(0xFA08, &data)
+	assert.NoError(t, err)
+	assert.Equal(t, "0000010000ff", data)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_ReadRequestFail(t *testing.T) {
+	c, tm, rdc := associateShortName(t)
+
+	var data uint16
+
+	// Read rejected
+	sendReceive(tm, rdc, "050102FA08", "0C010103")
+	err := c.ReadRequest(0xFA08, &data)
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorReadRejected, clientError.Code())
+
+	// Unexpected response
+	sendReceive(tm, rdc, "050102FA08", "C401C10010003C")
+	err = c.ReadRequest(0xFA08, &data)
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
+
+	// Block number mismatch
+	sendReceive(tm, rdc, "050102FA08", "0C01020000020409060000")
+	err = c.ReadRequest(0xFA08, &data)
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
+
+	// Response type doesn't match
+	sendReceive(tm, rdc, "050102FA08", "0C01000F01")
+	err = c.ReadRequest(0xFA08, &data)
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
+
+	tm.AssertExpectations(t)
+}
+
+func associateShortName(t *testing.T) (dlms.ContextClient, *mocks.TransportMock, dlms.DataChannel) {
+	t.Helper()
+
+	tm := mocks.NewTransportMock(t)
+
+	rdc := make(dlms.DataChannel, 10)
+	tm.On("SetReception", mock.Anything).Run(func(args mock.Arguments) {
+		rdc = args.Get(0).(dlms.DataChannel)
+	}).Once()
+
+	settings, _ := dlms.NewSettingsWithoutAuthentication()
+	settings.EnableShortNameReferencing()
+	c := dlmsclient.New(settings, tm, 5*time.Second, 0)
+
+	tm.On("Connect").Return(nil).Once()
+	c.Connect()
+
+	tm.On("IsConnected").Return(true).Once()
+	sendReceive(tm, rdc, "601DA109060760857405080102BE10040E01000000065F1F04001C1B200100", "6129A109060760857405080102A203020100A305A103020100BE10040E0800065F1F04001C1B200080FA00")
+
+	err := c.Associate()
+	assert.NoError(t, err)
+
+	return c, tm, rdc
+}
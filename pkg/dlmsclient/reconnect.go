@@ -0,0 +1,198 @@
+package dlmsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func (c *client) SetReconnectPolicy(policy dlms.ReconnectPolicy) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.reconnectPolicy = policy
+}
+
+// expireAssociation is called when the association timeout is reached. Without a
+// reconnect policy the client is disconnected for good. Otherwise only the transport
+// is closed, so the next request can establish everything again.
+func (c *client) expireAssociation() {
+	c.mutex.Lock()
+
+	if c.reconnectPolicy.MaxRetries <= 0 {
+		c.mutex.Unlock()
+		c.Disconnect()
+
+		return
+	}
+
+	defer c.mutex.Unlock()
+
+	c.closeAssociation()
+	c.transport.Disconnect()
+}
+
+// restoreAssociation establishes again an association lost since the last request,
+// if the reconnect policy is enabled. Nothing has been sent yet, so it is safe for
+// any kind of request.
+func (c *client) restoreAssociation(ctx context.Context) error {
+	associationID, isAssociated, wasAssociated := c.associationState()
+	if c.reconnectPolicy.MaxRetries <= 0 || !wasAssociated || isAssociated {
+		return nil
+	}
+
+	return c.reassociate(ctx, associationID)
+}
+
+// withReconnect runs an idempotent operation applying the reconnect policy: a lost
+// association is established again before running it, and it is retried with
+// exponential backoff while it fails due to communication problems.
+func (c *client) withReconnect(ctx context.Context, op func() error) error {
+	policy := c.reconnectPolicy
+	if policy.MaxRetries <= 0 {
+		return op()
+	}
+
+	associationID, _, _ := c.associationState()
+
+	err := c.restoreAssociation(ctx)
+	if err == nil {
+		err = op()
+	}
+
+	backoff := policy.InitialBackoff
+	for retry := 1; retry <= policy.MaxRetries; retry++ {
+		if !c.isRecoverable(ctx, err) {
+			return err
+		}
+
+		if c.logger != nil {
+			c.logger.Printf("Request failed (%v), retry %d of %d in %v", err, retry, policy.MaxRetries, backoff)
+		}
+
+		if e := sleepContext(ctx, backoff); e != nil {
+			return e
+		}
+
+		backoff = nextBackoff(policy, backoff)
+
+		err = c.reassociate(ctx, associationID)
+		associationID, _, _ = c.associationState()
+
+		if err == nil {
+			err = op()
+		}
+	}
+
+	if !c.isRecoverable(ctx, err) {
+		return err
+	}
+
+	return dlms.NewErrorWithCause(dlms.ErrorReconnectFailed, fmt.Sprintf("giving up after %d retries: %v", policy.MaxRetries, err), err)
+}
+
+// isRecoverable returns true if the error may be solved establishing the
+// connection and the association again. Invocation counter failures are not, as
+// the association would be established again with the same counters.
+func (c *client) isRecoverable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	var dlmsError *dlms.Error
+	if !errors.As(err, &dlmsError) {
+		return false
+	}
+
+	switch dlmsError.Code() {
+	case dlms.ErrorCommunicationFailed:
+		return true
+	case dlms.ErrorInvalidState:
+		_, _, wasAssociated := c.associationState()
+		return wasAssociated
+	default:
+		return false
+	}
+}
+
+// reassociate reconnects the transport and establishes the association again,
+// unless another request already did it since associationID was taken. The
+// pipelined requests still in flight are waited for, as no other request can use
+// the association while it is established.
+func (c *client) reassociate(ctx context.Context, associationID uint64) error {
+	downgrade, err := c.mutex.Upgrade(ctx)
+	if err != nil {
+		return err
+	}
+	defer downgrade()
+
+	currentID, isAssociated, _ := c.associationState()
+	if currentID != associationID && isAssociated {
+		return nil
+	}
+
+	if c.logger != nil {
+		c.logger.Printf("Reconnecting and associating again")
+	}
+
+	c.closeAssociation()
+	c.transport.Disconnect()
+
+	c.stateMutex.Lock()
+	if c.settings.Ciphering.Level == dlms.SecurityLevelDedicatedKey {
+		err = c.settings.Ciphering.RenewDedicatedKey()
+	}
+	c.stateMutex.Unlock()
+
+	if err != nil {
+		return dlms.NewError(dlms.ErrorInvalidParameter, err.Error())
+	}
+
+	if err := c.connect(ctx); err != nil {
+		return err
+	}
+
+	return c.associate(ctx)
+}
+
+// associationState returns the identifier of the current association and whether
+// the client is and was associated. Pipelined requests may change it concurrently.
+func (c *client) associationState() (uint64, bool, bool) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	return c.associationID, c.isAssociated, c.wasAssociated
+}
+
+func nextBackoff(policy dlms.ReconnectPolicy, backoff time.Duration) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	backoff = time.Duration(float64(backoff) * multiplier)
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+
+	return backoff
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return dlms.ContextError(ctx.Err())
+	}
+}
@@ -0,0 +1,217 @@
+package dlmsclient_test
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+	"gitlab.com/circutor-library/gosem/pkg/dlms/mocks"
+	"gitlab.com/circutor-library/gosem/pkg/dlmsclient"
+)
+
+const (
+	aarqWithoutAuthentication = "601DA109060760857405080101BE10040E01000000065F1F040000181F0100"
+	aareWithoutAuthentication = "6129A109060760857405080101A203020100A305A103020100BE10040E0800065F1F040000101D00800007"
+)
+
+func TestClient_ReconnectAndRetryGet(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	c.SetReconnectPolicy(dlms.ReconnectPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond})
+
+	// The connection drops while sending the request
+	tm.On("Send", decodeHexString("C001C100080000010000FF0200")).Return(fmt.Errorf("broken pipe")).Once()
+	tm.On("IsConnected").Return(false).Once()
+
+	// The transport is connected and associated again, and the request retried
+	tm.On("Disconnect").Return(nil).Once()
+	tm.On("Connect").Return(nil).Once()
+	tm.On("IsConnected").Return(true).Once()
+	sendReceive(tm, rdc, aarqWithoutAuthentication, aareWithoutAuthentication)
+	sendReceive(tm, rdc, "C001C200080000010000FF0200", "C401C2000600000005")
+
+	var data uint32
+	err := c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), &data)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), data)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_ReconnectWaitsForPipelinedRequests(t *testing.T) {
+	settings, _ := dlms.NewSettingsWithoutAuthentication()
+	settings.MaxPendingRequests = 2
+
+	c, tm, rdc := associateWithSettings(t, settings)
+
+	c.SetReconnectPolicy(dlms.ReconnectPolicy{MaxRetries: 1, InitialBackoff: time.Millisecond})
+
+	attribute := dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2)
+
+	// The first request is in flight when the second one breaks the connection
+	sent := make(chan struct{})
+	tm.On("Send", decodeHexString("C001C100080000010000FF0200")).Run(func(_ mock.Arguments) {
+		close(sent)
+	}).Return(nil).Once()
+
+	var answered atomic.Bool
+	tm.On("Send", decodeHexString("C001C200080000010000FF0200")).Run(func(_ mock.Arguments) {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			answered.Store(true)
+			rdc <- decodeHexString("C401C1000600000001")
+		}()
+	}).Return(fmt.Errorf("broken pipe")).Once()
+	tm.On("IsConnected").Return(false).Once()
+
+	// The association is established again once the first request has its response
+	tm.On("Disconnect").Run(func(_ mock.Arguments) {
+		assert.True(t, answered.Load())
+	}).Return(nil).Once()
+	tm.On("Connect").Return(nil).Once()
+	tm.On("IsConnected").Return(true).Once()
+	sendReceive(tm, rdc, aarqWithoutAuthentication, aareWithoutAuthentication)
+	sendReceive(tm, rdc, "C001C300080000010000FF0200", "C401C3000600000002")
+
+	var first uint32
+	var errFirst error
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		errFirst = c.GetRequest(attribute, &first)
+	}()
+
+	<-sent
+
+	var second uint32
+	err := c.GetRequest(attribute, &second)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), second)
+
+	<-done
+	assert.NoError(t, errFirst)
+	assert.Equal(t, uint32(1), first)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_ReconnectGivesUp(t *testing.T) {
+	c, tm, _ := associate(t)
+
+	c.SetReconnectPolicy(dlms.ReconnectPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond})
+
+	tm.On("Send", decodeHexString("C001C100080000010000FF0200")).Return(fmt.Errorf("broken pipe")).Once()
+	tm.On("IsConnected").Return(false).Once()
+
+	tm.On("Disconnect").Return(nil).Times(3)
+	tm.On("Connect").Return(fmt.Errorf("connection refused")).Times(3)
+
+	var data uint32
+	err := c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), &data)
+
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorReconnectFailed, clientError.Code())
+	assert.ErrorContains(t, err, "connection refused")
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_ReconnectDoesNotRetrySet(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	c.SetReconnectPolicy(dlms.ReconnectPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond})
+
+	attribute := dlms.CreateAttributeDescriptor(3, "0-1:94.35.11.255", 2)
+
+	// A set is never retried, as it may have been executed
+	tm.On("Send", decodeHexString("C101C1000300015E230BFF02000600002710")).Return(fmt.Errorf("broken pipe")).Once()
+	tm.On("IsConnected").Return(false).Once()
+
+	err := c.SetRequest(attribute, uint32(10000))
+
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorCommunicationFailed, clientError.Code())
+
+	// But the association is established again before the next one
+	tm.On("Disconnect").Return(nil).Once()
+	tm.On("Connect").Return(nil).Once()
+	tm.On("IsConnected").Return(true).Once()
+	sendReceive(tm, rdc, aarqWithoutAuthentication, aareWithoutAuthentication)
+	sendReceive(tm, rdc, "C101C2000300015E230BFF02000600002710", "C501C200")
+
+	err = c.SetRequest(attribute, uint32(10000))
+	assert.NoError(t, err)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_ReconnectDisabled(t *testing.T) {
+	c, tm, _ := associate(t)
+
+	tm.On("Send", decodeHexString("C001C100080000010000FF0200")).Return(fmt.Errorf("broken pipe")).Once()
+	tm.On("IsConnected").Return(false).Once()
+
+	var data uint32
+	err := c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), &data)
+
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorCommunicationFailed, clientError.Code())
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_ReconnectDoesNotRetryInvocationCounter(t *testing.T) {
+	tm := mocks.NewTransportMock(t)
+
+	rdc := make(dlms.DataChannel, 10)
+	tm.On("SetReception", mock.Anything).Run(func(args mock.Arguments) {
+		rdc = args.Get(0).(dlms.DataChannel)
+	}).Once()
+
+	ciphering, _ := dlms.NewCiphering(
+		dlms.SecurityLevelDedicatedKey,
+		dlms.SecurityEncryption|dlms.SecurityAuthentication,
+		decodeHexString("4349520000000001"),
+		decodeHexString("00112233445566778899AABBCCDDEEFF"),
+		0x00000059,
+		decodeHexString("00112233445566778899AABBCCDDEEFF"),
+	)
+	ciphering.DedicatedKey = decodeHexString("5E168412318BA71848C99B2B2AB33294")
+
+	settings, _ := dlms.NewSettingsWithLowAuthenticationAndCiphering([]byte("JuS66BCZ"), ciphering)
+	settings.MaxPduRecvSize = 512
+
+	c := dlmsclient.New(settings, tm, 5*time.Second, 0)
+
+	tm.On("Connect").Return(nil).Once()
+	assert.NoError(t, c.Connect())
+
+	tm.On("IsConnected").Return(true)
+	sendReceive(tm, rdc, "6066A109060760857405080103A60A040843495200000000018A0207808B0760857405080201AC0A80084A7553363642435ABE3404322130300000005992D807DBCF8533E9AD675AE0948241FB8E6CF9AFA7006BAA134A473C9151B3362F56DC12F89E85DA97E176",
+		"6148A109060760857405080103A203020100A305A103020100A40A04084C475A2022604828BE230421281F300000005AE916783AF33B5317AD0E453A799A65F26AE97660CF8B14FEB7B0")
+	assert.NoError(t, c.Associate())
+
+	c.SetReconnectPolicy(dlms.ReconnectPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond})
+
+	sendReceive(tm, rdc, "D01E3000000001D3B903996D9508C5B6BCDEB025DD1800A5C92775FB55F317CF", "D4233000000001AA07A549F82E6B8EEA919659D91689BF995BE6F93C95A7208718A3B84EE4")
+	assert.NoError(t, c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), nil))
+
+	// A reply with a repeated invocation counter: associating again would not fix it
+	sendReceive(tm, rdc, "D01E3000000002CDA00B47BC0032D323FB29C26AF5D9AE57298DB997B8900EA7", "D4233000000001AA07A549F82E6B8EEA919659D91689BF995BE6F93C95A7208718A3B84EE4")
+	err := c.GetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), nil)
+
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorFailureInvocationCounter, clientError.Code())
+
+	tm.AssertExpectations(t)
+	tm.AssertNotCalled(t, "Disconnect")
+}
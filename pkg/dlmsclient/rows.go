@@ -0,0 +1,135 @@
+package dlmsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func (c *client) GetRequestRows(att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error] {
+	return c.GetRequestRowsContext(context.Background(), att, acc)
+}
+
+// GetRequestRowsContext yields the elements of an array attribute as they arrive.
+// The client is locked until the iteration ends; if it is stopped early, the rest
+// of the blocks are not asked for.
+func (c *client) GetRequestRowsContext(ctx context.Context, att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor) iter.Seq2[axdr.DlmsData, error] {
+	return func(yield func(axdr.DlmsData, error) bool) {
+		if err := c.mutex.Acquire(ctx); err != nil {
+			yield(axdr.DlmsData{}, err)
+			return
+		}
+		defer c.mutex.Release()
+
+		if err := c.getRequestRows(ctx, att, acc, yield); err != nil {
+			yield(axdr.DlmsData{}, err)
+		}
+	}
+}
+
+// getRequestRows sends the get request, retrying it according to the reconnect
+// policy, and yields the rows of the response. Once the first row has been
+// yielded, the errors are not retried.
+func (c *client) getRequestRows(ctx context.Context, att *dlms.AttributeDescriptor, acc *dlms.SelectiveAccessDescriptor, yield func(axdr.DlmsData, error) bool) error {
+	if att == nil {
+		return dlms.NewError(dlms.ErrorInvalidParameter, "attribute descriptor cannot be nil")
+	}
+
+	if err := c.checkAnswered(att.String()); err != nil {
+		return err
+	}
+
+	var invokeID uint8
+	var pdu dlms.CosemPDU
+
+	err := c.withReconnect(ctx, func() (err error) {
+		invokeID = c.nextInvokeID()
+		pdu, err = c.encodeSendReceiveAndDecode(ctx, dlms.CreateGetRequestNormal(invokeID, *att, acc))
+		return
+	})
+	if err != nil {
+		return err
+	}
+
+	switch resp := pdu.(type) {
+	case dlms.GetResponseNormal:
+		data, err := resp.Result.ValueAsData()
+		if err != nil {
+			access, _ := resp.Result.ValueAsAccess()
+			return dlms.NewError(dlms.ErrorGetRejected, fmt.Sprintf("get %s rejected: %s", att.String(), access.String()))
+		}
+
+		return yieldRows(data, att, yield)
+	case dlms.GetResponseWithDataBlock:
+		dec := axdr.NewStreamDecoder(c.newBlockReader(ctx, invokeID, resp, att.String()))
+		return streamRows(dec, att, yield)
+	default:
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected PDU response type: %T", att.String(), pdu))
+	}
+}
+
+// yieldRows yields the elements of an array already decoded.
+func yieldRows(data axdr.DlmsData, att *dlms.AttributeDescriptor, yield func(axdr.DlmsData, error) bool) error {
+	rows, err := data.Elements()
+	if err != nil || data.Tag == axdr.TagStructure {
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("%s is not an array", att.String()))
+	}
+
+	for _, row := range rows {
+		if !yield(*row, nil) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// streamRows decodes and yields the elements of an array while its blocks arrive.
+func streamRows(dec *axdr.StreamDecoder, att *dlms.AttributeDescriptor, yield func(axdr.DlmsData, error) bool) error {
+	token, err := dec.Token()
+	if err != nil {
+		return rowsError(att, err)
+	}
+
+	switch token.Kind {
+	case axdr.TokenArray:
+	case axdr.TokenValue:
+		// Compact arrays are decoded whole
+		return yieldRows(token.Data, att, yield)
+	default:
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("%s is not an array", att.String()))
+	}
+
+	for i := 0; i < token.Length; i++ {
+		row, err := dec.Decode()
+		if err != nil {
+			return rowsError(att, err)
+		}
+
+		if !yield(row, nil) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// rowsError returns the error of the blocks as is, or an invalid response error
+// for data that cannot be decoded.
+func rowsError(att *dlms.AttributeDescriptor, err error) error {
+	var dlmsError *dlms.Error
+	if errors.As(err, &dlmsError) {
+		return err
+	}
+
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error decoding %s data: %v", att.String(), err))
+}
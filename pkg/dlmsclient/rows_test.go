@@ -0,0 +1,109 @@
+package dlmsclient_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func TestClient_GetRequestRows(t *testing.T) {
+	c, tm, rdc := associate(t)
+	att := dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2)
+
+	// Each block is asked for once the rows of the previous one have been read
+	sendReceive(tm, rdc, "C001C100070100630100FF0200", "C402C10000000001000C010506000000010600000002")
+	sendReceive(tm, rdc, "C002C100000001", "C402C10000000002000A06000000030600000004")
+	sendReceive(tm, rdc, "C002C100000002", "C402C1010000000300050600000005")
+
+	calls := len(tm.Calls)
+
+	var rows []uint32
+	for row, err := range c.GetRequestRows(att, nil) {
+		require.NoError(t, err)
+
+		var value uint32
+		require.NoError(t, axdr.UnmarshalData(row, &value))
+		rows = append(rows, value)
+
+		if len(rows) == 2 {
+			assert.Len(t, tm.Calls, calls+1) // Only the first block
+		}
+	}
+	assert.Equal(t, []uint32{1, 2, 3, 4, 5}, rows)
+
+	// Stopped early, the rest of the blocks are not asked for
+	sendReceive(tm, rdc, "C001C200070100630100FF0200", "C402C20000000001000C010506000000010600000002")
+	count := 0
+	for range c.GetRequestRows(att, nil) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, count)
+
+	// Not in blocks
+	sendReceive(tm, rdc, "C001C300070100630100FF0200", "C401C300010211011102")
+	rows = nil
+	for row, err := range c.GetRequestRows(att, nil) {
+		require.NoError(t, err)
+		rows = append(rows, uint32(row.Value.(uint8)))
+	}
+	assert.Equal(t, []uint32{1, 2}, rows)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_GetRequestRowsFail(t *testing.T) {
+	c, tm, rdc := associate(t)
+	att := dlms.CreateAttributeDescriptor(7, "1-0:99.1.0.255", 2)
+
+	collect := func() (int, error) {
+		count := 0
+		for _, err := range c.GetRequestRows(att, nil) {
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+
+		return count, nil
+	}
+
+	// Block number mismatch after the first rows
+	sendReceive(tm, rdc, "C001C100070100630100FF0200", "C402C10000000001000C010506000000010600000002")
+	sendReceive(tm, rdc, "C002C100000001", "C402C1010000000300050600000005")
+	count, err := collect()
+	assert.Equal(t, 2, count)
+	assertErrorCode(t, err, dlms.ErrorInvalidResponse)
+
+	// Data ends before all the rows
+	sendReceive(tm, rdc, "C001C200070100630100FF0200", "C402C20100000001000C010506000000010600000002")
+	count, err = collect()
+	assert.Equal(t, 2, count)
+	assertErrorCode(t, err, dlms.ErrorInvalidResponse)
+
+	// Not an array
+	sendReceive(tm, rdc, "C001C300070100630100FF0200", "C401C3001101")
+	_, err = collect()
+	assertErrorCode(t, err, dlms.ErrorInvalidResponse)
+
+	// Rejected
+	sendReceive(tm, rdc, "C001C400070100630100FF0200", "C401C4010B")
+	_, err = collect()
+	assertErrorCode(t, err, dlms.ErrorGetRejected)
+
+	tm.AssertExpectations(t)
+}
+
+func assertErrorCode(t *testing.T, err error, code dlms.ErrorCode) {
+	t.Helper()
+
+	var clientError *dlms.Error
+	if assert.ErrorAs(t, err, &clientError) {
+		assert.Equal(t, code, clientError.Code())
+	}
+}
@@ -0,0 +1,108 @@
+package dlmsclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+// scalerUnitAttribute returns the index of the scaler_unit attribute of the
+// classes that have one.
+func scalerUnitAttribute(classID uint16) (int8, bool) {
+	switch classID {
+	case 3, 4: // Register, Extended Register
+		return 3, true
+	case 5: // Demand Register
+		return 4, true
+	default:
+		return 0, false
+	}
+}
+
+// getScalerUnit returns the scaler_unit of the object of an attribute. It is read
+// only once, as it does not change, and kept until the address of the client changes.
+func (c *client) getScalerUnit(ctx context.Context, att *dlms.AttributeDescriptor) (su dlms.ScalerUnit, err error) {
+	index, ok := scalerUnitAttribute(att.ClassID)
+	if !ok {
+		err = dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("class %d of %s has no scaler_unit attribute", att.ClassID, att.String()))
+		return
+	}
+
+	key := fmt.Sprintf("%d/%s", att.ClassID, att.InstanceID.String())
+
+	c.scalersMutex.Lock()
+	su, ok = c.scalers[key]
+	c.scalersMutex.Unlock()
+
+	if ok {
+		return
+	}
+
+	sua := dlms.CreateAttributeDescriptor(att.ClassID, att.InstanceID.String(), index)
+	if err = c.getRequestWithUnmarshal(ctx, sua, nil, &su); err != nil {
+		return
+	}
+
+	c.scalersMutex.Lock()
+	c.scalers[key] = su
+	c.scalersMutex.Unlock()
+
+	return
+}
+
+func (c *client) clearScalers() {
+	c.scalersMutex.Lock()
+	defer c.scalersMutex.Unlock()
+
+	c.scalers = make(map[string]dlms.ScalerUnit)
+}
+
+// getScaledRequest reads an attribute and stores its physical value in data, which
+// must point to a float or a dlms.PhysicalValue (or a pointer to them). Pointers
+// are only assigned once the value has been read and scaled.
+func (c *client) getScaledRequest(ctx context.Context, att *dlms.AttributeDescriptor, data interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(data))
+
+	target := rv.Type()
+	if rv.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+
+	isPhysical := target == reflect.TypeOf(dlms.PhysicalValue{})
+	if !isPhysical && target.Kind() != reflect.Float32 && target.Kind() != reflect.Float64 {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("scaled %s must be stored in a float or dlms.PhysicalValue, not %s", att.String(), target))
+	}
+
+	su, err := c.getScalerUnit(ctx, att)
+	if err != nil {
+		return err
+	}
+
+	var raw axdr.DlmsData
+	if err := c.getRequestWithUnmarshal(ctx, att, nil, &raw); err != nil {
+		return err
+	}
+
+	value, err := su.Apply(raw.Value)
+	if err != nil {
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("error scaling %s data: %v", att.String(), err))
+	}
+
+	out := reflect.New(target)
+	if isPhysical {
+		out.Elem().Set(reflect.ValueOf(value))
+	} else {
+		out.Elem().SetFloat(value.Value)
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		rv.Set(out)
+	} else {
+		rv.Set(out.Elem())
+	}
+
+	return nil
+}
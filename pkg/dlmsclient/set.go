@@ -1,6 +1,7 @@
 package dlmsclient
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -10,16 +11,36 @@ import (
 )
 
 func (c *client) SetRequest(att *dlms.AttributeDescriptor, data interface{}) (err error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	return c.SetRequestContext(context.Background(), att, data)
+}
+
+func (c *client) SetRequestContext(ctx context.Context, att *dlms.AttributeDescriptor, data interface{}) (err error) {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
+
+	if err := c.restoreAssociation(ctx); err != nil {
+		return err
+	}
 
-	return c.setRequest(att, data)
+	return c.setRequest(ctx, att, data)
 }
 
-//nolint:nestif
 func (c *client) SetRequestWithStructOfElements(data interface{}, continueOnSetRejected bool) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+	return c.SetRequestWithStructOfElementsContext(context.Background(), data, continueOnSetRejected)
+}
+
+//nolint:nestif
+func (c *client) SetRequestWithStructOfElementsContext(ctx context.Context, data interface{}, continueOnSetRejected bool) error {
+	if err := c.mutex.Acquire(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Release()
+
+	if err := c.restoreAssociation(ctx); err != nil {
+		return err
+	}
 
 	v := eindirect(reflect.ValueOf(data))
 
@@ -32,11 +53,15 @@ func (c *client) SetRequestWithStructOfElements(data interface{}, continueOnSetR
 	isSomethingFailed := false
 
 	for i := 0; i < v.NumField(); i++ {
-		ad, err := c.getAttributeDescriptor(v.Type().Field(i))
+		ad, scaled, err := c.getAttributeDescriptor(v.Type().Field(i))
 		if err != nil {
 			return err
 		}
 
+		if scaled {
+			return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("scaled field %s cannot be set", v.Type().Field(i).Name))
+		}
+
 		if ad == nil {
 			continue
 		}
@@ -48,7 +73,7 @@ func (c *client) SetRequestWithStructOfElements(data interface{}, continueOnSetR
 			}
 		}
 
-		err = c.setRequest(ad, v.Field(i).Interface())
+		err = c.setRequest(ctx, ad, v.Field(i).Interface())
 		if err != nil {
 			// If a set is rejected, we will continue anyway
 			var dlmsError *dlms.Error
@@ -75,14 +100,14 @@ func (c *client) SetRequestWithStructOfElements(data interface{}, continueOnSetR
 	return errSet
 }
 
-func (c *client) setRequest(att *dlms.AttributeDescriptor, data interface{}) (err error) {
+func (c *client) setRequest(ctx context.Context, att *dlms.AttributeDescriptor, data interface{}) (err error) {
 	if att == nil {
 		return dlms.NewError(dlms.ErrorInvalidParameter, "attribute descriptor must be non-nil")
 	}
 
 	dt, ok := data.(*axdr.DlmsData)
 	if !ok {
-		dt, err = axdr.MarshalData(data)
+		dt, err = c.marshalData(data)
 		if err != nil {
 			return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("error marshaling %s data: %v", att.String(), err))
 		}
@@ -99,13 +124,17 @@ func (c *client) setRequest(att *dlms.AttributeDescriptor, data interface{}) (er
 	}
 
 	if len(out) < (c.settings.MaxPduSendSize - lenHeader) {
-		req := dlms.CreateSetRequestNormal(unicastInvokeID, *att, nil, *dt)
+		req := dlms.CreateSetRequestNormal(c.nextInvokeID(), *att, nil, *dt)
 
-		pdu, err := c.encodeSendReceiveAndDecode(req)
+		pdu, err := c.encodeSendReceiveAndDecode(ctx, req)
 		if err != nil {
 			return err
 		}
 
+		if pdu == nil && c.isUnanswered() {
+			return nil
+		}
+
 		resp, ok := pdu.(dlms.SetResponseNormal)
 		if !ok {
 			return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected PDU response type: %T", att.String(), pdu))
@@ -115,13 +144,18 @@ func (c *client) setRequest(att *dlms.AttributeDescriptor, data interface{}) (er
 			return dlms.NewError(dlms.ErrorSetRejected, fmt.Sprintf("set %s rejected: %s", att.String(), resp.Result.String()))
 		}
 	} else {
-		return c.setRequestWithDataBlock(att, out)
+		// Each block waits for the response to the previous one
+		if c.isUnanswered() {
+			return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("%s data is too long to be set without a response from the server", att.String()))
+		}
+
+		return c.setRequestWithDataBlock(ctx, c.nextInvokeID(), att, out)
 	}
 
 	return
 }
 
-func (c *client) setRequestWithDataBlock(att *dlms.AttributeDescriptor, out []byte) error {
+func (c *client) setRequestWithDataBlock(ctx context.Context, invokeID uint8, att *dlms.AttributeDescriptor, out []byte) error {
 	isLastBlock := false
 	isFirstBlock := true
 	blockNumber := uint32(1)
@@ -146,12 +180,12 @@ func (c *client) setRequestWithDataBlock(att *dlms.AttributeDescriptor, out []by
 		var req dlms.CosemPDU
 
 		if isFirstBlock {
-			req = dlms.CreateSetRequestWithFirstDataBlock(unicastInvokeID, *att, nil, *db)
+			req = dlms.CreateSetRequestWithFirstDataBlock(invokeID, *att, nil, *db)
 		} else {
-			req = dlms.CreateSetRequestWithDataBlock(unicastInvokeID, *db)
+			req = dlms.CreateSetRequestWithDataBlock(invokeID, *db)
 		}
 
-		pdu, err := c.encodeSendReceiveAndDecode(req)
+		pdu, err := c.encodeSendReceiveAndDecode(ctx, req)
 		if err != nil {
 			return err
 		}
@@ -1,8 +1,10 @@
 package dlmsclient_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gitlab.com/circutor-library/gosem/pkg/axdr"
@@ -21,6 +23,52 @@ func TestClient_SetRequest(t *testing.T) {
 	tm.AssertExpectations(t)
 }
 
+func TestClient_SetRequestUnconfirmed(t *testing.T) {
+	c, tm, _ := associate(t)
+
+	settings := c.GetSettings()
+	settings.ServiceClass = dlms.ServiceClassUnconfirmed
+	c.SetSettings(settings)
+
+	var data uint32 = 10000
+
+	tm.On("Send", decodeHexString("C10181000300015E230BFF02000600002710")).Return(nil).Once()
+
+	err := c.SetRequest(dlms.CreateAttributeDescriptor(3, "0-1:94.35.11.255", 2), data)
+	assert.NoError(t, err)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_SetRequestWithTimeZone(t *testing.T) {
+	settings, _ := dlms.NewSettingsWithoutAuthentication()
+	tz := axdr.TimeZoneIgnored
+	settings.TimeZone = &tz
+
+	c, tm, rdc := associateWithSettings(t, settings)
+
+	sendReceive(tm, rdc, "C101C100080000010000FF0200090C07D00106040F003000800000", "C501C100")
+	err := c.SetRequest(dlms.CreateAttributeDescriptor(8, "0-0:1.0.0.255", 2), time.Date(2000, time.January, 6, 15, 0, 48, 0, time.UTC))
+	assert.NoError(t, err)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_SetRequestWithJSON(t *testing.T) {
+	c, tm, rdc := associate(t)
+
+	var data axdr.DlmsData
+	err := json.Unmarshal([]byte(`{"type":"structure","value":[`+
+		`{"type":"double_long_unsigned","value":10000},{"type":"enum","value":30}]}`), &data)
+	assert.NoError(t, err)
+
+	sendReceive(tm, rdc, "C101C1000300015E230BFF020002020600002710161E", "C501C100")
+	err = c.SetRequest(dlms.CreateAttributeDescriptor(3, "0-1:94.35.11.255", 2), data)
+	assert.NoError(t, err)
+
+	tm.AssertExpectations(t)
+}
+
 func TestClient_SetRequestFail(t *testing.T) {
 	c, tm, rdc := associate(t)
 
@@ -35,19 +83,19 @@ func TestClient_SetRequestFail(t *testing.T) {
 	assert.Equal(t, dlms.ErrorSetRejected, clientError.Code())
 
 	// Unexpected response
-	sendReceive(tm, rdc, "C101C1000300015E230BFF02000600002710", "0E010203")
+	sendReceive(tm, rdc, "C101C2000300015E230BFF02000600002710", "0E010203")
 	err = c.SetRequest(demandAttributeDescriptor, data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Invalid response
-	sendReceive(tm, rdc, "C101C1000300015E230BFF02000600002710", "AE12")
+	sendReceive(tm, rdc, "C101C3000300015E230BFF02000600002710", "AE12")
 	err = c.SetRequest(demandAttributeDescriptor, data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// Send failed
-	tm.On("Send", decodeHexString("C101C1000300015E230BFF02000600002710")).Return(fmt.Errorf("error")).Once()
+	tm.On("Send", decodeHexString("C101C4000300015E230BFF02000600002710")).Return(fmt.Errorf("error")).Once()
 	tm.On("IsConnected").Return(false).Once()
 
 	err = c.SetRequest(demandAttributeDescriptor, data)
@@ -93,7 +141,7 @@ func TestClient_SetRequestWithStructOfElements(t *testing.T) {
 	var v interface{} = &data
 
 	sendReceive(tm, rdc, "C101C1000300015E230BFF02000600002710", "C501C100")
-	sendReceive(tm, rdc, "C101C1000101015E2268FF0200123039", "C501C100")
+	sendReceive(tm, rdc, "C101C2000101015E2268FF0200123039", "C501C200")
 	err := c.SetRequestWithStructOfElements(&v, true)
 	assert.NoError(t, err)
 
@@ -118,7 +166,7 @@ func TestClient_SetRequestWithStructOfElementsWithFail(t *testing.T) {
 	var v interface{} = &data
 
 	sendReceive(tm, rdc, "C101C1000300015E230BFF0200121A85", "C501C100")
-	sendReceive(tm, rdc, "C101C1000101015E2268FF0200123039", "C501C103")
+	sendReceive(tm, rdc, "C101C2000101015E2268FF0200123039", "C501C203")
 	err := c.SetRequestWithStructOfElements(&v, true)
 	var clientError *dlms.Error
 	assert.ErrorAs(t, err, &clientError)
@@ -126,23 +174,23 @@ func TestClient_SetRequestWithStructOfElementsWithFail(t *testing.T) {
 
 	// If first element fails, then we expect an ErrorSetPartial
 
-	sendReceive(tm, rdc, "C101C1000300015E230BFF0200121A85", "C501C103")
-	sendReceive(tm, rdc, "C101C1000101015E2268FF0200123039", "C501C100")
+	sendReceive(tm, rdc, "C101C3000300015E230BFF0200121A85", "C501C303")
+	sendReceive(tm, rdc, "C101C4000101015E2268FF0200123039", "C501C400")
 	err = c.SetRequestWithStructOfElements(&v, true)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorSetPartial, clientError.Code())
 
 	// If both fails, then we expect an ErrorSetRejected
 
-	sendReceive(tm, rdc, "C101C1000300015E230BFF0200121A85", "C501C103")
-	sendReceive(tm, rdc, "C101C1000101015E2268FF0200123039", "C501C103")
+	sendReceive(tm, rdc, "C101C5000300015E230BFF0200121A85", "C501C503")
+	sendReceive(tm, rdc, "C101C6000101015E2268FF0200123039", "C501C603")
 	err = c.SetRequestWithStructOfElements(&v, true)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorSetRejected, clientError.Code())
 
 	// If first element fails, don't continue and we expect an ErrorSetRejected
 
-	sendReceive(tm, rdc, "C101C1000300015E230BFF0200121A85", "C501C103")
+	sendReceive(tm, rdc, "C101C7000300015E230BFF0200121A85", "C501C703")
 	err = c.SetRequestWithStructOfElements(&v, false)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorSetRejected, clientError.Code())
@@ -222,31 +270,31 @@ func TestClient_SetRequestWithDataBlockFail(t *testing.T) {
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// If set failed, then we expect an ErrorSetRejected
-	sendReceive(tm, rdc, "C102C1000300015E230BFF020000000000016B020A092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708", "C502C100000001")
-	sendReceive(tm, rdc, "C103C100000000027509000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080915000000000000007B150000", "C502C100000002")
-	sendReceive(tm, rdc, "C103C10100000003210000000000EA1500000000000001591500000000000001C8150000000000000237", "C503C10200000003")
+	sendReceive(tm, rdc, "C102C2000300015E230BFF020000000000016B020A092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708", "C502C200000001")
+	sendReceive(tm, rdc, "C103C200000000027509000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080915000000000000007B150000", "C502C200000002")
+	sendReceive(tm, rdc, "C103C20100000003210000000000EA1500000000000001591500000000000001C8150000000000000237", "C503C20200000003")
 	err = c.SetRequest(dlms.CreateAttributeDescriptor(3, "0-1:94.35.11.255", 2), data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorSetRejected, clientError.Code())
 
 	// If block number doesn't match in last block, then we expect an ErrorInvalidResponse
-	sendReceive(tm, rdc, "C102C1000300015E230BFF020000000000016B020A092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708", "C502C100000001")
-	sendReceive(tm, rdc, "C103C100000000027509000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080915000000000000007B150000", "C502C100000002")
-	sendReceive(tm, rdc, "C103C10100000003210000000000EA1500000000000001591500000000000001C8150000000000000237", "C503C10000000004")
+	sendReceive(tm, rdc, "C102C3000300015E230BFF020000000000016B020A092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708", "C502C300000001")
+	sendReceive(tm, rdc, "C103C300000000027509000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080915000000000000007B150000", "C502C300000002")
+	sendReceive(tm, rdc, "C103C30100000003210000000000EA1500000000000001591500000000000001C8150000000000000237", "C503C30000000004")
 	err = c.SetRequest(dlms.CreateAttributeDescriptor(3, "0-1:94.35.11.255", 2), data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// If we receive an unexpected response, then we expect an ErrorInvalidResponse
-	sendReceive(tm, rdc, "C102C1000300015E230BFF020000000000016B020A092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708", "0E010203")
+	sendReceive(tm, rdc, "C102C4000300015E230BFF020000000000016B020A092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708", "0E010203")
 	err = c.SetRequest(dlms.CreateAttributeDescriptor(3, "0-1:94.35.11.255", 2), data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
 
 	// If we receive an unexpected response in last block, then we expect an ErrorInvalidResponse
-	sendReceive(tm, rdc, "C102C1000300015E230BFF020000000000016B020A092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708", "C502C100000001")
-	sendReceive(tm, rdc, "C103C100000000027509000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080915000000000000007B150000", "C502C100000002")
-	sendReceive(tm, rdc, "C103C10100000003210000000000EA1500000000000001591500000000000001C8150000000000000237", "0E010203")
+	sendReceive(tm, rdc, "C102C5000300015E230BFF020000000000016B020A092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708090001020304050607080900010203040506070809092800010203040506070809000102030405060708", "C502C500000001")
+	sendReceive(tm, rdc, "C103C500000000027509000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080909280001020304050607080900010203040506070809000102030405060708090001020304050607080915000000000000007B150000", "C502C500000002")
+	sendReceive(tm, rdc, "C103C50100000003210000000000EA1500000000000001591500000000000001C8150000000000000237", "0E010203")
 	err = c.SetRequest(dlms.CreateAttributeDescriptor(3, "0-1:94.35.11.255", 2), data)
 	assert.ErrorAs(t, err, &clientError)
 	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
@@ -0,0 +1,174 @@
+package dlmsclient
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func (c *client) WriteRequest(sn uint16, data interface{}) (err error) {
+	return c.WriteRequestContext(context.Background(), sn, data)
+}
+
+func (c *client) WriteRequestContext(ctx context.Context, sn uint16, data interface{}) (err error) {
+	if err := c.mutex.LockContext(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Unlock()
+
+	if err := c.restoreAssociation(ctx); err != nil {
+		return err
+	}
+
+	return c.writeRequest(ctx, dlms.CreateVariableNameAccess(sn), data)
+}
+
+func (c *client) UnconfirmedWriteRequest(sn uint16, data interface{}) (err error) {
+	return c.UnconfirmedWriteRequestContext(context.Background(), sn, data)
+}
+
+func (c *client) UnconfirmedWriteRequestContext(ctx context.Context, sn uint16, data interface{}) (err error) {
+	if err := c.mutex.LockContext(ctx); err != nil {
+		return err
+	}
+	defer c.mutex.Unlock()
+
+	if err := c.restoreAssociation(ctx); err != nil {
+		return err
+	}
+
+	va := dlms.CreateVariableNameAccess(sn)
+
+	dt, err := c.marshalWriteData(va, data)
+	if err != nil {
+		return err
+	}
+
+	req := dlms.CreateUnconfirmedWriteRequest([]dlms.VariableAccessSpecification{*va}, []axdr.DlmsData{*dt})
+
+	if err := ctx.Err(); err != nil {
+		return dlms.ContextError(err)
+	}
+
+	return c.encodeAndSend(req)
+}
+
+func (c *client) marshalWriteData(va *dlms.VariableAccessSpecification, data interface{}) (*axdr.DlmsData, error) {
+	dt, ok := data.(*axdr.DlmsData)
+	if !ok {
+		var err error
+		dt, err = c.marshalData(data)
+		if err != nil {
+			return nil, dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("error marshaling %s data: %v", va.String(), err))
+		}
+	}
+
+	return dt, nil
+}
+
+func (c *client) writeRequest(ctx context.Context, va *dlms.VariableAccessSpecification, data interface{}) (err error) {
+	dt, err := c.marshalWriteData(va, data)
+	if err != nil {
+		return err
+	}
+
+	req := dlms.CreateWriteRequest([]dlms.VariableAccessSpecification{*va}, []axdr.DlmsData{*dt})
+
+	out, err := req.EncodeContent()
+	if err != nil {
+		return dlms.NewError(dlms.ErrorInvalidParameter, fmt.Sprintf("error encoding %s data: %v", va.String(), err))
+	}
+
+	lenHeader := 1
+	if c.settings.Ciphering.Level != dlms.SecurityLevelNone {
+		lenHeader += 21
+	}
+
+	if len(out) >= (c.settings.MaxPduSendSize - lenHeader) {
+		return c.writeRequestWithDataBlock(ctx, va, out)
+	}
+
+	pdu, err := c.encodeSendReceiveAndDecode(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return checkWriteResponse(va, pdu, nil)
+}
+
+func (c *client) writeRequestWithDataBlock(ctx context.Context, va *dlms.VariableAccessSpecification, out []byte) error {
+	isLastBlock := false
+	blockNumber := uint16(1)
+
+	for {
+		lenHeader := 13
+		if c.settings.Ciphering.Level != dlms.SecurityLevelNone {
+			lenHeader += 21
+		}
+
+		blockSize := c.settings.MaxPduSendSize - lenHeader
+		if blockSize >= len(out) {
+			blockSize = len(out)
+			isLastBlock = true
+		}
+
+		raw := axdr.CreateAxdrOctetString(hex.EncodeToString(out[:blockSize]))
+		req := dlms.CreateWriteRequest([]dlms.VariableAccessSpecification{*dlms.CreateWriteDataBlockAccess(isLastBlock, blockNumber)}, []axdr.DlmsData{*raw})
+
+		pdu, err := c.encodeSendReceiveAndDecode(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if isLastBlock {
+			return checkWriteResponse(va, pdu, nil)
+		}
+
+		err = checkWriteResponse(va, pdu, &blockNumber)
+		if err != nil {
+			return err
+		}
+
+		out = out[blockSize:]
+		blockNumber++
+	}
+}
+
+// checkWriteResponse verifies the WRITE-response. If blockNumber is not nil, an
+// acknowledge of that block is expected instead of the final result.
+func checkWriteResponse(va *dlms.VariableAccessSpecification, pdu dlms.CosemPDU, blockNumber *uint16) error {
+	resp, ok := pdu.(dlms.WriteResponse)
+	if !ok {
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected PDU response type: %T", va.String(), pdu))
+	}
+
+	if len(resp.Results) != 1 {
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s expected 1 result, got %d", va.String(), len(resp.Results)))
+	}
+
+	result := resp.Results[0]
+	if result.Tag == dlms.TagWriteResultDataAccessError {
+		return dlms.NewError(dlms.ErrorWriteRejected, fmt.Sprintf("write %s rejected: %s", va.String(), result.AccessError.String()))
+	}
+
+	if blockNumber != nil {
+		if result.Tag != dlms.TagWriteResultBlockNumber {
+			return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s expected block number %d acknowledge, got result %d", va.String(), *blockNumber, result.Tag))
+		}
+
+		if result.BlockNumber != *blockNumber {
+			return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected block number %d (expected %d)", va.String(), result.BlockNumber, *blockNumber))
+		}
+
+		return nil
+	}
+
+	if result.Tag != dlms.TagWriteResultSuccess {
+		return dlms.NewError(dlms.ErrorInvalidResponse, fmt.Sprintf("in %s unexpected write result: %d", va.String(), result.Tag))
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package dlmsclient_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/circutor-library/gosem/pkg/axdr"
+	"gitlab.com/circutor-library/gosem/pkg/dlms"
+)
+
+func TestClient_WriteRequest(t *testing.T) {
+	c, tm, rdc := associateShortName(t)
+
+	var data uint16 = 5
+
+	sendReceive(tm, rdc, "060102FA0801120005", "0D0100")
+	err := c.WriteRequest(0xFA08, data)
+	assert.NoError(t, err)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_WriteRequestWithBlock(t *testing.T) {
+	c, tm, rdc := associateShortName(t)
+
+	data := axdr.CreateAxdrVisibleString(strings.Repeat("A", 200))
+	value, _ := data.Encode()
+	content := append(decodeHexString("0102FA0801"), value...)
+
+	// Max PDU size is 128, so blocks are 115 bytes long
+	sendReceive(tm, rdc, "06010700000101"+"0973"+hex.EncodeToString(content[:115]), "0D01020001")
+	sendReceive(tm, rdc, "06010701000201"+"095D"+hex.EncodeToString(content[115:]), "0D0100")
+	err := c.WriteRequest(0xFA08, data)
+	assert.NoError(t, err)
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_WriteRequestFail(t *testing.T) {
+	c, tm, rdc := associateShortName(t)
+
+	data := axdr.CreateAxdrLongUnsigned(5)
+
+	// Write rejected
+	sendReceive(tm, rdc, "060102FA0801120005", "0D010103")
+	err := c.WriteRequest(0xFA08, data)
+	var clientError *dlms.Error
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorWriteRejected, clientError.Code())
+
+	// Unexpected response
+	sendReceive(tm, rdc, "060102FA0801120005", "0C010012003C")
+	err = c.WriteRequest(0xFA08, data)
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorInvalidResponse, clientError.Code())
+
+	// Send failed
+	tm.On("Send", decodeHexString("060102FA0801120005")).Return(fmt.Errorf("error")).Once()
+	tm.On("IsConnected").Return(false).Once()
+	err = c.WriteRequest(0xFA08, data)
+	assert.ErrorAs(t, err, &clientError)
+	assert.Equal(t, dlms.ErrorCommunicationFailed, clientError.Code())
+
+	tm.AssertExpectations(t)
+}
+
+func TestClient_UnconfirmedWriteRequest(t *testing.T) {
+	c, tm, _ := associateShortName(t)
+
+	tm.On("Send", decodeHexString("160102FA0801120005")).Return(nil).Once()
+	err := c.UnconfirmedWriteRequest(0xFA08, uint16(5))
+	assert.NoError(t, err)
+
+	tm.AssertExpectations(t)
+}
@@ -1,6 +1,7 @@
 package hdlc
 
 import (
+	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
@@ -107,10 +108,21 @@ func (h *hdlc) Close() {
 }
 
 func (h *hdlc) Connect() error {
+	return h.ConnectContext(context.Background())
+}
+
+func (h *hdlc) ConnectContext(ctx context.Context) error {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
-	if err := h.transport.Connect(); err != nil {
+	var err error
+	if twc, ok := h.transport.(dlms.TransportWithContext); ok {
+		err = twc.ConnectContext(ctx)
+	} else {
+		err = h.transport.Connect()
+	}
+
+	if err != nil {
 		return err
 	}
 
@@ -120,8 +132,9 @@ func (h *hdlc) Connect() error {
 
 	frameToSend := h.createFrame(controlSNRM, nil)
 
-	rf, err := h.sendReceive(frameToSend)
+	rf, err := h.sendReceive(ctx, frameToSend)
 	if err != nil {
+		h.transport.Disconnect()
 		return fmt.Errorf("send error: %w", err)
 	}
 
@@ -146,7 +159,7 @@ func (h *hdlc) Disconnect() error {
 
 	frameToSend := h.createFrame(controlDISC, nil)
 
-	rf, err := h.sendReceive(frameToSend)
+	rf, err := h.sendReceive(context.Background(), frameToSend)
 	if err != nil {
 		return fmt.Errorf("send error: %w", err)
 	}
@@ -222,7 +235,7 @@ func (h *hdlc) Send(src []byte) error {
 			frameToSend = h.createFrame(control, nil)
 		}
 
-		rf, err := h.sendReceive(frameToSend)
+		rf, err := h.sendReceive(context.Background(), frameToSend)
 
 		switch {
 		case err != nil || rf == nil:
@@ -500,7 +513,19 @@ func (h *hdlc) createFrame(control uint8, data []byte) []byte {
 	return frame
 }
 
-func (h *hdlc) sendReceive(src []byte) (*ReceivedFrame, error) {
+// sendReceive sends a frame and waits for the reply until the timeout expires or
+// the context is done.
+func (h *hdlc) sendReceive(ctx context.Context, src []byte) (*ReceivedFrame, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Discard the late reply to a previous frame that was given up
+	select {
+	case <-h.fc:
+	default:
+	}
+
 	if h.logger != nil {
 		h.logger.Printf("TX: %s", encodeHexString(src))
 	}
@@ -519,6 +544,8 @@ func (h *hdlc) sendReceive(src []byte) (*ReceivedFrame, error) {
 		return data, nil
 	case <-timeout.C:
 		return nil, fmt.Errorf("timeout waiting for response")
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 }
 
@@ -0,0 +1,27 @@
+package hdlc
+
+import (
+	"testing"
+)
+
+func FuzzParseFrame(f *testing.F) {
+	h := &hdlc{
+		upperAddress:  1,
+		lowerAddress:  16,
+		clientAddress: 1,
+		fcsTable:      generateFCSTable(),
+	}
+
+	f.Add(h.createFrame(controlUA, []byte{0x81, 0x80, 0x14, 0x05, 0x02, 0x02, 0x00, 0x06, 0x02, 0x02, 0x00}))
+	f.Add(h.createFrame(controlI, []byte{0xE6, 0xE7, 0x00, 0xC4, 0x01, 0xC1, 0x00, 0x12, 0x00, 0x01}))
+	f.Add(h.createFrame(controlRR, nil))
+
+	f.Fuzz(func(t *testing.T, src []byte) {
+		frame := src
+		if rf := h.searchFrame(&frame); rf != nil && len(rf.Data) > len(src) {
+			t.Errorf("frame data longer than the received bytes")
+		}
+
+		_, _ = h.parseFrame(src)
+	})
+}
@@ -1,6 +1,7 @@
 package hdlc_test
 
 import (
+	"context"
 	"encoding/hex"
 	"testing"
 	"time"
@@ -45,6 +46,28 @@ func TestHDLC_Connect(t *testing.T) {
 	transportMock.AssertExpectations(t)
 }
 
+func TestHDLC_ConnectContextCanceled(t *testing.T) {
+	transportMock := mocks.NewTransportMock(t)
+
+	transportMock.On("SetReception", mock.Anything).Once()
+	w := hdlc.New(transportMock, time.Minute, interOctetTimeout, 16, 73, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The meter never answers the SNRM: canceling must not wait for the reply timeout
+	transportMock.On("Connect").Return(nil).Once()
+	transportMock.On("Send", decodeHexString("7EA00802219393DBD87E")).Run(func(_ mock.Arguments) {
+		cancel()
+	}).Return(nil).Once()
+	transportMock.On("Disconnect").Return(nil).Once()
+
+	err := w.(dlms.TransportWithContext).ConnectContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	transportMock.AssertExpectations(t)
+}
+
 func TestHDLC_ConnectFail(t *testing.T) {
 	transportMock := mocks.NewTransportMock(t)
 
@@ -1,6 +1,7 @@
 package tcp
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -58,13 +59,18 @@ func (t *tcp) Close() {
 }
 
 func (t *tcp) Connect() error {
+	return t.ConnectContext(context.Background())
+}
+
+func (t *tcp) ConnectContext(ctx context.Context) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
 	if !t.isConnected {
 		address := net.JoinHostPort(t.host, strconv.Itoa(t.port))
 
-		conn, err := net.DialTimeout("tcp", address, t.timeout)
+		dialer := net.Dialer{Timeout: t.timeout}
+		conn, err := dialer.DialContext(ctx, "tcp", address)
 		if err != nil {
 			if t.logger != nil {
 				t.logger.Printf("Connect to %s failed: %v", address, err)
@@ -1,6 +1,7 @@
 package wrapper
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"log"
@@ -56,6 +57,15 @@ func (w *wrapper) Connect() error {
 	return nil
 }
 
+func (w *wrapper) ConnectContext(ctx context.Context) error {
+	twc, ok := w.transport.(dlms.TransportWithContext)
+	if !ok {
+		return w.Connect()
+	}
+
+	return twc.ConnectContext(ctx)
+}
+
 func (w *wrapper) manager() {
 	for {
 		data, ok := <-w.tc